@@ -2,13 +2,79 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
 	"github.com/luffy050596/go-blackjack/internal/interfaces/cli"
 )
 
 func main() {
+	// 子命令（如 bankroll）直接运行后退出
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err := cli.RunCommand(os.Args[1], os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	rules := entities.DefaultRules()
+
+	ui := flag.String("ui", cli.RendererClassic, "界面模式: classic(滚动文本) 或 tui(全屏牌桌)")
+	sideBets := flag.Bool("side-bets", false, "下注阶段提供完美对子、21+3与幸运女士边注")
+	fairHistory := flag.String("fair-history", "", "可验证公平模式下公开的牌靴追加写入的历史文件")
+	strategy := flag.String("strategy", services.StrategySimulation.String(), "推荐操作来源: simulation(模拟胜率)、basic(基本策略) 或 composition(组成相关最优策略)")
+	infiniteReference := flag.Bool("infinite-reference", false, "概率分析附带无限副牌参考，显示牌堆组成对胜率的影响")
+	scenarioFile := flag.String("scenario", "", "场景文件(JSON): 按文件中的牌序发牌，可覆盖规则与初始筹码")
+	cli.RegisterRuleFlags(flag.CommandLine, &rules)
+	if err := cli.ParseRuleFlags(flag.CommandLine, &rules, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	renderer, err := cli.NewRenderer(*ui)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	strategySource, err := services.ParseStrategySource(*strategy)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	options := []cli.GameHandlerOption{
+		cli.WithRenderer(renderer),
+		cli.WithRules(rules),
+		cli.WithSideBets(*sideBets),
+		cli.WithStrategySource(strategySource),
+		cli.WithInfiniteDeckReference(*infiniteReference),
+		cli.WithFairHistory(*fairHistory),
+	}
+	if *scenarioFile != "" {
+		scenario, err := loadScenario(*scenarioFile, rules)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		options = append(options, cli.WithScenario(scenario))
+	}
+
 	// 创建命令行游戏处理器
-	gameHandler := cli.NewGameHandler()
+	gameHandler := cli.NewGameHandler(options...)
 
 	// 运行游戏
 	gameHandler.Run()
 }
+
+// loadScenario 读取场景文件，文件中的规则覆盖命令行规则
+func loadScenario(path string, rules entities.Rules) (*entities.Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return entities.ParseScenario(data, rules)
+}
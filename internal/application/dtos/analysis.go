@@ -0,0 +1,112 @@
+package dtos
+
+import "github.com/luffy050596/go-blackjack/internal/domain/entities"
+
+// BankrollAnalysisDTO 资金风险分析数据传输对象
+type BankrollAnalysisDTO struct {
+	Bankroll      float64                `json:"bankroll"`
+	Edge          float64                `json:"edge"`
+	Variance      float64                `json:"variance"`
+	Policy        string                 `json:"policy"`
+	InitialBet    float64                `json:"initial_bet"`
+	RiskOfRuin    float64                `json:"risk_of_ruin"`
+	N0            float64                `json:"n0"` // 0表示没有正期望
+	RoundsPerHour float64                `json:"rounds_per_hour"`
+	HourlyEV      float64                `json:"hourly_ev"`
+	HourlyStdDev  float64                `json:"hourly_std_dev"`
+	Simulation    *BankrollSimulationDTO `json:"simulation,omitempty"`
+}
+
+// BankrollSimulationDTO 蒙特卡洛资金分布数据传输对象
+type BankrollSimulationDTO struct {
+	Rounds            int     `json:"rounds"`
+	Trials            int     `json:"trials"`
+	Mean              float64 `json:"mean"`
+	P5                float64 `json:"p5"`
+	P25               float64 `json:"p25"`
+	Median            float64 `json:"median"`
+	P75               float64 `json:"p75"`
+	P95               float64 `json:"p95"`
+	RuinProbability   float64 `json:"ruin_probability"`
+	ProfitProbability float64 `json:"profit_probability"`
+}
+
+// HouseEdgeDTO 赌场优势计算结果数据传输对象
+type HouseEdgeDTO struct {
+	Rules       entities.Rules         `json:"rules"`
+	PlayerEV    float64                `json:"player_ev"`    // 首手玩家期望（以注码为单位）
+	HouseEdge   float64                `json:"house_edge"`   // 赌场优势（玩家期望取负）
+	ReferenceEV float64                `json:"reference_ev"` // 参考规则（默认规则）下的玩家期望
+	Breakdown   []*RuleContributionDTO `json:"breakdown"`
+}
+
+// RuleContributionDTO 单项规则对玩家期望的影响
+type RuleContributionDTO struct {
+	Rule        string  `json:"rule"`
+	Description string  `json:"description"`
+	EVChange    float64 `json:"ev_change"`
+}
+
+// CountingSimulationDTO 算牌优势模拟结果数据传输对象
+type CountingSimulationDTO struct {
+	System            string                `json:"system"`
+	ContinuousShuffle bool                  `json:"continuous_shuffle"`
+	Rounds            int                   `json:"rounds"`
+	Spread            int                   `json:"spread"`
+	FlatEV            float64               `json:"flat_ev"`     // 平注时每局期望（以注码为单位）
+	SpreadEV          float64               `json:"spread_ev"`   // 按真数加注时每单位注码的期望
+	AverageBet        float64               `json:"average_bet"` // 平均下注单位
+	TrueCounts        []*TrueCountResultDTO `json:"true_counts"`
+}
+
+// TrueCountResultDTO 下注前真数相同的局面的模拟结果
+type TrueCountResultDTO struct {
+	TrueCount int     `json:"true_count"`
+	Rounds    int     `json:"rounds"`
+	EV        float64 `json:"ev"` // 每单位注码的期望
+}
+
+// ShuffleAnalysisDTO 洗牌残留牌序相关性的统计结果
+type ShuffleAnalysisDTO struct {
+	Model                   string  `json:"model"`
+	Imperfection            float64 `json:"imperfection"`
+	Cards                   int     `json:"cards"`
+	Trials                  int     `json:"trials"`
+	RankCorrelation         float64 `json:"rank_correlation"`          // 洗牌前后位置的Spearman秩相关系数，理想洗牌为0
+	AdjacentPairs           float64 `json:"adjacent_pairs"`            // 原本相邻的两张牌洗牌后仍紧挨（先后不变）的比例
+	ExpectedAdjacentPairs   float64 `json:"expected_adjacent_pairs"`   // 理想洗牌下相邻牌仍紧挨的比例
+	RisingSequences         float64 `json:"rising_sequences"`          // 平均上升序列数，越少牌序残留越多
+	ExpectedRisingSequences float64 `json:"expected_rising_sequences"` // 理想洗牌下的平均上升序列数
+}
+
+// StrategyComparisonDTO 组成相关策略与总点数基本策略的首手比较数据传输对象
+type StrategyComparisonDTO struct {
+	Situations     int                      `json:"situations"`      // 比较的首手局面数（玩家两张牌与庄家明牌）
+	DifferenceRate float64                  `json:"difference_rate"` // 两种策略决策不同的首手出现的概率
+	EVGain         float64                  `json:"ev_gain"`         // 按组成相关策略决策每手多得的期望值（以初始注码为单位）
+	Differences    []*StrategyDifferenceDTO `json:"differences"`     // 决策不同的局面，按对总期望值的贡献从大到小
+}
+
+// StrategyDifferenceDTO 两种策略决策不同的局面数据传输对象
+type StrategyDifferenceDTO struct {
+	PlayerCards       []string              `json:"player_cards"`
+	DealerUpCard      string                `json:"dealer_up_card"`
+	BasicAction       entities.PlayerAction `json:"basic_action"`
+	CompositionAction entities.PlayerAction `json:"composition_action"`
+	Probability       float64               `json:"probability"` // 出现该局面并需要决策的概率
+	Gain              float64               `json:"gain"`        // 该局面下组成相关决策多得的期望值
+}
+
+// StrategyChartDTO 基本策略表数据传输对象
+type StrategyChartDTO struct {
+	DealerUpCards []string               `json:"dealer_up_cards"` // 各列的庄家明牌
+	Hard          []*StrategyChartRowDTO `json:"hard"`
+	Soft          []*StrategyChartRowDTO `json:"soft"`
+	Pairs         []*StrategyChartRowDTO `json:"pairs"`
+}
+
+// StrategyChartRowDTO 基本策略表的一行
+type StrategyChartRowDTO struct {
+	Hand    string                  `json:"hand"`    // 行标签，如"16"、"A-7"、"8-8"
+	Actions []entities.PlayerAction `json:"actions"` // 对各列庄家明牌的最优操作
+}
@@ -1,17 +1,46 @@
 // Package dtos contains data transfer objects for the blackjack game application.
 package dtos
 
-import "github.com/luffy050596/go-blackjack/internal/domain/entities"
+import (
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
 
 // GameStateDTO 游戏状态数据传输对象
 type GameStateDTO struct {
 	RoundNumber int                `json:"round_number"`
 	PlayerChips int                `json:"player_chips"`
-	PlayerBet   int                `json:"player_bet"`
-	PlayerHand  *HandDTO           `json:"player_hand"`
+	PlayerBet   int                `json:"player_bet"`  // 所有手牌的下注合计
+	PlayerHand  *HandDTO           `json:"player_hand"` // 当前行动的手牌
 	DealerHand  *HandDTO           `json:"dealer_hand"`
 	State       entities.GameState `json:"state"`
 	IsGameOver  bool               `json:"is_game_over"`
+
+	// 庄家两张牌都是暗牌（Pontoon玩家回合）
+	DealerFaceDown bool `json:"dealer_face_down"`
+
+	// 分牌或换牌玩法中玩家有多手牌时的所有手牌
+	PlayerHands []*PlayerHandDTO `json:"player_hands,omitempty"`
+	ActiveHand  int              `json:"active_hand"`
+}
+
+// PlayerHandDTO 多手牌中单手牌的数据传输对象
+type PlayerHandDTO struct {
+	Hand       *HandDTO `json:"hand"`
+	Bet        int      `json:"bet"`
+	FreeBet    int      `json:"free_bet"` // 免费加倍或分牌由庄家出资的注码
+	IsDoubled  bool     `json:"is_doubled"`
+	IsFinished bool     `json:"is_finished"`
+}
+
+// BetOptionsDTO 下注选项数据传输对象
+type BetOptionsDTO struct {
+	Amounts          []int `json:"amounts"` // 常用下注金额
+	MinBet           int   `json:"min_bet"`
+	MaxBet           int   `json:"max_bet"`
+	ChipDenomination int   `json:"chip_denomination"`
+	LastBet          int   `json:"last_bet"`
 }
 
 // HandDTO 手牌数据传输对象
@@ -34,6 +63,9 @@ type ActionResultDTO struct {
 	Continue bool                  `json:"continue"`
 	Card     *CardDTO              `json:"card,omitempty"`
 	Message  string                `json:"message,omitempty"`
+
+	// 训练模式下的决策反馈
+	Feedback *DecisionFeedbackDTO `json:"feedback,omitempty"`
 }
 
 // GameResultDTO 游戏结果数据传输对象
@@ -42,6 +74,38 @@ type GameResultDTO struct {
 	BetAmount   int                 `json:"bet_amount"`
 	IsDoubled   bool                `json:"is_doubled"`
 	PlayerChips int                 `json:"player_chips"`
+	SideBets    []*SideBetResultDTO `json:"side_bets,omitempty"`
+	Hands       []*HandResultDTO    `json:"hands,omitempty"` // 多手牌时各手的结果
+}
+
+// HandResultDTO 多手牌中单手牌的结果数据传输对象
+type HandResultDTO struct {
+	Type      entities.ResultType `json:"type"`
+	BetAmount int                 `json:"bet_amount"`
+	IsDoubled bool                `json:"is_doubled"`
+}
+
+// SideBetResultDTO 边注结算结果数据传输对象
+type SideBetResultDTO struct {
+	Type     entities.SideBetType `json:"type"`
+	Amount   int                  `json:"amount"`
+	Hand     string               `json:"hand"`
+	Payout   int                  `json:"payout"`    // 赔率（x:1），未中奖为0
+	NetValue int                  `json:"net_value"` // 净输赢
+}
+
+// SideBetEVDTO 边注期望值数据传输对象
+type SideBetEVDTO struct {
+	Type     entities.SideBetType `json:"type"`
+	EV       float64              `json:"ev"` // 以边注金额为单位的期望值
+	Outcomes []*SideBetOutcomeDTO `json:"outcomes"`
+}
+
+// SideBetOutcomeDTO 边注单个牌型的赔率与概率
+type SideBetOutcomeDTO struct {
+	Hand        string  `json:"hand"`
+	Payout      int     `json:"payout"`
+	Probability float64 `json:"probability"`
 }
 
 // BetOptionDTO 下注选项数据传输对象
@@ -70,6 +134,27 @@ type ProbabilityResultDTO struct {
 
 	// 操作胜率分析
 	ActionAnalysis *ActionAnalysisDTO `json:"action_analysis,omitempty"`
+
+	// 换牌建议（仅换牌玩法可换牌时）
+	SwitchAdvice *SwitchAdviceDTO `json:"switch_advice,omitempty"`
+
+	// 无限副牌参考（仅启用时）
+	InfiniteDeck *InfiniteDeckReferenceDTO `json:"infinite_deck,omitempty"`
+}
+
+// InfiniteDeckReferenceDTO 无限副牌参考数据传输对象：每张牌按点数的固定概率抽取，不受已发出的牌影响
+type InfiniteDeckReferenceDTO struct {
+	ExpectedValue     float64 `json:"expected_value"`      // 无限副牌下最优操作的期望值
+	ShoeExpectedValue float64 `json:"shoe_expected_value"` // 当前确切牌堆下最优操作的期望值
+	RecommendedAction string  `json:"recommended_action"`  // 无限副牌下的推荐操作
+	CompositionEffect float64 `json:"composition_effect"`  // 牌堆组成的影响：当前牌堆与无限副牌的期望值之差
+}
+
+// SwitchAdviceDTO 换牌建议数据传输对象
+type SwitchAdviceDTO struct {
+	KeepEV       float64 `json:"keep_ev"`       // 保持原样时两手牌的期望值之和
+	SwitchEV     float64 `json:"switch_ev"`     // 交换第二张牌后两手牌的期望值之和
+	ShouldSwitch bool    `json:"should_switch"` // 是否建议换牌
 }
 
 // ActionAnalysisDTO 操作胜率分析数据传输对象
@@ -87,7 +172,8 @@ type ActionAnalysisDTO struct {
 
 	// 推荐操作
 	RecommendedAction string  `json:"recommended_action"`
-	ExpectedValue     float64 `json:"expected_value"` // 推荐操作的期望值
+	ExpectedValue     float64 `json:"expected_value"`  // 推荐操作的期望值
+	StrategySource    string  `json:"strategy_source"` // 推荐操作的来源: simulation、basic或composition
 
 	// 凯利公式相关
 	KellyRecommendation *KellyRecommendationDTO `json:"kelly_recommendation,omitempty"`
@@ -112,3 +198,146 @@ type KellyRecommendationDTO struct {
 	RiskLevel          string  `json:"risk_level"`           // 风险等级 (Low/Medium/High)
 	ExpectedGrowthRate float64 `json:"expected_growth_rate"` // 期望资金增长率
 }
+
+// DecisionFeedbackDTO 策略决策反馈数据传输对象
+type DecisionFeedbackDTO struct {
+	Category     string                `json:"category"`      // 手牌类别 (hard/soft/pair)
+	ChosenAction entities.PlayerAction `json:"chosen_action"` // 玩家选择的操作
+	BestAction   entities.PlayerAction `json:"best_action"`   // 基本策略推荐的操作
+	ChosenEV     float64               `json:"chosen_ev"`     // 玩家选择的期望值
+	BestEV       float64               `json:"best_ev"`       // 最优操作的期望值
+	EVCost       float64               `json:"ev_cost"`       // 错误决策的期望损失（以注码为单位）
+	IsCorrect    bool                  `json:"is_correct"`
+}
+
+// CategoryStatsDTO 单类手牌训练统计数据传输对象
+type CategoryStatsDTO struct {
+	Category      string  `json:"category"`
+	Decisions     int     `json:"decisions"`
+	Correct       int     `json:"correct"`
+	Accuracy      float64 `json:"accuracy"`
+	AverageEVCost float64 `json:"average_ev_cost"`
+}
+
+// TrainingStatsDTO 训练统计数据传输对象
+type TrainingStatsDTO struct {
+	Categories      []*CategoryStatsDTO `json:"categories"`
+	TotalDecisions  int                 `json:"total_decisions"`
+	Accuracy        float64             `json:"accuracy"`
+	TotalEVCost     float64             `json:"total_ev_cost"`
+	WeakestCategory string              `json:"weakest_category"`
+}
+
+// StrategyDrillDTO 策略专项练习题目数据传输对象
+type StrategyDrillDTO struct {
+	Category     string   `json:"category"`
+	PlayerHand   *HandDTO `json:"player_hand"`
+	DealerUpCard *CardDTO `json:"dealer_up_card"`
+	CanDouble    bool     `json:"can_double"`
+	CanSplit     bool     `json:"can_split"`
+	CanSurrender bool     `json:"can_surrender"`
+}
+
+// DiscardTrayDTO 弃牌架数据传输对象
+type DiscardTrayDTO struct {
+	Discarded      int             `json:"discarded"`       // 弃牌架上亮过的弃牌张数
+	Burned         int             `json:"burned"`          // 洗牌后烧掉的暗牌张数
+	CardsRemaining int             `json:"cards_remaining"` // 牌靴中还没发的张数
+	Seen           []*RankCountDTO `json:"seen"`            // 本牌靴已看到的牌（弃牌与桌上明牌）按点数的张数
+}
+
+// RankCountDTO 单个点数的张数数据传输对象
+type RankCountDTO struct {
+	Rank  string `json:"rank"`
+	Count int    `json:"count"`
+}
+
+// ShoeCompositionDTO 牌靴组成数据传输对象
+type ShoeCompositionDTO struct {
+	ShoeSize         int                   `json:"shoe_size"`          // 完整牌靴的张数
+	CardsRemaining   int                   `json:"cards_remaining"`    // 还没看到的张数（牌靴余牌与烧牌）
+	DecksRemaining   float64               `json:"decks_remaining"`    // 还没看到的副数
+	Penetration      float64               `json:"penetration"`        // 已从牌靴发出的比例
+	Discarded        int                   `json:"discarded"`          // 弃牌架上亮过的弃牌张数
+	Burned           int                   `json:"burned"`             // 洗牌后烧掉的暗牌张数
+	Ranks            []*RankCompositionDTO `json:"ranks"`              // A到K各点数的余牌
+	PlayerEdge       float64               `json:"player_edge"`        // 按余牌组成计算的首手玩家期望值
+	EffectsOfRemoval []*EffectOfRemovalDTO `json:"effects_of_removal"` // A到10各点数
+}
+
+// RankCompositionDTO 单个点数的余牌数据传输对象
+type RankCompositionDTO struct {
+	Rank         string  `json:"rank"`
+	Remaining    int     `json:"remaining"`
+	Fresh        int     `json:"fresh"`         // 完整牌靴中的张数
+	Percent      float64 `json:"percent"`       // 占余牌的比例
+	FreshPercent float64 `json:"fresh_percent"` // 完整牌靴中的比例
+}
+
+// EffectOfRemovalDTO 移除一张牌对玩家期望值的影响数据传输对象
+type EffectOfRemovalDTO struct {
+	Rank   string  `json:"rank"`   // 点数，10代表所有10点牌
+	Effect float64 `json:"effect"` // 从余牌中移除一张该点数的牌后玩家期望值的变化
+}
+
+// FairShoeDTO 可验证公平牌靴数据传输对象，也是牌靴历史文件每行的格式
+type FairShoeDTO struct {
+	Number      int      `json:"number"`
+	ClientSeed  string   `json:"client_seed"`
+	Commitment  string   `json:"commitment"`            // 下注前公布的承诺
+	ServerSeed  string   `json:"server_seed,omitempty"` // 牌靴结束后公开
+	DeckCount   int      `json:"deck_count"`
+	SpanishDeck bool     `json:"spanish_deck"`
+	Excluded    []string `json:"excluded,omitempty"`
+	Dealt       []string `json:"dealt,omitempty"`
+}
+
+// CountingSystemDTO 算牌系统数据传输对象
+type CountingSystemDTO struct {
+	Name     string `json:"name"`
+	Balanced bool   `json:"balanced"`
+	Tags     []int  `json:"tags"` // A,2,3,...,9,10 的计数值
+}
+
+// CountingDrillDTO 算牌练习数据传输对象
+type CountingDrillDTO struct {
+	System           *CountingSystemDTO `json:"system"`
+	DeckCount        int                `json:"deck_count"`
+	DealIntervalMs   int64              `json:"deal_interval_ms"`
+	CardsPerQuestion int                `json:"cards_per_question"`
+	CardsToDeal      int                `json:"cards_to_deal"`
+}
+
+// CountingCardDTO 算牌练习发出的牌数据传输对象
+type CountingCardDTO struct {
+	Card           *CardDTO `json:"card"`
+	CardsDealt     int      `json:"cards_dealt"`
+	CardsRemaining int      `json:"cards_remaining"`
+	QuestionDue    bool     `json:"question_due"` // 是否需要回答计数
+	Finished       bool     `json:"finished"`
+}
+
+// CountingAnswerDTO 算牌回答结果数据传输对象
+type CountingAnswerDTO struct {
+	RunningCount    int     `json:"running_count"`
+	TrueCount       float64 `json:"true_count"`
+	AnsweredRunning int     `json:"answered_running"`
+	AnsweredTrue    float64 `json:"answered_true"`
+	TrueCountAsked  bool    `json:"true_count_asked"`
+	RunningCorrect  bool    `json:"running_correct"`
+	TrueCorrect     bool    `json:"true_correct"`
+	ResponseSeconds float64 `json:"response_seconds"`
+}
+
+// CountingSessionDTO 算牌练习记录数据传输对象
+type CountingSessionDTO struct {
+	System                 string    `json:"system"`
+	DeckCount              int       `json:"deck_count"`
+	StartedAt              time.Time `json:"started_at"`
+	CardsDealt             int       `json:"cards_dealt"`
+	CardsPerMinute         float64   `json:"cards_per_minute"`
+	Questions              int       `json:"questions"`
+	CorrectAnswers         int       `json:"correct_answers"`
+	Accuracy               float64   `json:"accuracy"`
+	AverageResponseSeconds float64   `json:"average_response_seconds"`
+}
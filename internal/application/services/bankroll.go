@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 二十一点每单位注码的典型方差
+const defaultBlackjackVariance = 1.33
+
+// BetPolicy 下注策略
+type BetPolicy int
+
+const (
+	// BetPolicyFlat bets the same amount every round
+	BetPolicyFlat BetPolicy = iota
+	// BetPolicyProportional bets a multiple of the Kelly fraction of the current bankroll
+	BetPolicyProportional
+)
+
+func (p BetPolicy) String() string {
+	switch p {
+	case BetPolicyFlat:
+		return "flat"
+	case BetPolicyProportional:
+		return "kelly"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseBetPolicy 解析下注策略名称
+func ParseBetPolicy(name string) (BetPolicy, error) {
+	for _, policy := range []BetPolicy{BetPolicyFlat, BetPolicyProportional} {
+		if policy.String() == name {
+			return policy, nil
+		}
+	}
+	return BetPolicyFlat, fmt.Errorf("unknown bet policy %q", name)
+}
+
+// BankrollParams 资金分析参数
+type BankrollParams struct {
+	Bankroll        float64   // 初始资金
+	Edge            float64   // 每单位注码的期望收益（玩家优势为正）
+	Variance        float64   // 每单位注码的方差
+	Policy          BetPolicy // 下注策略
+	BetAmount       float64   // 固定下注金额
+	KellyMultiplier float64   // 比例下注时相对完整凯利的倍数（0.5为半凯利）
+	MinBet          float64   // 牌桌最低下注，资金低于此值视为破产
+	RoundsPerHour   float64   // 每小时局数
+	Rounds          int       // 蒙特卡洛模拟的局数
+	Trials          int       // 蒙特卡洛模拟次数
+}
+
+// DefaultBankrollParams 默认资金分析参数：最低注平注，每小时100局
+func DefaultBankrollParams(rules entities.Rules) BankrollParams {
+	return BankrollParams{
+		Bankroll:        1000,
+		Edge:            -0.005,
+		Variance:        defaultBlackjackVariance,
+		Policy:          BetPolicyFlat,
+		BetAmount:       float64(rules.MinBet),
+		KellyMultiplier: 0.5,
+		MinBet:          float64(rules.MinBet),
+		RoundsPerHour:   100,
+		Rounds:          1000,
+		Trials:          2000,
+	}
+}
+
+// validate 检查参数
+func (p BankrollParams) validate() error {
+	switch {
+	case p.Bankroll <= 0:
+		return errors.New("bankroll must be positive")
+	case p.Variance <= 0:
+		return errors.New("variance must be positive")
+	case p.Policy == BetPolicyFlat && p.BetAmount <= 0:
+		return errors.New("flat bet amount must be positive")
+	case p.Policy == BetPolicyProportional && p.Edge <= 0:
+		return errors.New("proportional betting requires a positive edge")
+	case p.Policy == BetPolicyProportional && p.KellyMultiplier <= 0:
+		return errors.New("kelly multiplier must be positive")
+	case p.Rounds < 0 || p.Trials < 0:
+		return errors.New("rounds and trials must not be negative")
+	}
+	return nil
+}
+
+// fullKellyFraction 完整凯利比例（期望除以方差）
+func (p BankrollParams) fullKellyFraction() float64 {
+	return p.Edge / p.Variance
+}
+
+// betFor 按当前资金计算下注金额
+func (p BankrollParams) betFor(bankroll float64) float64 {
+	if p.Policy == BetPolicyProportional {
+		return max(p.MinBet, p.KellyMultiplier*p.fullKellyFraction()*bankroll)
+	}
+	return p.BetAmount
+}
+
+// BankrollService 资金风险分析服务
+type BankrollService struct {
+	rng *rand.Rand
+}
+
+// NewBankrollService 创建资金风险分析服务，seed为0时使用当前时间
+func NewBankrollService(seed uint64) *BankrollService {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return &BankrollService{
+		rng: rand.New(rand.NewPCG(seed, seed<<32)),
+	}
+}
+
+// Analyze 计算破产风险、N0、每小时期望与资金分布
+func (s *BankrollService) Analyze(params BankrollParams) (*dtos.BankrollAnalysisDTO, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	bet := params.betFor(params.Bankroll)
+	result := &dtos.BankrollAnalysisDTO{
+		Bankroll:      params.Bankroll,
+		Edge:          params.Edge,
+		Variance:      params.Variance,
+		Policy:        params.Policy.String(),
+		InitialBet:    bet,
+		RiskOfRuin:    RiskOfRuin(params),
+		N0:            N0(params.Edge, params.Variance),
+		RoundsPerHour: params.RoundsPerHour,
+		HourlyEV:      params.Edge * bet * params.RoundsPerHour,
+		HourlyStdDev:  math.Sqrt(params.Variance*params.RoundsPerHour) * bet,
+	}
+
+	if params.Rounds > 0 && params.Trials > 0 {
+		result.Simulation = s.simulate(params)
+	}
+
+	return result, nil
+}
+
+// RiskOfRuin 破产风险
+// 平注使用扩散近似 exp(-2·edge·B/(variance·bet))；
+// 比例下注（k倍凯利）跌到最低注的概率为 (MinBet/B)^(2/k-1)
+func RiskOfRuin(params BankrollParams) float64 {
+	if params.Edge <= 0 {
+		return 1
+	}
+
+	if params.Policy == BetPolicyProportional {
+		if params.KellyMultiplier >= 2 || params.MinBet >= params.Bankroll {
+			return 1
+		}
+		if params.MinBet <= 0 {
+			return 0
+		}
+		return math.Pow(params.MinBet/params.Bankroll, 2/params.KellyMultiplier-1)
+	}
+
+	return math.Exp(-2 * params.Edge * params.Bankroll / (params.Variance * params.BetAmount))
+}
+
+// N0 期望收益等于一个标准差所需的局数，没有正期望时返回0
+func N0(edge, variance float64) float64 {
+	if edge <= 0 {
+		return 0
+	}
+	return variance / (edge * edge)
+}
+
+// simulate 蒙特卡洛模拟若干局后的资金分布（每局结果按正态近似）
+func (s *BankrollService) simulate(params BankrollParams) *dtos.BankrollSimulationDTO {
+	stdDev := math.Sqrt(params.Variance)
+	finals := make([]float64, params.Trials)
+	ruined := 0
+	profitable := 0
+	total := 0.0
+
+	for trial := range params.Trials {
+		bankroll := params.Bankroll
+		for range params.Rounds {
+			bet := params.betFor(bankroll)
+			if bankroll < bet || bankroll < params.MinBet {
+				ruined++
+				break
+			}
+			bankroll += bet * (params.Edge + stdDev*s.rng.NormFloat64())
+		}
+
+		finals[trial] = bankroll
+		total += bankroll
+		if bankroll > params.Bankroll {
+			profitable++
+		}
+	}
+
+	slices.Sort(finals)
+	trials := float64(params.Trials)
+	return &dtos.BankrollSimulationDTO{
+		Rounds:            params.Rounds,
+		Trials:            params.Trials,
+		Mean:              total / trials,
+		P5:                percentile(finals, 0.05),
+		P25:               percentile(finals, 0.25),
+		Median:            percentile(finals, 0.5),
+		P75:               percentile(finals, 0.75),
+		P95:               percentile(finals, 0.95),
+		RuinProbability:   float64(ruined) / trials,
+		ProfitProbability: float64(profitable) / trials,
+	}
+}
+
+// percentile 已排序数据的分位数
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(q * float64(len(sorted)-1))
+	return sorted[index]
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestRiskOfRuin 测试破产风险公式
+func TestRiskOfRuin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		params   BankrollParams
+		expected float64
+	}{
+		{
+			name:     "negative_edge",
+			params:   BankrollParams{Bankroll: 1000, Edge: -0.005, Variance: 1.33, BetAmount: 10},
+			expected: 1,
+		},
+		{
+			name:     "flat",
+			params:   BankrollParams{Bankroll: 1000, Edge: 0.01, Variance: 1.33, BetAmount: 10},
+			expected: math.Exp(-2 * 0.01 * 100 / 1.33),
+		},
+		{
+			name: "half_kelly",
+			params: BankrollParams{Bankroll: 1000, Edge: 0.01, Variance: 1.33,
+				Policy: BetPolicyProportional, KellyMultiplier: 0.5, MinBet: 100},
+			expected: 0.001,
+		},
+		{
+			name: "double_kelly",
+			params: BankrollParams{Bankroll: 1000, Edge: 0.01, Variance: 1.33,
+				Policy: BetPolicyProportional, KellyMultiplier: 2, MinBet: 10},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := RiskOfRuin(tt.params); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Expected %.6f, got %.6f", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestN0 测试N0
+func TestN0(t *testing.T) {
+	t.Parallel()
+
+	if got := N0(0.01, 1.33); math.Abs(got-13300) > 1e-6 {
+		t.Errorf("Expected N0 13300, got %.2f", got)
+	}
+	if got := N0(-0.005, 1.33); got != 0 {
+		t.Errorf("Expected N0 0 without positive edge, got %.2f", got)
+	}
+}
+
+// TestBankrollAnalyze 测试资金分析结果与参数校验
+func TestBankrollAnalyze(t *testing.T) {
+	t.Parallel()
+
+	params := DefaultBankrollParams(entities.DefaultRules())
+	params.Bankroll = 200
+	params.Edge = -0.02
+	params.Rounds = 5000
+	params.Trials = 500
+
+	analysis, err := NewBankrollService(1).Analyze(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if analysis.RiskOfRuin != 1 {
+		t.Errorf("Expected certain ruin with negative edge, got %.4f", analysis.RiskOfRuin)
+	}
+	if math.Abs(analysis.HourlyEV-(-0.02*10*100)) > 1e-9 {
+		t.Errorf("Unexpected hourly EV %.4f", analysis.HourlyEV)
+	}
+
+	sim := analysis.Simulation
+	if sim == nil || sim.RuinProbability < 0.8 {
+		t.Fatalf("Expected most trials to go broke, got %+v", sim)
+	}
+	if sim.P5 > sim.Median || sim.Median > sim.P95 {
+		t.Errorf("Expected ordered percentiles, got %+v", sim)
+	}
+
+	params.Policy = BetPolicyProportional
+	if _, err := NewBankrollService(1).Analyze(params); err == nil {
+		t.Error("Expected error for proportional betting without an edge")
+	}
+}
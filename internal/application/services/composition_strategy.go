@@ -0,0 +1,350 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// StrategySource 推荐操作的来源
+type StrategySource int
+
+const (
+	// StrategySimulation recommends the action with the highest simulated win rate
+	StrategySimulation StrategySource = iota
+	// StrategyBasic recommends the total-dependent basic strategy action for a full shoe
+	StrategyBasic
+	// StrategyComposition recommends the composition-dependent optimal action for the exact remaining shoe
+	StrategyComposition
+)
+
+// StrategySources 所有推荐操作来源
+var StrategySources = []StrategySource{StrategySimulation, StrategyBasic, StrategyComposition}
+
+func (s StrategySource) String() string {
+	switch s {
+	case StrategySimulation:
+		return "simulation"
+	case StrategyBasic:
+		return "basic"
+	case StrategyComposition:
+		return "composition"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseStrategySource 解析推荐操作来源名称
+func ParseStrategySource(name string) (StrategySource, error) {
+	for _, source := range StrategySources {
+		if source.String() == name {
+			return source, nil
+		}
+	}
+	return StrategySimulation, fmt.Errorf("unknown strategy source %q", name)
+}
+
+// compositionKey 组成相关求解的记忆化键：玩家手牌状态与剩余牌堆
+type compositionKey struct {
+	state handState
+	shoe  [tenValue + 1]int
+}
+
+// compositionSolver 组成相关（composition-dependent）最优策略求解器
+// 玩家每要一张牌都从牌堆中移除，庄家结果按移除后的牌堆重新计算，
+// 因此点数相同、组成不同的手牌可能有不同的最优操作；同一庄家明牌下的记忆化结果可在多手牌之间共享
+type compositionSolver struct {
+	ev      *EVCalculator
+	up      int
+	dealers map[[tenValue + 1]int]*dealerOutcome
+	hits    map[compositionKey]float64
+}
+
+// newCompositionSolver 创建针对庄家明牌的组成相关求解器
+func newCompositionSolver(ev *EVCalculator, up int) *compositionSolver {
+	return &compositionSolver{
+		ev:      ev,
+		up:      up,
+		dealers: make(map[[tenValue + 1]int]*dealerOutcome),
+		hits:    make(map[compositionKey]float64),
+	}
+}
+
+// CalculateCompositionEVs 按组成相关策略计算当前局面下各操作的期望值：
+// 之后的每一步决策都针对当时确切的手牌组成与剩余牌堆，而不只看点数
+// remainingCards 为玩家未知的牌（包括庄家底牌所在的牌堆）
+func (ev *EVCalculator) CalculateCompositionEVs(
+	playerCards []entities.Card,
+	dealerUpCard entities.Card,
+	remainingCards []entities.Card,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *ActionEV {
+	up := cardPoint(dealerUpCard)
+	return newCompositionSolver(ev, up).actionEVs(playerCards, ev.remainingShoe(remainingCards), canDouble, canSplit, canSurrender)
+}
+
+// actionEVs 计算各操作的组成相关期望值
+func (s *compositionSolver) actionEVs(
+	playerCards []entities.Card,
+	shoe shoeComposition,
+	canDouble, canSplit, canSurrender bool,
+) *ActionEV {
+	state := newHandState(playerCards)
+
+	result := s.ev.availableActions(playerCards, canDouble, canSplit, canSurrender)
+	result.Stand = s.standEV(state, shoe)
+	result.Hit = s.hitEV(state, shoe)
+	if result.CanDouble {
+		result.Double = s.doubleEV(state, shoe)
+	}
+	if result.CanSplit {
+		result.Split = s.splitEV(cardPoint(playerCards[0]), shoe)
+	}
+
+	return s.ev.weighDealerBlackjack(result, s.ev.dealerOutcomes(s.up, shoe).blackjack)
+}
+
+// dealerOutcome 庄家按牌堆的最终结果分布，与总点数计算一致地排除庄家Blackjack
+func (s *compositionSolver) dealerOutcome(shoe shoeComposition) *dealerOutcome {
+	if outcome, ok := s.dealers[shoe.counts]; ok {
+		return outcome
+	}
+	outcome := s.ev.dealerOutcomes(s.up, shoe).withoutBlackjack()
+	s.dealers[shoe.counts] = outcome
+	return outcome
+}
+
+// standEV 停牌期望值
+func (s *compositionSolver) standEV(state handState, shoe shoeComposition) float64 {
+	return s.ev.standEV(state, s.dealerOutcome(shoe))
+}
+
+// hitEV 要一张牌后按最优策略继续的期望值，要到的牌从牌堆中移除
+func (s *compositionSolver) hitEV(state handState, shoe shoeComposition) float64 {
+	key := compositionKey{state: state, shoe: shoe.counts}
+	if value, ok := s.hits[key]; ok {
+		return value
+	}
+
+	result := 0.0
+	for value := aceValue; value <= tenValue; value++ {
+		count := shoe.counts[value]
+		if count == 0 {
+			continue
+		}
+		next := shoe
+		next.remove(value)
+		result += float64(count) / float64(shoe.total) * s.bestHitStandEV(state.draw(value), next)
+	}
+
+	s.hits[key] = result
+	return result
+}
+
+// bestHitStandEV 只能要牌或停牌时的最优期望值
+func (s *compositionSolver) bestHitStandEV(state handState, shoe shoeComposition) float64 {
+	total, _ := state.total()
+	if total > 21 {
+		return -1
+	}
+
+	stand := s.standEV(state, shoe)
+	if total == 21 || s.ev.isAutoWin(state) {
+		return stand
+	}
+	if !s.ev.canStand(state) {
+		return s.hitEV(state, shoe)
+	}
+
+	best := max(stand, s.hitEV(state, shoe))
+	if s.ev.rules.DoubleAnyCards && s.ev.canDouble(state) {
+		best = max(best, s.doubleEV(state, shoe))
+	}
+	return best
+}
+
+// doubleEV 加倍期望值，庄家结果按加倍拿到的牌移除后的牌堆计算
+func (s *compositionSolver) doubleEV(state handState, shoe shoeComposition) float64 {
+	result := 0.0
+	for value := aceValue; value <= tenValue; value++ {
+		count := shoe.counts[value]
+		if count == 0 {
+			continue
+		}
+		next := shoe
+		next.remove(value)
+
+		prob := float64(count) / float64(shoe.total)
+		if s.ev.rules.Pontoon {
+			// Pontoon买牌后仍可继续要牌
+			result += prob * 2 * s.bestHitStandEV(state.draw(value), next)
+			continue
+		}
+		result += prob * s.ev.doubledEV(state, value, s.dealerOutcome(next))
+	}
+	return result
+}
+
+// splitEV 分牌期望值：按与总点数计算相同的补牌与再分牌过程，每手牌按移除已补出的对子牌与自身第二张牌后的牌堆决策，
+// 分A只补一张，DAS规则下补牌后可加倍；免费分牌的手牌按补牌后的牌堆以总点数打法只计赢钱
+func (s *compositionSolver) splitEV(pairValue int, shoe shoeComposition) float64 {
+	start := handState{}.draw(pairValue)
+
+	paid, hands := s.ev.splitHandsEV(pairValue, shoe, func(second int, shoe shoeComposition) float64 {
+		next := shoe
+		next.remove(second)
+		state := start.draw(second)
+		if s.ev.splitAcesOneCard(pairValue) {
+			return s.standEV(state, next)
+		}
+		best := s.bestHitStandEV(state, next)
+		if s.ev.rules.DoubleAfterSplit && s.ev.canDouble(state) {
+			best = max(best, s.doubleEV(state, next))
+		}
+		return best
+	})
+	if !s.ev.isFreeSplit(pairValue) {
+		return paid
+	}
+
+	memos := make(map[[tenValue + 1]int]map[handState]float64)
+	free, _ := s.ev.splitHandsEV(pairValue, shoe, func(second int, shoe shoeComposition) float64 {
+		next := shoe
+		next.remove(second)
+		state := start.draw(second)
+		dealer := s.dealerOutcome(next)
+		if s.ev.splitAcesOneCard(pairValue) {
+			win, _ := s.ev.settleOdds(state, dealer)
+			return win
+		}
+
+		memo, ok := memos[next.counts]
+		if !ok {
+			memo = make(map[handState]float64)
+			memos[next.counts] = memo
+		}
+		return s.ev.freeHandEV(state, next.probabilities(), dealer, memo)
+	})
+	return s.ev.freeSplitEV(paid, free, hands)
+}
+
+// basicStrategyKey 总点数基本策略的决策单位：点数、软硬、对子点数（非对子为0）与庄家明牌
+type basicStrategyKey struct {
+	total int
+	soft  bool
+	pair  int
+	up    int
+}
+
+// firstHand 首手的一种玩家两张牌与庄家明牌组合
+type firstHand struct {
+	first, second int
+	up            int
+	prob          float64
+	evs           *ActionEV
+}
+
+// CompareStrategies 枚举完整牌靴首手的所有玩家两张牌与庄家明牌，比较组成相关最优决策与总点数基本策略：
+// 统计决策不同的局面出现的概率，以及按组成相关策略决策每手多得的期望值
+// 基本策略对同一点数、软硬、对子与庄家明牌只取一个操作：按各组成出现的概率加权期望值最高的操作，
+// 再用组成相关求解器评估这个固定操作，因此首个决策的组成偏差也计入比较
+// 10点对子一律按可分牌计算（分10点牌几乎从不是最优操作）
+func CompareStrategies(rules entities.Rules) (*dtos.StrategyComparisonDTO, error) {
+	if rules.Pontoon || rules.DoubleExposure {
+		return nil, errors.New("strategy comparison needs exactly one dealer upcard")
+	}
+
+	ev := NewEVCalculator(rules)
+	shoe := freshShoeComposition(rules)
+	comparison := &dtos.StrategyComparisonDTO{}
+
+	var hands []firstHand
+	basicEVs := make(map[basicStrategyKey]*ActionEV)
+	for up := aceValue; up <= tenValue; up++ {
+		solver := newCompositionSolver(ev, up)
+		for first := aceValue; first <= tenValue; first++ {
+			for second := first; second <= tenValue; second++ {
+				if first == aceValue && second == tenValue {
+					// 玩家Blackjack没有决策
+					continue
+				}
+				prob, remaining := drawProbability(shoe, first, second, up)
+				if prob == 0 {
+					continue
+				}
+				if first != second {
+					prob *= 2
+				}
+				if rules.HoleCard == entities.HoleCardPeek {
+					// 庄家Blackjack在玩家决策前已结算
+					prob *= 1 - ev.dealerOutcomes(up, remaining).blackjack
+				}
+
+				cards := []entities.Card{cardOfPoint(first), cardOfPoint(second)}
+				hand := firstHand{first: first, second: second, up: up, prob: prob}
+				hand.evs = solver.actionEVs(cards, remaining, true, first == second, true)
+				hands = append(hands, hand)
+
+				key := basicKey(hand)
+				if basicEVs[key] == nil {
+					basicEVs[key] = &ActionEV{}
+				}
+				addWeightedEVs(basicEVs[key], hand.evs, prob)
+			}
+		}
+	}
+
+	for _, hand := range hands {
+		comparison.Situations++
+
+		basic, _ := basicEVs[basicKey(hand)].Best()
+		best, bestEV := hand.evs.Best()
+		if best == basic {
+			continue
+		}
+
+		basicEV, _ := hand.evs.ValueOf(basic)
+		gain := bestEV - basicEV
+		comparison.DifferenceRate += hand.prob
+		comparison.EVGain += hand.prob * gain
+		comparison.Differences = append(comparison.Differences, &dtos.StrategyDifferenceDTO{
+			PlayerCards:       []string{entities.Rank(hand.first).String(), entities.Rank(hand.second).String()},
+			DealerUpCard:      entities.Rank(hand.up).String(),
+			BasicAction:       basic,
+			CompositionAction: best,
+			Probability:       hand.prob,
+			Gain:              gain,
+		})
+	}
+
+	slices.SortFunc(comparison.Differences, func(a, b *dtos.StrategyDifferenceDTO) int {
+		return cmp.Compare(b.Probability*b.Gain, a.Probability*a.Gain)
+	})
+	return comparison, nil
+}
+
+// basicKey 首手所属的总点数基本策略决策单位
+func basicKey(hand firstHand) basicStrategyKey {
+	total, soft := handState{}.draw(hand.first).draw(hand.second).total()
+	key := basicStrategyKey{total: total, soft: soft, up: hand.up}
+	if hand.first == hand.second {
+		key.pair = hand.first
+	}
+	return key
+}
+
+// addWeightedEVs 将一手牌各操作的期望值按权重累加（同一决策单位的手牌可用的操作相同）
+func addWeightedEVs(sum, evs *ActionEV, weight float64) {
+	sum.Stand += weight * evs.Stand
+	sum.Hit += weight * evs.Hit
+	sum.Double += weight * evs.Double
+	sum.Split += weight * evs.Split
+	sum.Surrender += weight * evs.Surrender
+	sum.CanStand, sum.CanDouble, sum.CanSplit, sum.CanSurrender = evs.CanStand, evs.CanDouble, evs.CanSplit, evs.CanSurrender
+}
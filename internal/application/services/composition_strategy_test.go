@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestCompositionDependentDecisions 测试组成相关策略按手牌组成区分点数相同的手牌
+func TestCompositionDependentDecisions(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	ev := NewEVCalculator(rules)
+
+	tests := []struct {
+		name     string
+		player   []entities.Rank
+		dealerUp entities.Rank
+		basic    entities.PlayerAction
+		expected entities.PlayerAction
+	}{
+		{"ten_two_vs_4_hit", []entities.Rank{entities.Ten, entities.Two}, entities.Four, entities.ActionStand, entities.ActionHit},
+		{"seven_five_vs_4_stand", []entities.Rank{entities.Seven, entities.Five}, entities.Four, entities.ActionStand, entities.ActionStand},
+		{"hard_16_vs_10_hit", []entities.Rank{entities.Ten, entities.Six}, entities.King, entities.ActionHit, entities.ActionHit},
+		{"hard_11_vs_6_double", []entities.Rank{entities.Six, entities.Five}, entities.Six, entities.ActionDoubleDown, entities.ActionDoubleDown},
+		{"eights_vs_6_split", []entities.Rank{entities.Eight, entities.Eight}, entities.Six, entities.ActionSplit, entities.ActionSplit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			player := cardsOf(tt.player...)
+			upCard := entities.Card{Rank: tt.dealerUp}
+			shoe := rules.NewShoe()
+			for _, card := range append(slices.Clone(player), upCard) {
+				shoe.Remove(card)
+			}
+
+			basic := ev.CalculateActionEVs(player, upCard, shoe.Cards, true, true, true)
+			if action, _ := basic.Best(); action != tt.basic {
+				t.Errorf("Expected basic strategy action %v, got %v", tt.basic, action)
+			}
+			composition := ev.CalculateCompositionEVs(player, upCard, shoe.Cards, true, true, true)
+			if action, _ := composition.Best(); action != tt.expected {
+				t.Errorf("Expected composition action %v, got %v (evs %+v)", tt.expected, action, *composition)
+			}
+
+			// 停牌时不再抽牌，两种计算的庄家结果相同
+			if math.Abs(basic.Stand-composition.Stand) > 1e-12 {
+				t.Errorf("Expected equal stand EVs, got %f and %f", basic.Stand, composition.Stand)
+			}
+		})
+	}
+}
+
+// TestCompareStrategies 测试单副牌首手的策略比较：基本策略按点数只取一个操作，
+// 组成相关策略应找出单副牌已知的首个决策偏差
+func TestCompareStrategies(t *testing.T) {
+	t.Parallel()
+
+	comparison, err := CompareStrategies(entities.DefaultRules())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if comparison.Situations != 540 {
+		t.Errorf("Expected 540 situations without player blackjacks, got %d", comparison.Situations)
+	}
+
+	tests := []struct {
+		name        string
+		cards       []string
+		up          string
+		basic       entities.PlayerAction
+		composition entities.PlayerAction
+	}{
+		{"10-2 vs 4", []string{"2", "10"}, "4", entities.ActionStand, entities.ActionHit},
+		{"10-3 vs 2", []string{"3", "10"}, "2", entities.ActionStand, entities.ActionHit},
+		{"6-2 vs 6", []string{"2", "6"}, "6", entities.ActionDoubleDown, entities.ActionHit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			found := slices.ContainsFunc(comparison.Differences, func(d *dtos.StrategyDifferenceDTO) bool {
+				return slices.Equal(d.PlayerCards, tt.cards) && d.DealerUpCard == tt.up &&
+					d.BasicAction == tt.basic && d.CompositionAction == tt.composition
+			})
+			if !found {
+				t.Errorf("Expected basic strategy to %v and composition strategy to %v", tt.basic, tt.composition)
+			}
+		})
+	}
+
+	rate, gain := 0.0, 0.0
+	for _, difference := range comparison.Differences {
+		if difference.Gain <= 0 {
+			t.Errorf("Expected a positive gain for %v vs %s, got %f", difference.PlayerCards, difference.DealerUpCard, difference.Gain)
+		}
+		rate += difference.Probability
+		gain += difference.Probability * difference.Gain
+	}
+	if math.Abs(rate-comparison.DifferenceRate) > 1e-12 || math.Abs(gain-comparison.EVGain) > 1e-12 {
+		t.Errorf("Expected totals %f and %f to match the differences, got %f and %f",
+			rate, gain, comparison.DifferenceRate, comparison.EVGain)
+	}
+
+	pontoon := entities.VariantRules(entities.VariantPontoon)
+	if _, err := CompareStrategies(pontoon); err == nil {
+		t.Error("Expected an error for Pontoon, whose dealer cards are both hidden")
+	}
+}
+
+// TestStrategySource 测试推荐操作来源
+func TestStrategySource(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	scenario := &entities.Scenario{Rules: rules, Cards: cardsOf(entities.Ten, entities.Four, entities.Two, entities.Ten)}
+	service := NewGameApplicationService("test", entities.WithScenario(scenario))
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+
+	if service.GetStrategyEVs(StrategySimulation) != nil {
+		t.Error("Expected no EVs for the simulation source")
+	}
+	if action, _ := service.GetStrategyEVs(StrategyBasic).Best(); action != entities.ActionStand {
+		t.Errorf("Expected basic strategy to stand on 10-2 vs 4, got %v", action)
+	}
+
+	service.SetStrategySource(StrategyComposition)
+	analysis := service.CalculateWinProbabilities().ActionAnalysis
+	if analysis.RecommendedAction != "hit" || analysis.StrategySource != "composition" {
+		t.Errorf("Expected the composition strategy to recommend hit, got %q from %q",
+			analysis.RecommendedAction, analysis.StrategySource)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// CountingSystem 算牌系统
+type CountingSystem struct {
+	Name     string
+	Balanced bool              // 平衡系统（一副牌计完为0，可换算真数）
+	Tags     [tenValue + 1]int // 按点数下标的计数值，1为A
+}
+
+// CountingSystems 支持的算牌系统
+var CountingSystems = []CountingSystem{
+	{Name: "Hi-Lo", Balanced: true, Tags: [tenValue + 1]int{0, -1, 1, 1, 1, 1, 1, 0, 0, 0, -1}},
+	{Name: "KO", Balanced: false, Tags: [tenValue + 1]int{0, -1, 1, 1, 1, 1, 1, 1, 0, 0, -1}},
+	{Name: "Hi-Opt I", Balanced: true, Tags: [tenValue + 1]int{0, 0, 0, 1, 1, 1, 1, 0, 0, 0, -1}},
+	{Name: "Hi-Opt II", Balanced: true, Tags: [tenValue + 1]int{0, 0, 1, 1, 2, 2, 1, 1, 0, 0, -2}},
+	{Name: "Omega II", Balanced: true, Tags: [tenValue + 1]int{0, 0, 1, 1, 2, 2, 2, 1, 0, -1, -2}},
+	{Name: "Zen", Balanced: true, Tags: [tenValue + 1]int{0, -1, 1, 1, 2, 2, 2, 1, 0, 0, -2}},
+}
+
+// FindCountingSystem 按名称查找算牌系统（不区分大小写）
+func FindCountingSystem(name string) (CountingSystem, error) {
+	for _, system := range CountingSystems {
+		if strings.EqualFold(system.Name, name) {
+			return system, nil
+		}
+	}
+	return CountingSystem{}, fmt.Errorf("unknown counting system %q", name)
+}
+
+// Tag 返回卡牌的计数值
+func (s CountingSystem) Tag(card entities.Card) int {
+	return s.Tags[cardPoint(card)]
+}
+
+// CountingDrillConfig 算牌练习配置
+type CountingDrillConfig struct {
+	System           CountingSystem
+	DeckCount        int
+	DealInterval     time.Duration // 发牌间隔
+	CardsPerQuestion int           // 每发多少张牌提问一次
+	Penetration      float64       // 发牌深度（占整个牌靴的比例）
+}
+
+// CountingQuestion 一次提问的结果
+type CountingQuestion struct {
+	CardsDealt      int
+	RunningCount    int
+	TrueCount       float64
+	AnsweredRunning int
+	AnsweredTrue    float64
+	TrueCountAsked  bool
+	RunningCorrect  bool
+	TrueCorrect     bool
+	ResponseTime    time.Duration
+}
+
+// IsCorrect 本次回答是否完全正确
+func (q CountingQuestion) IsCorrect() bool {
+	return q.RunningCorrect && (!q.TrueCountAsked || q.TrueCorrect)
+}
+
+// CountingSession 一次练习的汇总
+type CountingSession struct {
+	System         string
+	DeckCount      int
+	StartedAt      time.Time
+	CardsDealt     int
+	DealInterval   time.Duration
+	Questions      int
+	CorrectAnswers int
+	AverageAnswer  time.Duration
+}
+
+// CardsPerMinute 发牌速度（张/分钟）
+func (s CountingSession) CardsPerMinute() float64 {
+	if s.DealInterval <= 0 {
+		return 0
+	}
+	return float64(time.Minute) / float64(s.DealInterval)
+}
+
+// Accuracy 回答正确率
+func (s CountingSession) Accuracy() float64 {
+	if s.Questions == 0 {
+		return 0
+	}
+	return float64(s.CorrectAnswers) / float64(s.Questions)
+}
+
+// CountingDrill 算牌练习：按配置速度从牌靴发牌，定期询问流水数与真数
+type CountingDrill struct {
+	config       CountingDrillConfig
+	deck         *entities.Deck
+	cutIndex     int // 发到第几张牌后结束
+	runningCount int
+	cardsDealt   int
+	startedAt    time.Time
+	askedAt      time.Time
+	questions    []CountingQuestion
+}
+
+// NewCountingDrill 创建算牌练习
+func NewCountingDrill(config CountingDrillConfig) *CountingDrill {
+	config.DeckCount = max(config.DeckCount, 1)
+	if config.CardsPerQuestion <= 0 {
+		config.CardsPerQuestion = 10
+	}
+	if config.Penetration <= 0 || config.Penetration > 1 {
+		config.Penetration = 0.75
+	}
+
+	deck := entities.NewShoe(config.DeckCount)
+	return &CountingDrill{
+		config:    config,
+		deck:      deck,
+		cutIndex:  int(float64(len(deck.Cards)) * config.Penetration),
+		startedAt: time.Now(),
+	}
+}
+
+// Config 返回练习配置
+func (d *CountingDrill) Config() CountingDrillConfig {
+	return d.config
+}
+
+// DealNext 发下一张牌，返回是否到了提问时机
+func (d *CountingDrill) DealNext() (entities.Card, bool, error) {
+	if d.IsFinished() {
+		return entities.Card{}, false, errors.New("counting drill finished")
+	}
+
+	card, err := d.deck.Deal()
+	if err != nil {
+		return entities.Card{}, false, err
+	}
+
+	d.cardsDealt++
+	d.runningCount += d.config.System.Tag(card)
+
+	questionDue := d.cardsDealt%d.config.CardsPerQuestion == 0 || d.IsFinished()
+	if questionDue {
+		d.askedAt = time.Now()
+	}
+
+	return card, questionDue, nil
+}
+
+// IsFinished 是否已发到切牌位置
+func (d *CountingDrill) IsFinished() bool {
+	return d.cardsDealt >= d.cutIndex || len(d.deck.Cards) == 0
+}
+
+// CardsRemaining 剩余牌数
+func (d *CountingDrill) CardsRemaining() int {
+	return len(d.deck.Cards)
+}
+
+// TrueCount 当前真数（流水数除以剩余副数）
+func (d *CountingDrill) TrueCount() float64 {
+	decksRemaining := float64(len(d.deck.Cards)) / 52.0
+	if decksRemaining <= 0 {
+		return float64(d.runningCount)
+	}
+	return float64(d.runningCount) / decksRemaining
+}
+
+// Answer 记录回答；不平衡系统不考真数
+// 真数允许与精确值相差不到1（兼容取整与截断两种习惯）
+func (d *CountingDrill) Answer(running int, trueCount float64) CountingQuestion {
+	question := CountingQuestion{
+		CardsDealt:      d.cardsDealt,
+		RunningCount:    d.runningCount,
+		TrueCount:       d.TrueCount(),
+		AnsweredRunning: running,
+		AnsweredTrue:    trueCount,
+		TrueCountAsked:  d.config.System.Balanced,
+		RunningCorrect:  running == d.runningCount,
+	}
+	if question.TrueCountAsked {
+		question.TrueCorrect = math.Abs(trueCount-question.TrueCount) < 1
+	}
+	if !d.askedAt.IsZero() {
+		question.ResponseTime = time.Since(d.askedAt)
+	}
+
+	d.questions = append(d.questions, question)
+	return question
+}
+
+// Summary 汇总本次练习
+func (d *CountingDrill) Summary() CountingSession {
+	session := CountingSession{
+		System:       d.config.System.Name,
+		DeckCount:    d.config.DeckCount,
+		StartedAt:    d.startedAt,
+		CardsDealt:   d.cardsDealt,
+		DealInterval: d.config.DealInterval,
+		Questions:    len(d.questions),
+	}
+
+	var totalResponse time.Duration
+	for _, question := range d.questions {
+		if question.IsCorrect() {
+			session.CorrectAnswers++
+		}
+		totalResponse += question.ResponseTime
+	}
+	if session.Questions > 0 {
+		session.AverageAnswer = totalResponse / time.Duration(session.Questions)
+	}
+
+	return session
+}
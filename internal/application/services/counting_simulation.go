@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 真数区间的上下限，超出的局面并入两端
+const maxSimulatedTrueCount = 6
+
+// 模拟中玩家的筹码，足够覆盖任何一局的下注
+const simulationChips = 1 << 30
+
+// CountingSimulationParams 算牌优势模拟参数
+type CountingSimulationParams struct {
+	System CountingSystem
+	Rounds int // 模拟局数
+	Spread int // 最大下注单位：真数每高1点多下1单位注码
+}
+
+// DefaultCountingSimulationParams 默认算牌优势模拟参数：Hi-Lo，1-8倍注码，20万局
+func DefaultCountingSimulationParams() CountingSimulationParams {
+	return CountingSimulationParams{
+		System: CountingSystems[0],
+		Rounds: 200000,
+		Spread: 8,
+	}
+}
+
+// validate 按牌桌规则检查参数
+func (p CountingSimulationParams) validate(rules entities.Rules) error {
+	switch {
+	case p.Rounds <= 0:
+		return errors.New("rounds must be positive")
+	case p.Spread < 1:
+		return errors.New("bet spread must be at least 1")
+	case rules.MaxBet > 0 && rules.MinBet*p.Spread > rules.MaxBet:
+		return errors.New("bet spread exceeds the table maximum")
+	}
+	return nil
+}
+
+// betUnits 按真数计算下注单位
+func (p CountingSimulationParams) betUnits(trueCount float64) int {
+	return min(max(int(math.Floor(trueCount)), 1), p.Spread)
+}
+
+// strategyKey 基本策略缓存的键
+type strategyKey struct {
+	state        handState
+	pair         bool
+	up           int // 庄家明牌点数，Pontoon没有明牌时为0
+	canDouble    bool
+	canSplit     bool
+	canSurrender bool
+}
+
+// CountingSimulator 算牌优势模拟器：用基本策略逐局打牌，按下注前的真数统计每局输赢
+type CountingSimulator struct {
+	rules    entities.Rules
+	ev       *EVCalculator
+	strategy map[strategyKey]entities.PlayerAction
+}
+
+// NewCountingSimulator 创建算牌优势模拟器
+func NewCountingSimulator(rules entities.Rules) *CountingSimulator {
+	return &CountingSimulator{
+		rules:    rules,
+		ev:       NewEVCalculator(rules),
+		strategy: make(map[strategyKey]entities.PlayerAction),
+	}
+}
+
+// trueCountTally 单个真数区间的累计
+type trueCountTally struct {
+	rounds int
+	result float64
+}
+
+// Simulate 模拟指定局数，比较平注期望与按真数加注的期望
+// 计数者按普通牌靴计数：换新牌靴或发到切牌位置时清零；
+// 连续洗牌机每局把弃牌放回，计数对后续的牌没有预测力
+func (s *CountingSimulator) Simulate(params CountingSimulationParams) (*dtos.CountingSimulationDTO, error) {
+	if err := params.validate(s.rules); err != nil {
+		return nil, err
+	}
+
+	game := entities.NewGame("simulator", entities.WithRules(s.rules))
+	hands := s.rules.HandsPerRound()
+	shoeSize := len(game.Deck.Cards)
+	cutCards := shoeSize - entities.CutCardReserve*s.rules.DeckCount
+	tallies := make(map[int]*trueCountTally)
+	runningCount, cardsSeen := 0, 0
+	var flatTotal, spreadTotal, unitsTotal float64
+
+	for range params.Rounds {
+		deck := game.Deck
+		if err := game.StartNewRound(); err != nil {
+			return nil, err
+		}
+		if game.Deck != deck || cardsSeen >= cutCards {
+			runningCount, cardsSeen = 0, 0
+		}
+
+		// 平衡系统换算为真数，不平衡系统直接按流水数下注
+		trueCount := float64(runningCount)
+		if params.System.Balanced {
+			trueCount /= float64(shoeSize-cardsSeen) / 52
+		}
+		units := params.betUnits(trueCount)
+		bet := s.rules.MinBet * units
+
+		game.Player.Chips = simulationChips
+		if err := s.playRound(game, bet); err != nil {
+			return nil, err
+		}
+
+		// 每单位注码的输赢
+		result := float64(game.Player.Chips-simulationChips) / float64(bet*hands)
+		flatTotal += result
+		spreadTotal += result * float64(units)
+		unitsTotal += float64(units)
+
+		bucket := min(max(int(math.Round(trueCount)), -maxSimulatedTrueCount), maxSimulatedTrueCount)
+		if tallies[bucket] == nil {
+			tallies[bucket] = &trueCountTally{}
+		}
+		tallies[bucket].rounds++
+		tallies[bucket].result += result
+
+		for _, card := range game.GetUsedCards() {
+			runningCount += params.System.Tag(card)
+			cardsSeen++
+		}
+	}
+
+	simulation := &dtos.CountingSimulationDTO{
+		System:            params.System.Name,
+		ContinuousShuffle: s.rules.ContinuousShuffle,
+		Rounds:            params.Rounds,
+		Spread:            params.Spread,
+		FlatEV:            flatTotal / float64(params.Rounds),
+		SpreadEV:          spreadTotal / unitsTotal,
+		AverageBet:        unitsTotal / float64(params.Rounds),
+	}
+	buckets := make([]int, 0, len(tallies))
+	for bucket := range tallies {
+		buckets = append(buckets, bucket)
+	}
+	slices.Sort(buckets)
+	for _, bucket := range buckets {
+		tally := tallies[bucket]
+		simulation.TrueCounts = append(simulation.TrueCounts, &dtos.TrueCountResultDTO{
+			TrueCount: bucket,
+			Rounds:    tally.rounds,
+			EV:        tally.result / float64(tally.rounds),
+		})
+	}
+
+	return simulation, nil
+}
+
+// playRound 下注、发牌并按基本策略打完一局
+func (s *CountingSimulator) playRound(game *entities.Game, bet int) error {
+	if err := game.PlaceBet(bet); err != nil {
+		return err
+	}
+	if err := game.DealInitialCards(); err != nil {
+		return err
+	}
+
+	for game.State == entities.StatePlayerTurn {
+		if err := s.playAction(game, s.strategyAction(game)); err != nil {
+			// 规则不允许该操作时退回停牌或要牌
+			if !game.CanPlayerStand() {
+				_, err = game.PlayerHit()
+			} else {
+				err = game.PlayerStand()
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := game.DealerTurn(); err != nil {
+		return err
+	}
+	game.EvaluateResult()
+	return nil
+}
+
+// playAction 执行玩家操作
+func (s *CountingSimulator) playAction(game *entities.Game, action entities.PlayerAction) error {
+	var err error
+	switch action {
+	case entities.ActionHit:
+		_, err = game.PlayerHit()
+	case entities.ActionDoubleDown:
+		_, err = game.PlayerDoubleDown(game.MaxDoubleAmount())
+	case entities.ActionSplit:
+		err = game.PlayerSplit()
+	case entities.ActionSurrender:
+		err = game.PlayerSurrender()
+	default:
+		err = game.PlayerStand()
+	}
+	return err
+}
+
+// strategyAction 当前手牌的基本策略操作，按点数状态缓存；Pontoon玩家看不到庄家的牌，按没有明牌的策略决策
+func (s *CountingSimulator) strategyAction(game *entities.Game) entities.PlayerAction {
+	hand := game.Player.Hand
+	key := strategyKey{
+		state:        newHandState(hand.Cards),
+		pair:         len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank,
+		canDouble:    game.CanPlayerDoubleDown(),
+		canSplit:     game.CanPlayerSplit(),
+		canSurrender: game.CanPlayerSurrender(),
+	}
+	if !s.rules.Pontoon {
+		key.up = cardPoint(game.Dealer.Hand.Cards[0])
+	}
+	if action, ok := s.strategy[key]; ok {
+		return action
+	}
+
+	var evs *ActionEV
+	if s.rules.Pontoon {
+		evs = s.ev.CalculateHiddenDealerEVs(hand.Cards, key.canDouble, key.canSplit, key.canSurrender)
+	} else {
+		evs = s.ev.CalculateBasicStrategyEVs(hand.Cards, game.Dealer.Hand.Cards[0], key.canDouble, key.canSplit, key.canSurrender)
+	}
+	action, _ := evs.Best()
+	s.strategy[key] = action
+	return action
+}
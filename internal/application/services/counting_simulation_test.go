@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+func TestCountingSimulation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		continuous bool
+	}{
+		{name: "cut card shoe", continuous: false},
+		{name: "continuous shuffler", continuous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.ContinuousShuffle = tt.continuous
+			result, err := NewCountingSimulator(rules).Simulate(DefaultCountingSimulationParams())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			rounds := 0
+			for _, trueCount := range result.TrueCounts {
+				rounds += trueCount.Rounds
+			}
+			if rounds != result.Rounds {
+				t.Errorf("Expected true count buckets to cover %d rounds, got %d", result.Rounds, rounds)
+			}
+
+			// 单副牌1-8加注的优势约2%；连续洗牌机下加注与平注的期望只差模拟误差
+			gain := result.SpreadEV - result.FlatEV
+			if !tt.continuous && gain < 0.01 {
+				t.Errorf("Expected counting to gain at least 1%% with a cut card, got %+.3f%%", gain*100)
+			}
+			if tt.continuous && math.Abs(gain) > 0.012 {
+				t.Errorf("Expected counting to gain nothing against a continuous shuffler, got %+.3f%%", gain*100)
+			}
+		})
+	}
+}
+
+func TestCountingSimulationValidation(t *testing.T) {
+	t.Parallel()
+
+	params := DefaultCountingSimulationParams()
+	params.Spread = 100
+	if _, err := NewCountingSimulator(entities.DefaultRules()).Simulate(params); err == nil {
+		t.Error("Expected a spread above the table maximum to be rejected")
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestCountingSystemsBalance 测试平衡系统一副牌计完为0，不平衡系统不为0
+func TestCountingSystemsBalance(t *testing.T) {
+	t.Parallel()
+
+	deck := entities.NewShoe(1)
+	for _, system := range CountingSystems {
+		t.Run(system.Name, func(t *testing.T) {
+			t.Parallel()
+
+			sum := 0
+			for _, card := range deck.Cards {
+				sum += system.Tag(card)
+			}
+			if system.Balanced && sum != 0 {
+				t.Errorf("Expected balanced count 0, got %d", sum)
+			}
+			if !system.Balanced && sum == 0 {
+				t.Error("Expected unbalanced count, got 0")
+			}
+		})
+	}
+}
+
+// TestFindCountingSystem 测试按名称查找算牌系统
+func TestFindCountingSystem(t *testing.T) {
+	t.Parallel()
+
+	system, err := FindCountingSystem("hi-lo")
+	if err != nil || system.Name != "Hi-Lo" {
+		t.Fatalf("Expected Hi-Lo, got %+v (%v)", system, err)
+	}
+	if _, err := FindCountingSystem("unknown"); err == nil {
+		t.Error("Expected error for unknown counting system")
+	}
+}
+
+// TestCountingDrill 测试发牌计数、提问节奏与答题评分
+func TestCountingDrill(t *testing.T) {
+	t.Parallel()
+
+	system, _ := FindCountingSystem("Hi-Lo")
+	drill := NewCountingDrill(CountingDrillConfig{System: system, DeckCount: 2, CardsPerQuestion: 5})
+
+	expected := 0
+	for i := 1; i <= 5; i++ {
+		card, questionDue, err := drill.DealNext()
+		if err != nil {
+			t.Fatalf("Unexpected deal error: %v", err)
+		}
+		expected += system.Tag(card)
+		if questionDue != (i == 5) {
+			t.Errorf("Card %d: unexpected questionDue %v", i, questionDue)
+		}
+	}
+
+	correct := drill.Answer(expected, drill.TrueCount()+0.5)
+	if !correct.IsCorrect() {
+		t.Errorf("Expected correct answer within tolerance, got %+v", correct)
+	}
+
+	wrong := drill.Answer(expected+1, drill.TrueCount())
+	if wrong.IsCorrect() || wrong.RunningCorrect {
+		t.Errorf("Expected wrong running count to be graded incorrect, got %+v", wrong)
+	}
+
+	summary := drill.Summary()
+	if summary.Questions != 2 || summary.CorrectAnswers != 1 || summary.Accuracy() != 0.5 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+// TestCountingDrillFinishes 测试发到切牌位置后结束
+func TestCountingDrillFinishes(t *testing.T) {
+	t.Parallel()
+
+	drill := NewCountingDrill(CountingDrillConfig{System: CountingSystems[0], DeckCount: 1, Penetration: 0.5})
+	for !drill.IsFinished() {
+		if _, _, err := drill.DealNext(); err != nil {
+			t.Fatalf("Unexpected deal error: %v", err)
+		}
+	}
+
+	if drill.CardsRemaining() != 26 {
+		t.Errorf("Expected 26 cards remaining, got %d", drill.CardsRemaining())
+	}
+	if _, _, err := drill.DealNext(); err == nil {
+		t.Error("Expected error after drill finished")
+	}
+}
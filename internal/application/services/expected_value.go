@@ -0,0 +1,849 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 牌点下标：1为A，10为所有10点牌
+const (
+	aceValue     = 1
+	tenValue     = 10
+	dealerStands = 17
+)
+
+// shoeComposition 按点数统计的牌堆组成
+type shoeComposition struct {
+	counts   [tenValue + 1]int
+	total    int
+	infinite bool // 无限副牌：抽牌不改变组成，各点数始终按固定概率抽取
+}
+
+// newShoeComposition 根据卡牌列表统计牌堆组成
+func newShoeComposition(cards []entities.Card) shoeComposition {
+	var shoe shoeComposition
+	for _, card := range cards {
+		shoe.add(cardPoint(card))
+	}
+	return shoe
+}
+
+// freshShoeComposition 按规则的完整牌靴组成（西班牙牌每副少4张10点牌）
+func freshShoeComposition(rules entities.Rules) shoeComposition {
+	var shoe shoeComposition
+	deckCount := max(rules.DeckCount, 1)
+	for value := aceValue; value < tenValue; value++ {
+		shoe.counts[value] = 4 * deckCount
+	}
+	shoe.counts[tenValue] = 16 * deckCount
+	if rules.SpanishDeck {
+		shoe.counts[tenValue] = 12 * deckCount
+	}
+	shoe.total = 36*deckCount + shoe.counts[tenValue]
+	return shoe
+}
+
+// infiniteShoeComposition 无限副牌组成：各点数按一副完整牌中的比例抽取，移除牌不改变组成
+func infiniteShoeComposition(rules entities.Rules) shoeComposition {
+	rules.DeckCount = 1
+	shoe := freshShoeComposition(rules)
+	shoe.infinite = true
+	return shoe
+}
+
+// add 向牌堆加入一张牌
+func (s *shoeComposition) add(value int) {
+	s.counts[value]++
+	s.total++
+}
+
+// remove 从牌堆移除一张牌（牌堆中没有该点数或无限副牌时忽略）
+func (s *shoeComposition) remove(value int) {
+	if s.infinite || s.counts[value] == 0 {
+		return
+	}
+	s.counts[value]--
+	s.total--
+}
+
+// probabilities 各点数的抽取概率
+func (s shoeComposition) probabilities() [tenValue + 1]float64 {
+	var probs [tenValue + 1]float64
+	if s.total == 0 {
+		return probs
+	}
+	for value := aceValue; value <= tenValue; value++ {
+		probs[value] = float64(s.counts[value]) / float64(s.total)
+	}
+	return probs
+}
+
+// cardPoint 卡牌的点数下标（A为1）
+func cardPoint(card entities.Card) int {
+	if card.IsAce() {
+		return aceValue
+	}
+	return card.BaseValue()
+}
+
+// handState 按点数表示的手牌状态
+type handState struct {
+	hard   int  // A按1点计算的硬点数
+	hasAce bool // 是否含A
+	cards  int  // 牌数
+	trio   int  // 前三张牌中6、7、8的组合，用于6-7-8与7-7-7奖励；出现其他牌后为0
+}
+
+// 6-7-8与7-7-7奖励的牌型位：每张6、8各占一位，7按出现顺序占三位
+const (
+	trioSix      = 1
+	trioSeven    = 2
+	trioEight    = 4
+	trioSeven2   = 8
+	trioSeven3   = 16
+	trioSixEight = trioSix | trioSeven | trioEight
+	trioSevens   = trioSeven | trioSeven2 | trioSeven3
+)
+
+// newHandState 根据卡牌创建手牌状态
+func newHandState(cards []entities.Card) handState {
+	var state handState
+	for _, card := range cards {
+		state = state.draw(cardPoint(card))
+	}
+	return state
+}
+
+// draw 加入一张牌
+func (h handState) draw(value int) handState {
+	return handState{
+		hard:   h.hard + value,
+		hasAce: h.hasAce || value == aceValue,
+		cards:  h.cards + 1,
+		trio:   h.nextTrio(value),
+	}
+}
+
+// nextTrio 加入一张牌后的6-7-8与7-7-7牌型位
+func (h handState) nextTrio(value int) int {
+	if h.cards >= 3 || (h.cards > 0 && h.trio == 0) {
+		return 0
+	}
+
+	var bit int
+	switch {
+	case value == 6:
+		bit = trioSix
+	case value == 8:
+		bit = trioEight
+	case value == 7 && h.trio&trioSeven == 0:
+		bit = trioSeven
+	case value == 7 && h.trio&trioSeven2 == 0:
+		bit = trioSeven2
+	case value == 7:
+		bit = trioSeven3
+	}
+	if bit == 0 || h.trio&bit != 0 {
+		return 0
+	}
+	return h.trio | bit
+}
+
+// total 最优点数及是否为软牌
+func (h handState) total() (int, bool) {
+	if h.hasAce && h.hard+10 <= 21 {
+		return h.hard + 10, true
+	}
+	return h.hard, false
+}
+
+// dealerOutcome 庄家最终结果分布
+type dealerOutcome struct {
+	totals    [22]float64 // 下标为庄家停牌点数（17-21）
+	bust      float64
+	push22    float64 // 庄家22点平局规则下爆成22点的概率（不计入bust）
+	blackjack float64
+}
+
+// withoutBlackjack 排除庄家Blackjack后的条件分布
+func (d *dealerOutcome) withoutBlackjack() *dealerOutcome {
+	if d.blackjack == 0 || d.blackjack >= 1 {
+		return d
+	}
+
+	scale := 1 / (1 - d.blackjack)
+	conditional := &dealerOutcome{bust: d.bust * scale, push22: d.push22 * scale}
+	for total, p := range d.totals {
+		conditional.totals[total] = p * scale
+	}
+	return conditional
+}
+
+// ActionEV 各操作的期望值（以初始注码为单位）
+type ActionEV struct {
+	Stand     float64
+	Hit       float64
+	Double    float64
+	Split     float64
+	Surrender float64
+
+	CanStand     bool
+	CanDouble    bool
+	CanSplit     bool
+	CanSurrender bool
+}
+
+// Best 返回期望值最高的操作
+func (a *ActionEV) Best() (entities.PlayerAction, float64) {
+	bestAction := entities.ActionStand
+	bestValue := a.Stand
+	if !a.CanStand {
+		bestAction, bestValue = entities.ActionHit, a.Hit
+	}
+
+	if a.Hit > bestValue {
+		bestAction = entities.ActionHit
+		bestValue = a.Hit
+	}
+	if a.CanDouble && a.Double > bestValue {
+		bestAction = entities.ActionDoubleDown
+		bestValue = a.Double
+	}
+	if a.CanSplit && a.Split > bestValue {
+		bestAction = entities.ActionSplit
+		bestValue = a.Split
+	}
+	if a.CanSurrender && a.Surrender > bestValue {
+		bestAction = entities.ActionSurrender
+		bestValue = a.Surrender
+	}
+
+	return bestAction, bestValue
+}
+
+// ValueOf 返回指定操作的期望值
+func (a *ActionEV) ValueOf(action entities.PlayerAction) (float64, bool) {
+	switch action {
+	case entities.ActionStand:
+		return a.Stand, a.CanStand
+	case entities.ActionHit:
+		return a.Hit, true
+	case entities.ActionDoubleDown:
+		return a.Double, a.CanDouble
+	case entities.ActionSplit:
+		return a.Split, a.CanSplit
+	case entities.ActionSurrender:
+		return a.Surrender, a.CanSurrender
+	default:
+		return 0, false
+	}
+}
+
+// EVCalculator 期望值计算器
+// 庄家结果按牌堆组成精确计算，玩家决策按点数（total-dependent）递归求最优
+type EVCalculator struct {
+	rules        entities.Rules
+	infiniteDeck bool // 无限副牌模式：各点数按固定概率抽取，与已发出的牌无关
+}
+
+// EVOption is a function type for configuring the expected value calculator
+type EVOption func(ev *EVCalculator)
+
+// WithEVInfiniteDeck computes every expected value with an infinite deck, where each rank keeps its fresh-deck probability
+func WithEVInfiniteDeck() EVOption {
+	return func(ev *EVCalculator) {
+		ev.infiniteDeck = true
+	}
+}
+
+// NewEVCalculator 创建期望值计算器
+func NewEVCalculator(rules entities.Rules, options ...EVOption) *EVCalculator {
+	ev := &EVCalculator{
+		rules: rules,
+	}
+
+	for _, option := range options {
+		option(ev)
+	}
+
+	return ev
+}
+
+// fullShoe 完整牌靴组成，无限副牌模式下为固定概率的组成
+func (ev *EVCalculator) fullShoe() shoeComposition {
+	if ev.infiniteDeck {
+		return infiniteShoeComposition(ev.rules)
+	}
+	return freshShoeComposition(ev.rules)
+}
+
+// remainingShoe 剩余牌堆组成，无限副牌模式下忽略实际剩余的牌
+func (ev *EVCalculator) remainingShoe(remainingCards []entities.Card) shoeComposition {
+	if ev.infiniteDeck {
+		return ev.fullShoe()
+	}
+	return newShoeComposition(remainingCards)
+}
+
+// CalculateActionEVs 计算当前局面下各操作的期望值
+// remainingCards 为玩家未知的牌（包括庄家底牌所在的牌堆）
+func (ev *EVCalculator) CalculateActionEVs(
+	playerCards []entities.Card,
+	dealerUpCard entities.Card,
+	remainingCards []entities.Card,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *ActionEV {
+	return ev.actionEVs(playerCards, dealerUpCard, ev.remainingShoe(remainingCards), canDouble, canSplit, canSurrender)
+}
+
+// CalculateBasicStrategyEVs 以完整牌靴（仅移除可见牌）计算基本策略下各操作的期望值
+func (ev *EVCalculator) CalculateBasicStrategyEVs(
+	playerCards []entities.Card,
+	dealerUpCard entities.Card,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *ActionEV {
+	shoe := ev.fullShoe()
+	for _, card := range playerCards {
+		shoe.remove(cardPoint(card))
+	}
+	shoe.remove(cardPoint(dealerUpCard))
+
+	return ev.actionEVs(playerCards, dealerUpCard, shoe, canDouble, canSplit, canSurrender)
+}
+
+// CalculateHiddenDealerEVs 庄家没有明牌（Pontoon）时以完整牌靴（仅移除玩家的牌）计算各操作的期望值，
+// 庄家结果按所有可能的明牌混合
+func (ev *EVCalculator) CalculateHiddenDealerEVs(playerCards []entities.Card, canDouble, canSplit, canSurrender bool) *ActionEV {
+	shoe := ev.fullShoe()
+	for _, card := range playerCards {
+		shoe.remove(cardPoint(card))
+	}
+	return ev.actionEVsAgainst(playerCards, ev.hiddenDealerOutcomes(shoe), shoe, canDouble, canSplit, canSurrender)
+}
+
+// CalculateSwitchEVs 换牌玩法两手牌保持原样与交换第二张牌后的期望值之和（完整牌靴仅移除可见牌）
+func (ev *EVCalculator) CalculateSwitchEVs(first, second []entities.Card, dealerUpCard entities.Card) (keep, switched float64) {
+	shoe := ev.fullShoe()
+	for _, card := range slices.Concat(first, second) {
+		shoe.remove(cardPoint(card))
+	}
+	up := cardPoint(dealerUpCard)
+	shoe.remove(up)
+
+	dealer := ev.dealerOutcomes(up, shoe)
+	a1, b1 := cardPoint(first[0]), cardPoint(first[1])
+	a2, b2 := cardPoint(second[0]), cardPoint(second[1])
+
+	keep = ev.openingHandEV(a1, b1, true, dealer, shoe) + ev.openingHandEV(a2, b2, true, dealer, shoe)
+	switched = ev.openingHandEV(a1, b2, false, dealer, shoe) + ev.openingHandEV(a2, b1, false, dealer, shoe)
+	return keep, switched
+}
+
+// actionEVs 计算各操作的期望值
+func (ev *EVCalculator) actionEVs(
+	playerCards []entities.Card,
+	dealerUpCard entities.Card,
+	shoe shoeComposition,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *ActionEV {
+	dealer := ev.dealerOutcomes(cardPoint(dealerUpCard), shoe)
+	return ev.actionEVsAgainst(playerCards, dealer, shoe, canDouble, canSplit, canSurrender)
+}
+
+// actionEVsAgainst 针对给定庄家结果分布计算各操作的期望值
+// 美式偷看规则下玩家行动时已知庄家没有Blackjack，按条件分布计算；
+// 欧式无底牌规则下庄家Blackjack赢走全部注码（OBO只赢原始注码）
+func (ev *EVCalculator) actionEVsAgainst(
+	playerCards []entities.Card,
+	dealer *dealerOutcome,
+	shoe shoeComposition,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *ActionEV {
+	noBlackjack := dealer.withoutBlackjack()
+	probs := shoe.probabilities()
+	state := newHandState(playerCards)
+	memo := make(map[handState]float64)
+
+	result := ev.availableActions(playerCards, canDouble, canSplit, canSurrender)
+	result.Stand = ev.standEV(state, noBlackjack)
+	result.Hit = ev.hitEV(state, probs, noBlackjack, memo)
+	if result.CanDouble {
+		result.Double = ev.doubleEV(state, probs, noBlackjack)
+	}
+	if result.CanSplit {
+		result.Split = ev.splitEV(cardPoint(playerCards[0]), shoe, noBlackjack)
+	}
+
+	return ev.weighDealerBlackjack(result, dealer.blackjack)
+}
+
+// availableActions 按规则与手牌确定可用的操作，投降期望值固定为输一半注码
+// 能否加倍、分牌、投降由调用方按牌局状态传入（如分牌后的手牌不能投降）
+func (ev *EVCalculator) availableActions(playerCards []entities.Card, canDouble, canSplit, canSurrender bool) *ActionEV {
+	state := newHandState(playerCards)
+	return &ActionEV{
+		CanStand:  ev.canStand(state),
+		CanDouble: canDouble && ev.canDouble(state),
+		CanSplit:  canSplit && len(playerCards) == 2 && playerCards[0].Rank == playerCards[1].Rank,
+		// 后投降只能在前两张牌时，输一半注码
+		CanSurrender: canSurrender && ev.rules.LateSurrender && len(playerCards) == 2,
+		Surrender:    -0.5,
+	}
+}
+
+// weighDealerBlackjack 欧式无底牌规则下按庄家Blackjack的概率折算各操作期望值（美式偷看规则下不变）
+func (ev *EVCalculator) weighDealerBlackjack(result *ActionEV, blackjack float64) *ActionEV {
+	if ev.rules.HoleCard == entities.HoleCardPeek || blackjack == 0 {
+		return result
+	}
+
+	extraBetLoss := -2.0
+	if ev.rules.HoleCard == entities.HoleCardENHCOBO {
+		extraBetLoss = -1
+	}
+	weight := func(value, loss float64) float64 {
+		return blackjack*loss + (1-blackjack)*value
+	}
+	result.Stand = weight(result.Stand, -1)
+	result.Hit = weight(result.Hit, -1)
+	result.Double = weight(result.Double, extraBetLoss)
+	result.Split = weight(result.Split, extraBetLoss)
+	result.Surrender = weight(result.Surrender, -1)
+
+	return result
+}
+
+// dealerOutcomes 计算庄家从明牌开始的最终结果分布（考虑牌堆减少）
+func (ev *EVCalculator) dealerOutcomes(upCard int, shoe shoeComposition) *dealerOutcome {
+	outcome := &dealerOutcome{}
+	ev.dealerDraw(handState{}.draw(upCard), shoe, 1.0, outcome)
+	return outcome
+}
+
+// dealerDraw 递归模拟庄家要牌
+func (ev *EVCalculator) dealerDraw(state handState, shoe shoeComposition, prob float64, outcome *dealerOutcome) {
+	total, soft := state.total()
+	hitsSoft17 := ev.rules.DealerHitsSoft17 && soft && total == dealerStands
+
+	switch {
+	case state.cards == 2 && total == 21:
+		outcome.blackjack += prob
+		return
+	case ev.rules.Dealer22Push && total == 22:
+		outcome.push22 += prob
+		return
+	case total > 21:
+		outcome.bust += prob
+		return
+	case total >= dealerStands && !hitsSoft17:
+		outcome.totals[total] += prob
+		return
+	case shoe.total == 0:
+		// 牌堆耗尽时按当前点数结算
+		outcome.totals[total] += prob
+		return
+	}
+
+	for value := aceValue; value <= tenValue; value++ {
+		count := shoe.counts[value]
+		if count == 0 {
+			continue
+		}
+		next := shoe
+		next.remove(value)
+		ev.dealerDraw(state.draw(value), next, prob*float64(count)/float64(shoe.total), outcome)
+	}
+}
+
+// standEV 停牌期望值（含西班牙21点多张21点奖励）
+func (ev *EVCalculator) standEV(state handState, dealer *dealerOutcome) float64 {
+	return ev.settleEV(state, dealer, ev.bonusPayout(state))
+}
+
+// settleEV 停牌结算的期望值，获胜时按payout倍赔付
+func (ev *EVCalculator) settleEV(state handState, dealer *dealerOutcome, payout float64) float64 {
+	win, lose := ev.settleOdds(state, dealer)
+	return payout*win - lose
+}
+
+// settleOdds 停牌结算的胜负概率（庄家22点平局计入两者之外）
+func (ev *EVCalculator) settleOdds(state handState, dealer *dealerOutcome) (win, lose float64) {
+	total, _ := state.total()
+	if total > 21 {
+		return 0, 1
+	}
+	if ev.isAutoWin(state) {
+		// 自动获胜只输给庄家Blackjack
+		return 1 - dealer.blackjack, dealer.blackjack
+	}
+
+	win, lose = dealer.bust, dealer.blackjack
+	// 庄家点数通常为17-21，牌堆耗尽时可能停在更低点数
+	for dealerTotal, p := range dealer.totals {
+		switch {
+		case total > dealerTotal:
+			win += p
+		case total < dealerTotal || ev.rules.DealerWinsTies():
+			// 双明牌与Pontoon规则下平局庄家赢
+			lose += p
+		}
+	}
+
+	return win, lose
+}
+
+// bonusPayout 西班牙21点5张及以上21点、6-7-8与7-7-7以及Pontoon五张牌型的奖励赔率
+func (ev *EVCalculator) bonusPayout(state handState) float64 {
+	if ev.rules.Pontoon && ev.isAutoWin(state) {
+		return entities.FiveCardTrickPayout
+	}
+	if total, _ := state.total(); !ev.rules.BonusPayouts || total != 21 {
+		return 1
+	}
+
+	switch {
+	case state.cards >= 7:
+		return 3
+	case state.cards == 6:
+		return 2
+	case state.cards == 5:
+		return 1.5
+	case state.cards == 3 && (state.trio == trioSixEight || state.trio == trioSevens):
+		return ev.trioPayout(state.trio == trioSevens)
+	default:
+		return 1
+	}
+}
+
+// trioPayout 6-7-8与7-7-7按花色的平均奖励赔率：杂色3:2，同花2:1，黑桃3:1
+// 点数模型不记录花色，同花概率按完整牌靴中每种花色各占四分之一计算（无限副牌下均为1/16）
+func (ev *EVCalculator) trioPayout(sevens bool) float64 {
+	suited := 1.0 / 16
+	if sevens && !ev.infiniteDeck {
+		// 三张7同花须从同一花色的7中连续抽出
+		decks := float64(max(ev.rules.DeckCount, 1))
+		suited = (decks - 1) / (4*decks - 1) * (decks - 2) / (4*decks - 2)
+	}
+	spades := suited / 4
+	return 1.5*(1-suited) + 2*(suited-spades) + 3*spades
+}
+
+// hitEV 要一张牌后按最优策略继续的期望值
+func (ev *EVCalculator) hitEV(state handState, probs [tenValue + 1]float64, dealer *dealerOutcome, memo map[handState]float64) float64 {
+	key := handState{hard: state.hard, hasAce: state.hasAce}
+	if ev.rules.CharlieCards > 0 || ev.rules.BonusPayouts {
+		// 查理与多张21点奖励规则下期望值与牌数有关
+		key.cards = state.cards
+	}
+	if ev.rules.BonusPayouts {
+		// 6-7-8与7-7-7奖励与前三张牌的组合有关
+		key.trio = state.trio
+	}
+	if value, ok := memo[key]; ok {
+		return value
+	}
+
+	result := 0.0
+	for value := aceValue; value <= tenValue; value++ {
+		if probs[value] == 0 {
+			continue
+		}
+		result += probs[value] * ev.bestHitStandEV(state.draw(value), probs, dealer, memo)
+	}
+
+	memo[key] = result
+	return result
+}
+
+// bestHitStandEV 只能要牌或停牌时的最优期望值
+func (ev *EVCalculator) bestHitStandEV(state handState, probs [tenValue + 1]float64, dealer *dealerOutcome, memo map[handState]float64) float64 {
+	total, _ := state.total()
+	if total > 21 {
+		return -1
+	}
+
+	stand := ev.standEV(state, dealer)
+	if total == 21 || ev.isAutoWin(state) {
+		return stand
+	}
+	if !ev.canStand(state) {
+		return ev.hitEV(state, probs, dealer, memo)
+	}
+
+	best := max(stand, ev.hitEV(state, probs, dealer, memo))
+	if ev.rules.DoubleAnyCards && ev.canDouble(state) {
+		// 任意张数可加倍时，要牌后的局面也可以加倍
+		best = max(best, ev.doubleEV(state, probs, dealer))
+	}
+	return best
+}
+
+// isAutoWin 手牌状态是否已自动获胜（查理或非Blackjack的21点必胜）
+func (ev *EVCalculator) isAutoWin(state handState) bool {
+	total, _ := state.total()
+	if total > 21 {
+		return false
+	}
+	if ev.rules.CharlieCards > 0 && state.cards >= ev.rules.CharlieCards {
+		return true
+	}
+	return ev.rules.Player21AlwaysWins && total == 21 && state.cards > 2
+}
+
+// canStand 规则是否允许在该手牌状态停牌（Pontoon须达到15点）
+func (ev *EVCalculator) canStand(state handState) bool {
+	total, _ := state.total()
+	return !ev.rules.Pontoon || total >= entities.PontoonMinStick
+}
+
+// canDouble 规则是否允许在该手牌状态加倍，Pontoon买牌须在五张牌之前且未到21点
+func (ev *EVCalculator) canDouble(state handState) bool {
+	if ev.rules.Pontoon {
+		total, _ := state.total()
+		return state.cards < ev.rules.CharlieCards && total < 21
+	}
+	if state.cards > 2 && !ev.rules.DoubleAnyCards {
+		return false
+	}
+	total, _ := state.total()
+	return ev.rules.DoubleRestriction.Allows(total)
+}
+
+// doubleEV 加倍期望值（只要一张牌，注码翻倍，加倍后不发奖励）
+// 加倍救援规则下未爆牌时可投降，只输原始注码；免费加倍时输牌只输原始注码
+func (ev *EVCalculator) doubleEV(state handState, probs [tenValue + 1]float64, dealer *dealerOutcome) float64 {
+	if ev.rules.Pontoon {
+		return ev.buyEV(state, probs, dealer)
+	}
+
+	result := 0.0
+	for value := aceValue; value <= tenValue; value++ {
+		if probs[value] == 0 {
+			continue
+		}
+		result += probs[value] * ev.doubledEV(state, value, dealer)
+	}
+	return result
+}
+
+// doubledEV 加倍后拿到value这张牌的期望值
+func (ev *EVCalculator) doubledEV(state handState, value int, dealer *dealerOutcome) float64 {
+	win, lose := ev.settleOdds(state.draw(value), dealer)
+	if ev.isFreeDouble(state) {
+		return 2*win - lose
+	}
+
+	stand := 2 * (win - lose)
+	if total, _ := state.draw(value).total(); ev.rules.DoubleDownRescue && total <= 21 {
+		stand = max(stand, -1)
+	}
+	return stand
+}
+
+// buyEV Pontoon买牌期望值：注码翻倍后要一张牌，之后仍可继续要牌但不能再买
+func (ev *EVCalculator) buyEV(state handState, probs [tenValue + 1]float64, dealer *dealerOutcome) float64 {
+	memo := make(map[handState]float64)
+	result := 0.0
+	for value := aceValue; value <= tenValue; value++ {
+		if probs[value] == 0 {
+			continue
+		}
+		result += probs[value] * 2 * ev.bestHitStandEV(state.draw(value), probs, dealer, memo)
+	}
+	return result
+}
+
+// isFreeDouble 免费加倍规则下两张牌的硬9-11点
+func (ev *EVCalculator) isFreeDouble(state handState) bool {
+	total, soft := state.total()
+	return ev.rules.FreeDoubles && state.cards == 2 && !soft && total >= 9 && total <= 11
+}
+
+// splitEV 分牌期望值（以初始注码为单位），分A通常只补一张，DAS规则下补牌后可加倍
+// 手牌打法按移除已补出的对子牌后的牌堆计算；免费分牌时分出的手牌由庄家出资，输牌不计损失
+func (ev *EVCalculator) splitEV(pairValue int, shoe shoeComposition, dealer *dealerOutcome) float64 {
+	paid, hands := ev.splitHandsEV(pairValue, shoe, ev.splitHandEV(pairValue, dealer, false))
+	if !ev.isFreeSplit(pairValue) {
+		return paid
+	}
+	free, _ := ev.splitHandsEV(pairValue, shoe, ev.splitHandEV(pairValue, dealer, true))
+	return ev.freeSplitEV(paid, free, hands)
+}
+
+// splitHandEV 分出的一手牌补到second后按最优打法的期望值，free为免费分牌由庄家出资的手牌
+func (ev *EVCalculator) splitHandEV(pairValue int, dealer *dealerOutcome, free bool) func(second int, shoe shoeComposition) float64 {
+	start := handState{}.draw(pairValue)
+	memos := make(map[[tenValue + 1]int]map[handState]float64)
+
+	return func(second int, shoe shoeComposition) float64 {
+		state := start.draw(second)
+		if ev.splitAcesOneCard(pairValue) {
+			if free {
+				win, _ := ev.settleOdds(state, dealer)
+				return win
+			}
+			return ev.standEV(state, dealer)
+		}
+
+		memo, ok := memos[shoe.counts]
+		if !ok {
+			memo = make(map[handState]float64)
+			memos[shoe.counts] = memo
+		}
+		probs := shoe.probabilities()
+		if free {
+			return ev.freeHandEV(state, probs, dealer, memo)
+		}
+		best := ev.bestHitStandEV(state, probs, dealer, memo)
+		if ev.rules.DoubleAfterSplit && ev.canDouble(state) {
+			best = max(best, ev.doubleEV(state, probs, dealer))
+		}
+		return best
+	}
+}
+
+// splitAcesOneCard 分出的手牌是否只补一张（分A且规则不允许分A后要牌）
+func (ev *EVCalculator) splitAcesOneCard(pairValue int) bool {
+	return pairValue == aceValue && !ev.rules.HitSplitAces
+}
+
+// isFreeSplit 免费分牌规则下除10点牌外的对子分牌免费
+func (ev *EVCalculator) isFreeSplit(pairValue int) bool {
+	return ev.rules.FreeSplits && pairValue != tenValue
+}
+
+// freeSplitEV 免费分牌：原注码只押在其中一手，其余分出（含再分）的手牌由庄家出资；
+// paid与free为所有手牌分别按自付与免费结算的期望值之和，押原注码的一手按平均每手的差额计
+func (ev *EVCalculator) freeSplitEV(paid, free, hands float64) float64 {
+	return free + (paid-free)/hands
+}
+
+// splitHandsEV 汇总分牌得到的所有手牌的期望值之和与期望手数
+// handEV(second, shoe)为一手牌补到second后按最优打法的期望值，shoe为移除补出的对子牌后的牌堆
+func (ev *EVCalculator) splitHandsEV(pairValue int, shoe shoeComposition, handEV func(second int, shoe shoeComposition) float64) (total, hands float64) {
+	nonPair, pair := ev.splitCompletions(pairValue, shoe)
+
+	for drawn := range nonPair {
+		if nonPair[drawn] == 0 && pair[drawn] == 0 {
+			continue
+		}
+		remaining := shoe
+		for range drawn {
+			remaining.remove(pairValue)
+		}
+
+		others := remaining.total - remaining.counts[pairValue]
+		if nonPair[drawn] > 0 && others > 0 {
+			// 以非对子牌完成的手牌：第二张牌按牌堆中其余点数的比例
+			nonPairEV := 0.0
+			for value := aceValue; value <= tenValue; value++ {
+				if value == pairValue || remaining.counts[value] == 0 {
+					continue
+				}
+				nonPairEV += float64(remaining.counts[value]) / float64(others) * handEV(value, remaining)
+			}
+			total += nonPair[drawn] * nonPairEV
+		}
+		if pair[drawn] > 0 {
+			total += pair[drawn] * handEV(pairValue, remaining)
+		}
+		hands += nonPair[drawn] + pair[drawn]
+	}
+	return total, hands
+}
+
+// splitCompletions 分牌后每手牌依次补第二张牌：补到对子牌且未达MaxSplitHands手时再分牌（A不能再分），
+// 对子牌逐张减少的概率按牌堆精确计算；按整个过程补出的对子牌张数drawn统计期望手数：
+// nonPair[drawn]为以非对子牌完成的手数，pair[drawn]为补到对子牌但不能再分的手数
+func (ev *EVCalculator) splitCompletions(pairValue int, shoe shoeComposition) (nonPair, pair []float64) {
+	pairCards := shoe.counts[pairValue]
+	size := pairCards
+	if shoe.infinite {
+		// 无限副牌中对子牌不会抽完，补出的对子牌最多为再分牌次数加上每手各一张
+		size = 2 * entities.MaxSplitHands
+	}
+	nonPair = make([]float64, size+1)
+	pair = make([]float64, size+1)
+	resplit := pairValue != aceValue || ev.rules.ResplitAces
+
+	var deal func(pending, hands, nonPairDone, pairDone, drawn, others int, prob float64)
+	deal = func(pending, hands, nonPairDone, pairDone, drawn, others int, prob float64) {
+		remaining, pairLeft := shoe.total-drawn-others, pairCards-drawn
+		if shoe.infinite {
+			remaining, pairLeft = shoe.total, pairCards
+		}
+		if pending == 0 || remaining <= 0 {
+			// 牌堆耗尽时未补牌的手牌按非对子牌计
+			nonPair[drawn] += prob * float64(nonPairDone+pending)
+			pair[drawn] += prob * float64(pairDone)
+			return
+		}
+
+		pPair := float64(pairLeft) / float64(remaining)
+		if pPair > 0 {
+			if resplit && hands < entities.MaxSplitHands {
+				deal(pending+1, hands+1, nonPairDone, pairDone, drawn+1, others, prob*pPair)
+			} else {
+				deal(pending-1, hands, nonPairDone, pairDone+1, drawn+1, others, prob*pPair)
+			}
+		}
+		if pPair < 1 {
+			deal(pending-1, hands, nonPairDone+1, pairDone, drawn, others+1, prob*(1-pPair))
+		}
+	}
+	deal(2, 2, 0, 0, 0, 0, 1)
+
+	return nonPair, pair
+}
+
+// freeHandEV 免费分牌得到的一手牌按最优打法的期望值：庄家出资的注码只计赢钱，
+// DAS规则下可加倍，硬9-11点免费加倍输牌不计损失，其余加倍自付、输牌只输加倍的注码
+func (ev *EVCalculator) freeHandEV(state handState, probs [tenValue + 1]float64, dealer *dealerOutcome, memo map[handState]float64) float64 {
+	if value, ok := memo[state]; ok {
+		return value
+	}
+	total, _ := state.total()
+	if total > 21 {
+		return 0
+	}
+
+	win, _ := ev.settleOdds(state, dealer)
+	best := ev.bonusPayout(state) * win
+	if total < 21 && !ev.isAutoWin(state) {
+		hit := 0.0
+		for value := aceValue; value <= tenValue; value++ {
+			if probs[value] == 0 {
+				continue
+			}
+			hit += probs[value] * ev.freeHandEV(state.draw(value), probs, dealer, memo)
+		}
+		best = max(best, hit)
+
+		if ev.rules.DoubleAfterSplit && ev.canDouble(state) {
+			double := 0.0
+			for value := aceValue; value <= tenValue; value++ {
+				if probs[value] == 0 {
+					continue
+				}
+				win, lose := ev.settleOdds(state.draw(value), dealer)
+				if ev.isFreeDouble(state) {
+					lose = 0
+				}
+				double += probs[value] * (2*win - lose)
+			}
+			best = max(best, double)
+		}
+	}
+
+	memo[state] = best
+	return best
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// cardsOf 按牌面创建卡牌（花色轮换）
+func cardsOf(ranks ...entities.Rank) []entities.Card {
+	cards := make([]entities.Card, 0, len(ranks))
+	for i, rank := range ranks {
+		cards = append(cards, entities.Card{Suit: entities.Suit(i % 4), Rank: rank})
+	}
+	return cards
+}
+
+// TestDealerOutcomes 测试庄家结果分布
+func TestDealerOutcomes(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	ev := NewEVCalculator(rules)
+	shoe := freshShoeComposition(rules)
+
+	for upCard := aceValue; upCard <= tenValue; upCard++ {
+		outcome := ev.dealerOutcomes(upCard, shoe)
+
+		total := outcome.bust + outcome.blackjack
+		for _, p := range outcome.totals {
+			total += p
+		}
+		if math.Abs(total-1.0) > 1e-9 {
+			t.Errorf("upcard %d: probabilities should sum to 1, got %f", upCard, total)
+		}
+
+		if (upCard == aceValue || upCard == tenValue) != (outcome.blackjack > 0) {
+			t.Errorf("upcard %d: unexpected blackjack probability %f", upCard, outcome.blackjack)
+		}
+	}
+
+	// 庄家明牌6的爆牌率约42%
+	if bust := ev.dealerOutcomes(6, shoe).bust; bust < 0.40 || bust > 0.44 {
+		t.Errorf("Expected dealer 6 bust probability around 0.42, got %f", bust)
+	}
+}
+
+// TestBasicStrategyDecisions 测试基本策略决策
+func TestBasicStrategyDecisions(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	ev := NewEVCalculator(rules)
+
+	tests := []struct {
+		name     string
+		player   []entities.Rank
+		dealerUp entities.Rank
+		expected entities.PlayerAction
+	}{
+		{"hard_11_vs_6_double", []entities.Rank{entities.Six, entities.Five}, entities.Six, entities.ActionDoubleDown},
+		{"hard_16_vs_10_hit", []entities.Rank{entities.Ten, entities.Six}, entities.King, entities.ActionHit},
+		{"hard_12_vs_4_stand", []entities.Rank{entities.Ten, entities.Two}, entities.Four, entities.ActionStand},
+		{"hard_12_vs_2_hit", []entities.Rank{entities.Ten, entities.Two}, entities.Two, entities.ActionHit},
+		{"hard_17_vs_ace_stand", []entities.Rank{entities.Ten, entities.Seven}, entities.Ace, entities.ActionStand},
+		{"soft_18_vs_9_hit", []entities.Rank{entities.Ace, entities.Seven}, entities.Nine, entities.ActionHit},
+		{"soft_18_vs_7_stand", []entities.Rank{entities.Ace, entities.Seven}, entities.Seven, entities.ActionStand},
+		{"soft_17_vs_4_double", []entities.Rank{entities.Ace, entities.Six}, entities.Four, entities.ActionDoubleDown},
+		{"eights_vs_6_split", []entities.Rank{entities.Eight, entities.Eight}, entities.Six, entities.ActionSplit},
+		{"aces_vs_5_split", []entities.Rank{entities.Ace, entities.Ace}, entities.Five, entities.ActionSplit},
+		{"tens_vs_6_stand", []entities.Rank{entities.Ten, entities.Ten}, entities.Six, entities.ActionStand},
+	}
+
+	for _, tt := range tests {
+		tt := tt // capture range variable
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evs := ev.CalculateBasicStrategyEVs(cardsOf(tt.player...), entities.Card{Rank: tt.dealerUp}, true, true, true)
+			action, _ := evs.Best()
+			if action != tt.expected {
+				t.Errorf("Expected action %v, got %v (evs %+v)", tt.expected, action, *evs)
+			}
+		})
+	}
+}
+
+// TestActionEVBounds 测试期望值范围
+func TestActionEVBounds(t *testing.T) {
+	t.Parallel()
+
+	ev := NewEVCalculator(entities.DefaultRules())
+	remaining := createRemainingCards(cardsOf(entities.Ten, entities.Six), cardsOf(entities.Seven))
+	evs := ev.CalculateActionEVs(cardsOf(entities.Ten, entities.Six), entities.Card{Rank: entities.Seven}, remaining, true, false, true)
+
+	if evs.Stand < -1 || evs.Stand > 1 || evs.Hit < -1 || evs.Hit > 1 {
+		t.Errorf("Stand/hit EV out of range: %+v", *evs)
+	}
+	if evs.Double < -2 || evs.Double > 2 {
+		t.Errorf("Double EV out of range: %f", evs.Double)
+	}
+	if _, ok := evs.ValueOf(entities.ActionSplit); ok {
+		t.Error("Split should not be available for non-pair hand")
+	}
+}
+
+// TestH17StrategyChanges 测试庄家软17要牌时的策略变化
+func TestH17StrategyChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		player []entities.Rank
+		up     entities.Rank
+		s17    entities.PlayerAction
+		h17    entities.PlayerAction
+	}{
+		{"hard_11_vs_ace", []entities.Rank{entities.Six, entities.Five}, entities.Ace, entities.ActionHit, entities.ActionDoubleDown},
+		{"soft_19_vs_6", []entities.Rank{entities.Ace, entities.Eight}, entities.Six, entities.ActionStand, entities.ActionDoubleDown},
+		{"soft_18_vs_2", []entities.Rank{entities.Ace, entities.Seven}, entities.Two, entities.ActionStand, entities.ActionDoubleDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, hitsSoft17 := range []bool{false, true} {
+				rules := entities.DefaultRules()
+				rules.DeckCount = 6
+				rules.DealerHitsSoft17 = hitsSoft17
+
+				expected := tt.s17
+				if hitsSoft17 {
+					expected = tt.h17
+				}
+				evs := NewEVCalculator(rules).CalculateBasicStrategyEVs(cardsOf(tt.player...), entities.Card{Rank: tt.up}, true, false, true)
+				if action, _ := evs.Best(); action != expected {
+					t.Errorf("Expected %v with h17=%v, got %v (evs %+v)", expected, hitsSoft17, action, *evs)
+				}
+			}
+		})
+	}
+
+	// 软17要牌时庄家最终不会停在软17，整体对玩家不利约0.2%
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	s17 := NewEVCalculator(rules).OffTheTopEV()
+	rules.DealerHitsSoft17 = true
+	h17 := NewEVCalculator(rules).OffTheTopEV()
+	if diff := h17 - s17; diff > -0.0015 || diff < -0.0030 {
+		t.Errorf("Expected H17 to cost about 0.2%%, got %.4f%%", diff*100)
+	}
+}
+
+// TestSplitEV 测试分牌期望值与公开的6副牌S17、可再分至4手、分A只补一张的分牌期望值表一致
+func TestSplitEV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pair     entities.Rank
+		up       entities.Rank
+		das      bool
+		expected float64
+	}{
+		{"aces_vs_6", entities.Ace, entities.Six, true, 0.680},
+		{"aces_vs_10", entities.Ace, entities.King, true, 0.184},
+		{"eights_vs_6_das", entities.Eight, entities.Six, true, 0.404},
+		{"eights_vs_6_nodas", entities.Eight, entities.Six, false, 0.293},
+		{"eights_vs_10_das", entities.Eight, entities.King, true, -0.476},
+		{"nines_vs_7_das", entities.Nine, entities.Seven, true, 0.365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.DeckCount = 6
+			rules.DoubleAfterSplit = tt.das
+			evs := NewEVCalculator(rules).CalculateBasicStrategyEVs(cardsOf(tt.pair, tt.pair), entities.Card{Rank: tt.up}, true, true, true)
+			if math.Abs(evs.Split-tt.expected) > 0.02 {
+				t.Errorf("Expected split EV about %.3f, got %.4f", tt.expected, evs.Split)
+			}
+		})
+	}
+
+	// 6副牌2-2对2：不能加倍后分牌时要牌，能加倍后分牌时分牌
+	for _, das := range []bool{false, true} {
+		rules := entities.DefaultRules()
+		rules.DeckCount = 6
+		rules.DoubleAfterSplit = das
+		evs := NewEVCalculator(rules).CalculateBasicStrategyEVs(cardsOf(entities.Two, entities.Two), entities.Card{Rank: entities.Two}, true, true, true)
+		expected := entities.ActionHit
+		if das {
+			expected = entities.ActionSplit
+		}
+		if action, _ := evs.Best(); action != expected {
+			t.Errorf("2-2 vs 2 (das=%v): expected %v, got %v (evs %+v)", das, expected, action, *evs)
+		}
+	}
+}
+
+// TestSplitCompletions 测试分牌补牌过程的期望手数：A不能再分，其余对子最多再分至MaxSplitHands手
+func TestSplitCompletions(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	ev := NewEVCalculator(rules)
+	shoe := freshShoeComposition(rules)
+
+	tests := []struct {
+		name     string
+		pair     int
+		minHands float64
+		maxHands float64
+	}{
+		{"aces", aceValue, 2, 2},
+		{"eights", 8, 2.1, 2.3},
+		{"tens", tenValue, 2.8, 3.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			nonPair, pair := ev.splitCompletions(tt.pair, shoe)
+			hands, stuck := 0.0, 0.0
+			for drawn := range nonPair {
+				hands += nonPair[drawn] + pair[drawn]
+				stuck += pair[drawn]
+			}
+			if hands < tt.minHands-1e-9 || hands > tt.maxHands+1e-9 {
+				t.Errorf("Expected %.1f-%.1f hands, got %.4f", tt.minHands, tt.maxHands, hands)
+			}
+			// A不能再分，或已分至上限时补到的对子牌只能留在手牌中
+			if stuck <= 0 {
+				t.Errorf("Expected some hands to keep a pair card they cannot resplit, got %f", stuck)
+			}
+		})
+	}
+}
+
+// TestInfiniteDeckEV 测试无限副牌期望值：不受剩余牌影响，首手期望值等于按副数倒数外推到无限副的极限
+func TestInfiniteDeckEV(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	infinite := NewEVCalculator(rules, WithEVInfiniteDeck())
+
+	player, up := cardsOf(entities.Ten, entities.Six), entities.Card{Rank: entities.Ten}
+	remaining := cardsOf(entities.Five, entities.Five, entities.Four)
+	basic := infinite.CalculateBasicStrategyEVs(player, up, true, true, true)
+	if evs := infinite.CalculateActionEVs(player, up, remaining, true, true, true); *evs != *basic {
+		t.Errorf("Expected the remaining cards ignored, got %+v and %+v", *evs, *basic)
+	}
+
+	// 首手期望值随副数近似按 a + b/副数 变化，由6副与8副外推无限副
+	offTheTop := func(decks int) float64 {
+		rules := rules
+		rules.DeckCount = decks
+		return NewEVCalculator(rules).OffTheTopEV()
+	}
+	six, eight := offTheTop(6), offTheTop(8)
+	limit := eight - 3*(six-eight)
+	if got := infinite.OffTheTopEV(); math.Abs(got-limit) > 0.0002 || got >= eight {
+		t.Errorf("Expected infinite deck EV near %.4f%% and below 8 decks %.4f%%, got %.4f%%", limit*100, eight*100, got*100)
+	}
+
+	// 无限副牌分8：每手补到8的概率固定为1/13，最多再分至MaxSplitHands手
+	nonPair, pair := infinite.splitCompletions(8, infiniteShoeComposition(rules))
+	hands := 0.0
+	for drawn := range nonPair {
+		hands += nonPair[drawn] + pair[drawn]
+	}
+	if hands < 2.1 || hands > 2.3 {
+		t.Errorf("Expected 2.1-2.3 split hands, got %.4f", hands)
+	}
+}
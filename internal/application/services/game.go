@@ -3,6 +3,8 @@ package services
 
 import (
 	"errors"
+	"slices"
+	"time"
 
 	"github.com/luffy050596/go-blackjack/internal/application/dtos"
 	"github.com/luffy050596/go-blackjack/internal/domain/entities"
@@ -12,14 +14,26 @@ import (
 type GameApplicationService struct {
 	game            *entities.Game
 	probabilityCalc *ProbabilityCalculator
+	trainer         *StrategyTrainer
+	sideBets        *SideBetCalculator
+	evCalc          *EVCalculator
+	trainingMode    bool
+	strategySource  StrategySource // 推荐操作的来源
+	infiniteEV      *EVCalculator  // 无限副牌参考的期望值计算器，未启用参考时为nil
+	drill           *StrategyDrill
+	countingDrill   *CountingDrill
+	countingHistory []CountingSession
 }
 
 // NewGameApplicationService 创建游戏应用服务
-func NewGameApplicationService(playerName string) *GameApplicationService {
-	game := entities.NewGame(playerName)
+func NewGameApplicationService(playerName string, options ...entities.GameOption) *GameApplicationService {
+	game := entities.NewGame(playerName, options...)
 	return &GameApplicationService{
 		game:            game,
-		probabilityCalc: NewProbabilityCalculator(game.Deck),
+		probabilityCalc: NewProbabilityCalculator(game.Deck, WithProbabilityRules(game.Rules)),
+		trainer:         NewStrategyTrainer(game.Rules),
+		sideBets:        NewSideBetCalculator(game.Rules),
+		evCalc:          NewEVCalculator(game.Rules),
 	}
 }
 
@@ -41,15 +55,40 @@ func (s *GameApplicationService) StartDealerTurn() bool {
 
 // GetGameState 获取游戏状态
 func (s *GameApplicationService) GetGameState() *dtos.GameStateDTO {
-	return &dtos.GameStateDTO{
+	state := &dtos.GameStateDTO{
 		RoundNumber: s.game.RoundNumber,
 		PlayerChips: s.game.Player.Chips,
-		PlayerBet:   s.game.Player.Bet,
 		PlayerHand:  convertHandToDTO(s.game.Player.Hand),
 		DealerHand:  convertHandToDTO(s.game.Dealer.Hand),
 		State:       s.game.State,
 		IsGameOver:  s.game.IsGameOver(),
+
+		DealerFaceDown: s.game.Rules.Pontoon && s.game.State == entities.StatePlayerTurn,
 	}
+
+	hands := s.game.Player.Hands
+	for i, hand := range hands {
+		state.PlayerBet += hand.Bet
+		if hand == s.game.Player.PlayerHand {
+			state.ActiveHand = i
+		}
+		if len(hands) > 1 {
+			state.PlayerHands = append(state.PlayerHands, &dtos.PlayerHandDTO{
+				Hand:       convertHandToDTO(hand.Hand),
+				Bet:        hand.Bet,
+				FreeBet:    hand.FreeBet,
+				IsDoubled:  hand.DoubledDown,
+				IsFinished: hand.Finished,
+			})
+		}
+	}
+
+	return state
+}
+
+// GetRules 获取当前牌桌规则
+func (s *GameApplicationService) GetRules() entities.Rules {
+	return s.game.Rules
 }
 
 // PlaceBet 下注
@@ -62,8 +101,39 @@ func (s *GameApplicationService) DealInitialCards() error {
 	return s.game.DealInitialCards()
 }
 
-// ProcessPlayerAction 处理玩家行动
+// DealerPeekedBlackjack 庄家是否偷看底牌发现Blackjack（本局无需玩家行动）
+func (s *GameApplicationService) DealerPeekedBlackjack() bool {
+	return s.game.DealerPeekedBlackjack()
+}
+
+// ProcessPlayerAction 处理玩家行动，加倍时按最多可追加的金额加倍
 func (s *GameApplicationService) ProcessPlayerAction(action entities.PlayerAction) (*dtos.ActionResultDTO, error) {
+	return s.processAction(action, s.game.MaxDoubleAmount())
+}
+
+// ProcessDoubleDown 按指定的追加金额加倍（少加倍规则下可低于原注）
+func (s *GameApplicationService) ProcessDoubleDown(amount int) (*dtos.ActionResultDTO, error) {
+	return s.processAction(entities.ActionDoubleDown, amount)
+}
+
+// processAction 执行玩家行动并附加训练模式的评分
+func (s *GameApplicationService) processAction(action entities.PlayerAction, doubleAmount int) (*dtos.ActionResultDTO, error) {
+	// 训练模式下在执行前按行动前的局面评分
+	var feedback *dtos.DecisionFeedbackDTO
+	if s.trainingMode {
+		feedback = s.gradePlayerAction(action)
+	}
+
+	result, err := s.processPlayerAction(action, doubleAmount)
+	if result != nil && result.Success {
+		result.Feedback = feedback
+	}
+
+	return result, err
+}
+
+// processPlayerAction 执行玩家行动
+func (s *GameApplicationService) processPlayerAction(action entities.PlayerAction, doubleAmount int) (*dtos.ActionResultDTO, error) {
 	switch action {
 	case entities.ActionHit:
 		card, err := s.game.PlayerHit()
@@ -73,30 +143,63 @@ func (s *GameApplicationService) ProcessPlayerAction(action entities.PlayerActio
 		return &dtos.ActionResultDTO{
 			Action:   entities.ActionHit,
 			Success:  true,
-			Continue: !s.game.Player.Hand.IsBust() && !s.game.Player.Hand.IsBlackjack(),
+			Continue: s.game.State == entities.StatePlayerTurn,
 			Card:     convertCardToDTO(card),
 		}, nil
 
 	case entities.ActionStand:
-		s.game.PlayerStand()
+		if err := s.game.PlayerStand(); err != nil {
+			return nil, err
+		}
 		return &dtos.ActionResultDTO{
 			Action:   entities.ActionStand,
 			Success:  true,
-			Continue: false,
+			Continue: s.game.State == entities.StatePlayerTurn,
 		}, nil
 
 	case entities.ActionDoubleDown:
-		card, err := s.game.PlayerDoubleDown()
+		card, err := s.game.PlayerDoubleDown(doubleAmount)
 		if err != nil {
 			return nil, err
 		}
+		// 加倍救援规则下加倍后仍可选择投降，多手牌时继续下一手
 		return &dtos.ActionResultDTO{
 			Action:   entities.ActionDoubleDown,
 			Success:  true,
-			Continue: false,
+			Continue: s.game.State == entities.StatePlayerTurn,
 			Card:     convertCardToDTO(card),
 		}, nil
 
+	case entities.ActionSurrender:
+		if err := s.game.PlayerSurrender(); err != nil {
+			return nil, err
+		}
+		return &dtos.ActionResultDTO{
+			Action:   entities.ActionSurrender,
+			Success:  true,
+			Continue: s.game.State == entities.StatePlayerTurn,
+		}, nil
+
+	case entities.ActionSplit:
+		if err := s.game.PlayerSplit(); err != nil {
+			return nil, err
+		}
+		return &dtos.ActionResultDTO{
+			Action:   entities.ActionSplit,
+			Success:  true,
+			Continue: s.game.State == entities.StatePlayerTurn,
+		}, nil
+
+	case entities.ActionSwitch:
+		if err := s.game.PlayerSwitch(); err != nil {
+			return nil, err
+		}
+		return &dtos.ActionResultDTO{
+			Action:   entities.ActionSwitch,
+			Success:  true,
+			Continue: true,
+		}, nil
+
 	case entities.ActionQuit:
 		return &dtos.ActionResultDTO{
 			Action:   entities.ActionQuit,
@@ -125,32 +228,138 @@ func (s *GameApplicationService) EvaluateGame() *dtos.GameResultDTO {
 		return nil
 	}
 
-	return &dtos.GameResultDTO{
+	resultDTO := &dtos.GameResultDTO{
 		Type:        result.ResultType,
 		BetAmount:   result.BetAmount,
 		IsDoubled:   result.IsDoubled,
 		PlayerChips: s.game.Player.Chips,
+		SideBets:    s.GetSideBetResults(),
 	}
+	for _, hand := range result.Hands {
+		resultDTO.Hands = append(resultDTO.Hands, &dtos.HandResultDTO{
+			Type:      hand.ResultType,
+			BetAmount: hand.BetAmount,
+			IsDoubled: hand.IsDoubled,
+		})
+	}
+
+	return resultDTO
 }
 
-// GetBetOptions 获取下注选项
-func (s *GameApplicationService) GetBetOptions() []int {
-	chips := s.game.Player.Chips
-	betOptions := []int{10, 25, 50, 100, 200}
-	validOptions := []int{}
+// PlaceSideBet 下边注（主注之后、发牌之前）
+func (s *GameApplicationService) PlaceSideBet(betType entities.SideBetType, amount int) error {
+	return s.game.PlaceSideBet(betType, amount)
+}
+
+// GetSideBetResults 获取本局边注结算结果
+func (s *GameApplicationService) GetSideBetResults() []*dtos.SideBetResultDTO {
+	results := make([]*dtos.SideBetResultDTO, 0, len(s.game.SideBetResults))
+	for _, result := range s.game.SideBetResults {
+		results = append(results, &dtos.SideBetResultDTO{
+			Type:     result.Type,
+			Amount:   result.Amount,
+			Hand:     result.Hand.String(),
+			Payout:   result.Payout,
+			NetValue: result.NetValue,
+		})
+	}
+	return results
+}
+
+// GetSideBetEVs 按当前剩余牌堆计算各边注的期望值
+func (s *GameApplicationService) GetSideBetEVs() []*dtos.SideBetEVDTO {
+	evs := make([]*dtos.SideBetEVDTO, 0, len(entities.SideBetTypes))
+	for _, betType := range entities.SideBetTypes {
+		evs = append(evs, s.sideBets.Calculate(betType, s.game.GetRemainingCards()))
+	}
+	return evs
+}
+
+// GetDiscardTray 获取弃牌架状态与本牌靴已看到的牌的组成
+func (s *GameApplicationService) GetDiscardTray() *dtos.DiscardTrayDTO {
+	counts := make(map[entities.Rank]int)
+	for _, card := range s.game.GetSeenCards() {
+		counts[card.Rank]++
+	}
+
+	tray := &dtos.DiscardTrayDTO{
+		Discarded:      len(s.game.Discards.Cards),
+		Burned:         len(s.game.Discards.Burned),
+		CardsRemaining: len(s.game.Deck.Cards),
+	}
+	for rank := entities.Ace; rank <= entities.King; rank++ {
+		tray.Seen = append(tray.Seen, &dtos.RankCountDTO{Rank: rank.String(), Count: counts[rank]})
+	}
+	return tray
+}
+
+// GetShoeComposition 获取还没看到的牌（含庄家未翻开的底牌）的组成：与完整牌靴对比的各点数余牌、剩余副数、渗透率，
+// 以及按余牌组成计算的玩家期望值和每种牌的移除效应
+func (s *GameApplicationService) GetShoeComposition() *dtos.ShoeCompositionDTO {
+	rules := s.game.Rules
+	remaining := s.game.GetUnseenCards()
+	fresh := freshShoeComposition(rules)
+	deckCount := max(rules.DeckCount, 1)
+
+	counts := make(map[entities.Rank]int)
+	for _, card := range remaining {
+		counts[card.Rank]++
+	}
 
-	for _, amount := range betOptions {
-		if amount <= chips {
-			validOptions = append(validOptions, amount)
+	composition := &dtos.ShoeCompositionDTO{
+		ShoeSize:       fresh.total,
+		CardsRemaining: len(remaining),
+		DecksRemaining: float64(len(remaining)) * float64(deckCount) / float64(fresh.total),
+		Penetration:    1 - float64(len(s.game.Deck.Cards))/float64(fresh.total),
+		Discarded:      len(s.game.Discards.Cards),
+		Burned:         len(s.game.Discards.Burned),
+	}
+	for rank := entities.Ace; rank <= entities.King; rank++ {
+		rankFresh := 4 * deckCount
+		if rank == entities.Ten && rules.SpanishDeck {
+			rankFresh = 0
+		}
+		dto := &dtos.RankCompositionDTO{
+			Rank:         rank.String(),
+			Remaining:    counts[rank],
+			Fresh:        rankFresh,
+			FreshPercent: float64(rankFresh) / float64(fresh.total),
+		}
+		if len(remaining) > 0 {
+			dto.Percent = float64(counts[rank]) / float64(len(remaining))
 		}
+		composition.Ranks = append(composition.Ranks, dto)
 	}
 
-	// 如果玩家筹码很少，添加全押选项
-	if chips < betOptions[0] && chips > 0 {
-		validOptions = append(validOptions, chips)
+	edge, effects := NewEVCalculator(rules).effectsOfRemoval(newShoeComposition(remaining))
+	composition.PlayerEdge = edge
+	for value := aceValue; value <= tenValue; value++ {
+		composition.EffectsOfRemoval = append(composition.EffectsOfRemoval, &dtos.EffectOfRemovalDTO{
+			Rank:   entities.Rank(value).String(),
+			Effect: effects[value],
+		})
+	}
+	return composition
+}
+
+// GetBetOptions 获取下注选项（常用金额与牌桌限额）
+func (s *GameApplicationService) GetBetOptions() *dtos.BetOptionsDTO {
+	rules := s.game.Rules
+	chips := s.game.Player.Chips
+
+	options := &dtos.BetOptionsDTO{
+		MinBet:           rules.MinBet,
+		MaxBet:           rules.MaxBet,
+		ChipDenomination: rules.ChipDenomination,
+		LastBet:          s.game.Player.LastBet,
+	}
+	for _, amount := range []int{10, 25, 50, 100, 200} {
+		if amount <= chips && rules.ValidateBet(amount) == nil {
+			options.Amounts = append(options.Amounts, amount)
+		}
 	}
 
-	return validOptions
+	return options
 }
 
 // GetKellyBettingRecommendation 获取凯利公式下注建议
@@ -163,6 +372,9 @@ func (s *GameApplicationService) GetKellyBettingRecommendation() *dtos.KellyReco
 	// 计算基础凯利比例
 	kelly := s.probabilityCalc.CalculateBasicKellyFraction(estimatedWinRate, estimatedLoseRate, s.game.Player.Chips)
 
+	// 推荐金额按牌桌限额和筹码面额取整，保证可以直接下注
+	kelly.RecommendedBetAmount = s.game.Rules.RoundBet(kelly.RecommendedBetAmount, s.game.Player.Chips)
+
 	// 转换为DTO
 	return &dtos.KellyRecommendationDTO{
 		StandardKellyFraction:  kelly.StandardKellyFraction,
@@ -179,7 +391,47 @@ func (s *GameApplicationService) GetKellyBettingRecommendation() *dtos.KellyReco
 
 // CanPlayerDoubleDown 检查玩家是否可以加倍
 func (s *GameApplicationService) CanPlayerDoubleDown() bool {
-	return s.game.Player.CanDoubleDown()
+	return s.game.CanPlayerDoubleDown()
+}
+
+// IsFreeDouble 当前手牌加倍是否免费
+func (s *GameApplicationService) IsFreeDouble() bool {
+	return s.game.CanPlayerDoubleDown() && s.game.Rules.IsFreeDouble(s.game.Player.Hand)
+}
+
+// IsFreeSplit 当前手牌分牌是否免费
+func (s *GameApplicationService) IsFreeSplit() bool {
+	return s.game.CanPlayerSplit() && s.game.Rules.IsFreeSplit(s.game.Player.Hand)
+}
+
+// CanPlayerSplit 检查玩家是否可以分牌
+func (s *GameApplicationService) CanPlayerSplit() bool {
+	return s.game.CanPlayerSplit()
+}
+
+// CanPlayerSwitch 检查玩家是否可以交换两手牌的第二张牌
+func (s *GameApplicationService) CanPlayerSwitch() bool {
+	return s.game.CanPlayerSwitch()
+}
+
+// CanPlayerHit 检查玩家是否可以要牌
+func (s *GameApplicationService) CanPlayerHit() bool {
+	return s.game.CanPlayerHit()
+}
+
+// CanPlayerStand 检查玩家是否可以停牌（Pontoon需达到最低点数）
+func (s *GameApplicationService) CanPlayerStand() bool {
+	return s.game.CanPlayerStand()
+}
+
+// CanPlayerSurrender 检查玩家是否可以投降
+func (s *GameApplicationService) CanPlayerSurrender() bool {
+	return s.game.CanPlayerSurrender()
+}
+
+// MaxDoubleAmount 加倍最多可追加的金额
+func (s *GameApplicationService) MaxDoubleAmount() int {
+	return s.game.MaxDoubleAmount()
 }
 
 // IsGameOver 检查游戏是否结束
@@ -236,8 +488,10 @@ func (s *GameApplicationService) CalculateWinProbabilities() *dtos.ProbabilityRe
 			CanSplit:            result.ActionAnalysis.CanSplit,
 			RecommendedAction:   result.ActionAnalysis.RecommendedAction,
 			ExpectedValue:       result.ActionAnalysis.ExpectedValue,
+			StrategySource:      StrategySimulation.String(),
 			KellyRecommendation: kellyRecommendationDTO,
 		}
+		s.applyStrategySource(actionAnalysisDTO)
 	}
 
 	// 转换为DTO
@@ -252,6 +506,415 @@ func (s *GameApplicationService) CalculateWinProbabilities() *dtos.ProbabilityRe
 		Player21Probability:   result.Player21Probability,
 		Dealer21Probability:   result.Dealer21Probability,
 		ActionAnalysis:        actionAnalysisDTO,
+		SwitchAdvice:          s.switchAdvice(),
+		InfiniteDeck:          s.infiniteDeckReference(),
+	}
+}
+
+// infiniteDeckReference 按无限副牌精确计算当前局面的最优期望值作为参考，
+// 与同样按总点数决策、针对当前确切牌堆的期望值之差即为牌堆组成的影响；未启用或没有可用明牌时返回nil
+func (s *GameApplicationService) infiniteDeckReference() *dtos.InfiniteDeckReferenceDTO {
+	if s.infiniteEV == nil || !s.strategyApplicable() {
+		return nil
+	}
+
+	hand := s.game.Player.Hand
+	upCard := s.game.Dealer.Hand.Cards[0]
+	canDouble, canSplit, canSurrender := s.game.CanPlayerDoubleDown(), s.game.CanPlayerSplit(), s.game.CanPlayerSurrender()
+	action, referenceEV := s.infiniteEV.CalculateBasicStrategyEVs(hand.Cards, upCard, canDouble, canSplit, canSurrender).Best()
+	_, shoeEV := s.evCalc.CalculateActionEVs(hand.Cards, upCard, s.game.GetUnseenCards(), canDouble, canSplit, canSurrender).Best()
+
+	return &dtos.InfiniteDeckReferenceDTO{
+		ExpectedValue:     referenceEV,
+		ShoeExpectedValue: shoeEV,
+		RecommendedAction: actionKey(action),
+		CompositionEffect: shoeEV - referenceEV,
+	}
+}
+
+// applyStrategySource 按推荐来源改写推荐操作：基本策略或组成相关最优策略，Pontoon没有明牌可用时保留模拟结果
+func (s *GameApplicationService) applyStrategySource(analysis *dtos.ActionAnalysisDTO) {
+	evs := s.GetStrategyEVs(s.strategySource)
+	if evs == nil {
+		return
+	}
+
+	action, _ := evs.Best()
+	analysis.RecommendedAction = actionKey(action)
+	analysis.StrategySource = s.strategySource.String()
+	switch action {
+	case entities.ActionHit:
+		analysis.ExpectedValue = analysis.HitWinRate
+	case entities.ActionDoubleDown:
+		analysis.ExpectedValue = analysis.DoubleWinRate
+	case entities.ActionSplit:
+		analysis.ExpectedValue = analysis.SplitWinRate
+	case entities.ActionSurrender:
+		analysis.ExpectedValue = 0
+	default:
+		analysis.ExpectedValue = analysis.StandWinRate
+	}
+}
+
+// GetStrategyEVs 按推荐来源计算当前手牌各操作的期望值：基本策略按完整牌靴的总点数计算，
+// 组成相关策略按玩家看不到的确切牌堆计算；模拟来源或没有可用明牌时返回nil
+func (s *GameApplicationService) GetStrategyEVs(source StrategySource) *ActionEV {
+	if source == StrategySimulation || !s.strategyApplicable() {
+		return nil
+	}
+
+	hand := s.game.Player.Hand
+	upCard := s.game.Dealer.Hand.Cards[0]
+	canDouble, canSplit, canSurrender := s.game.CanPlayerDoubleDown(), s.game.CanPlayerSplit(), s.game.CanPlayerSurrender()
+	if source == StrategyBasic {
+		return s.evCalc.CalculateBasicStrategyEVs(hand.Cards, upCard, canDouble, canSplit, canSurrender)
+	}
+
+	return s.evCalc.CalculateCompositionEVs(hand.Cards, upCard, s.game.GetUnseenCards(), canDouble, canSplit, canSurrender)
+}
+
+// strategyApplicable 当前局面能否按庄家明牌计算策略期望值（加倍后与Pontoon不适用）
+func (s *GameApplicationService) strategyApplicable() bool {
+	return s.game.State == entities.StatePlayerTurn && !s.game.Rules.Pontoon &&
+		len(s.game.Dealer.Hand.Cards) > 0 && !s.game.Player.DoubledDown
+}
+
+// SetStrategySource 设置推荐操作的来源
+func (s *GameApplicationService) SetStrategySource(source StrategySource) {
+	s.strategySource = source
+}
+
+// SetInfiniteDeckReference 设置概率分析是否附带无限副牌参考
+func (s *GameApplicationService) SetInfiniteDeckReference(enabled bool) {
+	s.infiniteEV = nil
+	if enabled {
+		s.infiniteEV = NewEVCalculator(s.game.Rules, WithEVInfiniteDeck())
+	}
+}
+
+// switchAdvice 比较换牌与否的期望值，不能换牌时返回nil
+func (s *GameApplicationService) switchAdvice() *dtos.SwitchAdviceDTO {
+	if !s.game.CanPlayerSwitch() {
+		return nil
+	}
+
+	hands := s.game.Player.Hands
+	keep, switched := s.evCalc.CalculateSwitchEVs(hands[0].Hand.Cards, hands[1].Hand.Cards, s.game.Dealer.Hand.Cards[0])
+	return &dtos.SwitchAdviceDTO{
+		KeepEV:       keep,
+		SwitchEV:     switched,
+		ShouldSwitch: switched > keep,
+	}
+}
+
+// SetTrainingMode 开启或关闭训练模式（每次行动后与基本策略比较）
+func (s *GameApplicationService) SetTrainingMode(enabled bool) {
+	s.trainingMode = enabled
+}
+
+// gradePlayerAction 评估玩家行动是否符合基本策略
+func (s *GameApplicationService) gradePlayerAction(action entities.PlayerAction) *dtos.DecisionFeedbackDTO {
+	// 加倍后（加倍救援）的决策不在基本策略表中，Pontoon庄家没有明牌可供查表
+	if s.game.State != entities.StatePlayerTurn || len(s.game.Dealer.Hand.Cards) == 0 || s.game.Player.DoubledDown ||
+		s.game.Rules.Pontoon {
+		return nil
+	}
+
+	grade := s.trainer.Grade(
+		s.game.Player.Hand,
+		s.game.Dealer.Hand.Cards[0],
+		action,
+		s.game.CanPlayerDoubleDown(),
+		s.game.CanPlayerSplit(),
+		s.game.CanPlayerSurrender(),
+	)
+	if grade == nil {
+		return nil
+	}
+
+	return convertGradeToDTO(grade)
+}
+
+// GetTrainingStats 获取训练统计
+func (s *GameApplicationService) GetTrainingStats() *dtos.TrainingStatsDTO {
+	result := &dtos.TrainingStatsDTO{
+		WeakestCategory: s.trainer.WeakestCategory().String(),
+	}
+
+	correct := 0
+	for _, stats := range s.trainer.Stats() {
+		categoryDTO := &dtos.CategoryStatsDTO{
+			Category:  stats.Category.String(),
+			Decisions: stats.Decisions,
+			Correct:   stats.Correct,
+			Accuracy:  stats.Accuracy(),
+		}
+		if stats.Decisions > 0 {
+			categoryDTO.AverageEVCost = stats.TotalEVCost / float64(stats.Decisions)
+		}
+		result.Categories = append(result.Categories, categoryDTO)
+
+		result.TotalDecisions += stats.Decisions
+		result.TotalEVCost += stats.TotalEVCost
+		correct += stats.Correct
+	}
+
+	if result.TotalDecisions > 0 {
+		result.Accuracy = float64(correct) / float64(result.TotalDecisions)
+	}
+
+	return result
+}
+
+// NextStrategyDrill 生成下一道策略专项练习（优先练习错误率高的类别）
+func (s *GameApplicationService) NextStrategyDrill() *dtos.StrategyDrillDTO {
+	s.drill = s.trainer.NextDrill()
+
+	return &dtos.StrategyDrillDTO{
+		Category:     s.drill.Category.String(),
+		PlayerHand:   convertHandToDTO(s.drill.PlayerHand),
+		DealerUpCard: convertCardToDTO(s.drill.DealerUpCard),
+		CanDouble:    s.drill.CanDouble,
+		CanSplit:     s.drill.CanSplit,
+		CanSurrender: s.drill.CanSurrender,
+	}
+}
+
+// AnswerStrategyDrill 回答当前策略练习并返回评分
+func (s *GameApplicationService) AnswerStrategyDrill(action entities.PlayerAction) (*dtos.DecisionFeedbackDTO, error) {
+	if s.drill == nil {
+		return nil, errors.New("no active drill")
+	}
+
+	grade := s.trainer.Grade(
+		s.drill.PlayerHand,
+		s.drill.DealerUpCard,
+		action,
+		s.drill.CanDouble,
+		s.drill.CanSplit,
+		s.drill.CanSurrender,
+	)
+	if grade == nil {
+		return nil, errors.New("action not available for this hand")
+	}
+	s.drill = nil
+
+	return convertGradeToDTO(grade), nil
+}
+
+// GetFairShoe 获取当前可验证公平牌靴，未开启或尚未洗出时返回nil
+func (s *GameApplicationService) GetFairShoe() *dtos.FairShoeDTO {
+	if s.game.Fair == nil {
+		return nil
+	}
+	return convertFairShoeToDTO(s.game.Fair)
+}
+
+// GetFairCommitment 获取为下一个可验证公平牌靴公布的承诺，客户端种子为目前设置的种子，未开启时返回nil
+func (s *GameApplicationService) GetFairCommitment() *dtos.FairShoeDTO {
+	if s.game.FairNext == nil {
+		return nil
+	}
+	commitment := convertFairShoeToDTO(s.game.FairNext)
+	commitment.ClientSeed = s.game.ClientSeed()
+	return commitment
+}
+
+// SetClientSeed 设置或更换可验证公平模式的客户端种子，从下一个牌靴开始生效
+func (s *GameApplicationService) SetClientSeed(seed string) error {
+	return s.game.SetClientSeed(seed)
+}
+
+// RevealFairShoe 提前结束当前可验证公平牌靴并公开服务器种子（如离开牌桌时）
+func (s *GameApplicationService) RevealFairShoe() *dtos.FairShoeDTO {
+	fair := s.game.RevealFairShoe()
+	if fair == nil {
+		return nil
+	}
+	return convertFairShoeToDTO(fair)
+}
+
+// GetFairHistory 获取已公开服务器种子的牌靴
+func (s *GameApplicationService) GetFairHistory() []*dtos.FairShoeDTO {
+	history := make([]*dtos.FairShoeDTO, 0, len(s.game.FairHistory))
+	for _, fair := range s.game.FairHistory {
+		history = append(history, convertFairShoeToDTO(fair))
+	}
+	return history
+}
+
+// VerifyFairShoe 用公开的服务器种子重现牌靴并核对承诺与已发出的牌，返回重现的完整牌序
+func VerifyFairShoe(record *dtos.FairShoeDTO) ([]*dtos.CardDTO, error) {
+	order, err := entities.VerifyFairShoe(&entities.FairShoe{
+		Number:      record.Number,
+		ClientSeed:  record.ClientSeed,
+		Commitment:  record.Commitment,
+		ServerSeed:  record.ServerSeed,
+		DeckCount:   record.DeckCount,
+		SpanishDeck: record.SpanishDeck,
+		Excluded:    record.Excluded,
+		Dealt:       record.Dealt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]*dtos.CardDTO, len(order))
+	for i, card := range order {
+		cards[i] = convertCardToDTO(card)
+	}
+	return cards, nil
+}
+
+// GetCountingSystems 获取支持的算牌系统
+func (s *GameApplicationService) GetCountingSystems() []*dtos.CountingSystemDTO {
+	systems := make([]*dtos.CountingSystemDTO, 0, len(CountingSystems))
+	for _, system := range CountingSystems {
+		systems = append(systems, convertCountingSystemToDTO(system))
+	}
+	return systems
+}
+
+// StartCountingDrill 开始算牌练习
+func (s *GameApplicationService) StartCountingDrill(
+	systemName string,
+	deckCount int,
+	dealInterval time.Duration,
+	cardsPerQuestion int,
+) (*dtos.CountingDrillDTO, error) {
+	system, err := FindCountingSystem(systemName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.countingDrill = NewCountingDrill(CountingDrillConfig{
+		System:           system,
+		DeckCount:        deckCount,
+		DealInterval:     dealInterval,
+		CardsPerQuestion: cardsPerQuestion,
+	})
+	config := s.countingDrill.Config()
+
+	return &dtos.CountingDrillDTO{
+		System:           convertCountingSystemToDTO(config.System),
+		DeckCount:        config.DeckCount,
+		DealIntervalMs:   config.DealInterval.Milliseconds(),
+		CardsPerQuestion: config.CardsPerQuestion,
+		CardsToDeal:      s.countingDrill.cutIndex,
+	}, nil
+}
+
+// DealCountingCard 算牌练习发下一张牌
+func (s *GameApplicationService) DealCountingCard() (*dtos.CountingCardDTO, error) {
+	if s.countingDrill == nil {
+		return nil, errors.New("no active counting drill")
+	}
+
+	card, questionDue, err := s.countingDrill.DealNext()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.CountingCardDTO{
+		Card:           convertCardToDTO(card),
+		CardsDealt:     s.countingDrill.cardsDealt,
+		CardsRemaining: s.countingDrill.CardsRemaining(),
+		QuestionDue:    questionDue,
+		Finished:       s.countingDrill.IsFinished(),
+	}, nil
+}
+
+// AnswerCountingQuestion 回答当前流水数与真数
+func (s *GameApplicationService) AnswerCountingQuestion(runningCount int, trueCount float64) (*dtos.CountingAnswerDTO, error) {
+	if s.countingDrill == nil {
+		return nil, errors.New("no active counting drill")
+	}
+
+	question := s.countingDrill.Answer(runningCount, trueCount)
+	return &dtos.CountingAnswerDTO{
+		RunningCount:    question.RunningCount,
+		TrueCount:       question.TrueCount,
+		AnsweredRunning: question.AnsweredRunning,
+		AnsweredTrue:    question.AnsweredTrue,
+		TrueCountAsked:  question.TrueCountAsked,
+		RunningCorrect:  question.RunningCorrect,
+		TrueCorrect:     question.TrueCorrect,
+		ResponseSeconds: question.ResponseTime.Seconds(),
+	}, nil
+}
+
+// FinishCountingDrill 结束算牌练习并记入历史
+func (s *GameApplicationService) FinishCountingDrill() *dtos.CountingSessionDTO {
+	if s.countingDrill == nil {
+		return nil
+	}
+
+	session := s.countingDrill.Summary()
+	s.countingDrill = nil
+	s.countingHistory = append(s.countingHistory, session)
+
+	return convertCountingSessionToDTO(session)
+}
+
+// GetCountingHistory 获取算牌练习历史（速度与正确率）
+func (s *GameApplicationService) GetCountingHistory() []*dtos.CountingSessionDTO {
+	history := make([]*dtos.CountingSessionDTO, 0, len(s.countingHistory))
+	for _, session := range s.countingHistory {
+		history = append(history, convertCountingSessionToDTO(session))
+	}
+	return history
+}
+
+// 辅助函数：转换算牌系统到DTO
+func convertCountingSystemToDTO(system CountingSystem) *dtos.CountingSystemDTO {
+	return &dtos.CountingSystemDTO{
+		Name:     system.Name,
+		Balanced: system.Balanced,
+		Tags:     slices.Clone(system.Tags[aceValue:]),
+	}
+}
+
+// 辅助函数：转换算牌练习记录到DTO
+func convertCountingSessionToDTO(session CountingSession) *dtos.CountingSessionDTO {
+	return &dtos.CountingSessionDTO{
+		System:                 session.System,
+		DeckCount:              session.DeckCount,
+		StartedAt:              session.StartedAt,
+		CardsDealt:             session.CardsDealt,
+		CardsPerMinute:         session.CardsPerMinute(),
+		Questions:              session.Questions,
+		CorrectAnswers:         session.CorrectAnswers,
+		Accuracy:               session.Accuracy(),
+		AverageResponseSeconds: session.AverageAnswer.Seconds(),
+	}
+}
+
+// 辅助函数：转换决策评分到DTO
+func convertGradeToDTO(grade *DecisionGrade) *dtos.DecisionFeedbackDTO {
+	return &dtos.DecisionFeedbackDTO{
+		Category:     grade.Category.String(),
+		ChosenAction: grade.ChosenAction,
+		BestAction:   grade.BestAction,
+		ChosenEV:     grade.ChosenEV,
+		BestEV:       grade.BestEV,
+		EVCost:       grade.EVCost,
+		IsCorrect:    grade.IsCorrect,
+	}
+}
+
+// actionKey 推荐操作的名称
+func actionKey(action entities.PlayerAction) string {
+	switch action {
+	case entities.ActionHit:
+		return "hit"
+	case entities.ActionDoubleDown:
+		return "double"
+	case entities.ActionSplit:
+		return "split"
+	case entities.ActionSurrender:
+		return "surrender"
+	default:
+		return "stand"
 	}
 }
 
@@ -276,3 +939,17 @@ func convertCardToDTO(card entities.Card) *dtos.CardDTO {
 		Value: card.Value(),
 	}
 }
+
+// convertFairShoeToDTO 转换可验证公平牌靴到DTO
+func convertFairShoeToDTO(fair *entities.FairShoe) *dtos.FairShoeDTO {
+	return &dtos.FairShoeDTO{
+		Number:      fair.Number,
+		ClientSeed:  fair.ClientSeed,
+		Commitment:  fair.Commitment,
+		ServerSeed:  fair.ServerSeed,
+		DeckCount:   fair.DeckCount,
+		SpanishDeck: fair.SpanishDeck,
+		Excluded:    fair.Excluded,
+		Dealt:       fair.Dealt,
+	}
+}
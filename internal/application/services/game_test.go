@@ -0,0 +1,1334 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestPlaceBetTableLimits 测试下注金额按牌桌限额与筹码面额校验
+func TestPlaceBetTableLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		amount  int
+		wantErr string
+	}{
+		{"valid", 35, ""},
+		{"zero", 0, "positive"},
+		{"below_minimum", 5, "table minimum"},
+		{"above_maximum", 505, "table maximum"},
+		{"denomination", 37, "multiple of the 5 chip"},
+		{"at_maximum", 500, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test")
+			err := service.PlaceBet(tt.amount)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	rules := entities.DefaultRules()
+	rules.MaxBet = 0
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+	if err := service.PlaceBet(1005); err == nil || !strings.Contains(err.Error(), "available chips") {
+		t.Errorf("Expected insufficient chips error, got %v", err)
+	}
+}
+
+// TestKellyRecommendationPlaceable 测试凯利建议金额可以直接下注
+func TestKellyRecommendationPlaceable(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.ChipDenomination = 25
+	rules.MinBet = 25
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+
+	kelly := service.GetKellyBettingRecommendation()
+	if err := service.PlaceBet(kelly.RecommendedBetAmount); err != nil {
+		t.Fatalf("Expected Kelly bet %d to be placeable, got %v", kelly.RecommendedBetAmount, err)
+	}
+	if options := service.GetBetOptions(); options.LastBet != kelly.RecommendedBetAmount {
+		t.Errorf("Expected last bet %d, got %d", kelly.RecommendedBetAmount, options.LastBet)
+	}
+}
+
+// stackDeck 开局下注后按顺序叠放牌堆顶部的牌
+func stackDeck(t *testing.T, service *GameApplicationService, bet int, ranks ...entities.Rank) {
+	t.Helper()
+
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(bet); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	service.game.Deck.Cards = append(cardsOf(ranks...), service.game.Deck.Cards...)
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+}
+
+// TestDealerPeek 测试美式偷看规则下庄家Blackjack直接结算
+func TestDealerPeek(t *testing.T) {
+	t.Parallel()
+
+	service := NewGameApplicationService("test")
+	stackDeck(t, service, 10, entities.Ten, entities.Ace, entities.Nine, entities.King)
+	if service.GetGameState().State != entities.StateDealerTurn {
+		t.Fatal("Expected round to skip the player turn after peeking a blackjack")
+	}
+	if err := service.ProcessDealerTurn(); err != nil {
+		t.Fatalf("Unexpected dealer error: %v", err)
+	}
+	if result := service.EvaluateGame(); result.Type != entities.DealerBlackjack || result.PlayerChips != 990 {
+		t.Errorf("Expected dealer blackjack taking 10 chips, got %+v", result)
+	}
+
+	stackDeck(t, service, 10, entities.Ten, entities.Ace, entities.Nine, entities.Seven)
+	if service.GetGameState().State != entities.StatePlayerTurn {
+		t.Error("Expected player turn when the dealer has no blackjack")
+	}
+}
+
+// TestNoHoleCard 测试欧式无底牌规则下加倍遇到庄家Blackjack的损失
+func TestNoHoleCard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		holeCard entities.HoleCardRule
+		chips    int
+	}{
+		{"enhc", entities.HoleCardENHC, 980},
+		{"enhc_obo", entities.HoleCardENHCOBO, 990},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.HoleCard = tt.holeCard
+			service := NewGameApplicationService("test", entities.WithRules(rules))
+
+			stackDeck(t, service, 10, entities.Five, entities.Ace, entities.Six, entities.Ten, entities.King)
+			if cards := len(service.GetGameState().DealerHand.Cards); cards != 1 {
+				t.Fatalf("Expected dealer to hold only the upcard, got %d cards", cards)
+			}
+			if _, err := service.ProcessPlayerAction(entities.ActionDoubleDown); err != nil {
+				t.Fatalf("Unexpected double error: %v", err)
+			}
+			service.StartDealerTurn()
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+
+			result := service.EvaluateGame()
+			if result.Type != entities.DealerBlackjack || result.PlayerChips != tt.chips {
+				t.Errorf("Expected dealer blackjack leaving %d chips, got %+v", tt.chips, result)
+			}
+		})
+	}
+}
+
+// TestDealerSoft17 测试庄家软17按规则要牌或停牌
+func TestDealerSoft17(t *testing.T) {
+	t.Parallel()
+
+	for _, hitsSoft17 := range []bool{false, true} {
+		rules := entities.DefaultRules()
+		rules.DealerHitsSoft17 = hitsSoft17
+		service := NewGameApplicationService("test", entities.WithRules(rules))
+
+		stackDeck(t, service, 10, entities.Ten, entities.Ace, entities.Nine, entities.Six, entities.Two)
+		if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+			t.Fatalf("Unexpected stand error: %v", err)
+		}
+		service.StartDealerTurn()
+		if err := service.ProcessDealerTurn(); err != nil {
+			t.Fatalf("Unexpected dealer error: %v", err)
+		}
+
+		dealer := service.GetGameState().DealerHand
+		expectedCards := 2
+		if hitsSoft17 {
+			expectedCards = 3
+		}
+		if len(dealer.Cards) != expectedCards {
+			t.Errorf("h17=%v: expected dealer to hold %d cards, got %d", hitsSoft17, expectedCards, len(dealer.Cards))
+		}
+	}
+}
+
+// TestDoubleRestrictions 测试加倍点数与张数限制
+func TestDoubleRestrictions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		restriction entities.DoubleRestriction
+		anyCards    bool
+		player      []entities.Rank // 玩家前两张牌
+		hit         entities.Rank   // 非零时先要一张牌
+		canDouble   bool
+	}{
+		{"any total on 15", entities.DoubleAnyTotal, false, []entities.Rank{entities.Nine, entities.Six}, 0, true},
+		{"9-11 on 9", entities.DoubleNineToEleven, false, []entities.Rank{entities.Five, entities.Four}, 0, true},
+		{"9-11 on 15", entities.DoubleNineToEleven, false, []entities.Rank{entities.Nine, entities.Six}, 0, false},
+		{"10-11 on 9", entities.DoubleTenToEleven, false, []entities.Rank{entities.Five, entities.Four}, 0, false},
+		{"10-11 on soft 21", entities.DoubleTenToEleven, false, []entities.Rank{entities.Ace, entities.King}, 0, false},
+		{"three cards", entities.DoubleAnyTotal, false, []entities.Rank{entities.Two, entities.Three}, entities.Five, false},
+		{"three cards any-cards rule", entities.DoubleAnyTotal, true, []entities.Rank{entities.Two, entities.Three}, entities.Five, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.DoubleRestriction = tt.restriction
+			rules.DoubleAnyCards = tt.anyCards
+			service := NewGameApplicationService("test", entities.WithRules(rules))
+
+			ranks := []entities.Rank{tt.player[0], entities.Seven, tt.player[1], entities.Eight}
+			if tt.hit != 0 {
+				ranks = append(ranks, tt.hit)
+			}
+			stackDeck(t, service, 10, ranks...)
+			if tt.hit != 0 {
+				if _, err := service.ProcessPlayerAction(entities.ActionHit); err != nil {
+					t.Fatalf("Unexpected hit error: %v", err)
+				}
+			}
+
+			if got := service.CanPlayerDoubleDown(); got != tt.canDouble {
+				t.Errorf("Expected CanPlayerDoubleDown %v, got %v", tt.canDouble, got)
+			}
+			_, err := service.ProcessPlayerAction(entities.ActionDoubleDown)
+			if (err == nil) != tt.canDouble {
+				t.Errorf("Expected double allowed=%v, got error %v", tt.canDouble, err)
+			}
+		})
+	}
+}
+
+// TestDoubleForLess 测试少加倍：筹码不足原注时可追加较少金额，OBO下庄家Blackjack退回追加部分
+func TestDoubleForLess(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+	stackDeck(t, service, 20, entities.Five, entities.Seven, entities.Six, entities.Eight)
+	if _, err := service.ProcessDoubleDown(10); err == nil {
+		t.Error("Expected double for less to be rejected without the rule")
+	}
+
+	rules.DoubleForLess = true
+	rules.HoleCard = entities.HoleCardENHCOBO
+	service = NewGameApplicationService("test", entities.WithRules(rules))
+	stackDeck(t, service, 20, entities.Five, entities.Ace, entities.Six)
+	service.game.Player.Chips = 12
+
+	if amount := service.MaxDoubleAmount(); amount != 10 {
+		t.Fatalf("Expected max double of 10 with 12 chips, got %d", amount)
+	}
+	if _, err := service.ProcessDoubleDown(7); err == nil {
+		t.Error("Expected an off-chip double amount to be rejected")
+	}
+	service.game.Deck.Cards = append(cardsOf(entities.Nine, entities.King), service.game.Deck.Cards...)
+	if _, err := service.ProcessDoubleDown(10); err != nil {
+		t.Fatalf("Unexpected double error: %v", err)
+	}
+	if bet := service.GetGameState().PlayerBet; bet != 30 {
+		t.Errorf("Expected total bet of 30, got %d", bet)
+	}
+
+	service.StartDealerTurn()
+	if err := service.ProcessDealerTurn(); err != nil {
+		t.Fatalf("Unexpected dealer error: %v", err)
+	}
+	if result := service.EvaluateGame(); result.PlayerChips != 12 {
+		t.Errorf("Expected OBO to refund the 10 chip double, got %d chips", result.PlayerChips)
+	}
+}
+
+// TestAutoWinRules 测试查理与21点必胜规则下自动停牌并结算
+func TestAutoWinRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		apply    func(rules *entities.Rules)
+		deal     []entities.Rank // 玩家、庄家交替发牌
+		hits     []entities.Rank
+		expected entities.ResultType
+	}{
+		{
+			name:     "five card charlie",
+			apply:    func(rules *entities.Rules) { rules.CharlieCards = 5 },
+			deal:     []entities.Rank{entities.Two, entities.Ten, entities.Three, entities.Seven},
+			hits:     []entities.Rank{entities.Two, entities.Four, entities.Two},
+			expected: entities.PlayerCharlie,
+		},
+		{
+			name:     "player 21 always wins",
+			apply:    func(rules *entities.Rules) { rules.Player21AlwaysWins = true },
+			deal:     []entities.Rank{entities.Ten, entities.Ten, entities.Five, entities.Six},
+			hits:     []entities.Rank{entities.Six},
+			expected: entities.PlayerTwentyOne,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			tt.apply(&rules)
+			service := NewGameApplicationService("test", entities.WithRules(rules))
+			stackDeck(t, service, 10, append(tt.deal, tt.hits...)...)
+
+			for i := range tt.hits {
+				result, err := service.ProcessPlayerAction(entities.ActionHit)
+				if err != nil {
+					t.Fatalf("Unexpected hit error: %v", err)
+				}
+				if last := i == len(tt.hits)-1; result.Continue == last {
+					t.Fatalf("Hit %d: expected continue=%v", i+1, !last)
+				}
+			}
+			if service.GetGameState().State != entities.StateDealerTurn {
+				t.Fatal("Expected the player turn to end automatically")
+			}
+
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+			if dealer := service.GetGameState().DealerHand; len(dealer.Cards) != 2 {
+				t.Errorf("Expected the dealer not to draw, got %d cards", len(dealer.Cards))
+			}
+			result := service.EvaluateGame()
+			if result.Type != tt.expected {
+				t.Errorf("Expected result %v, got %v", tt.expected, result.Type)
+			}
+			if result.PlayerChips != 1010 {
+				t.Errorf("Expected an even-money win, got %d chips", result.PlayerChips)
+			}
+		})
+	}
+}
+
+// TestSpanishShoe 测试西班牙牌靴去掉10点数字牌
+func TestSpanishShoe(t *testing.T) {
+	t.Parallel()
+
+	service := NewGameApplicationService("test", entities.WithRules(entities.Spanish21Rules()))
+	cards := service.game.Deck.Cards
+	if len(cards) != 6*48 {
+		t.Fatalf("Expected %d cards, got %d", 6*48, len(cards))
+	}
+	for _, card := range cards {
+		if card.Rank == entities.Ten {
+			t.Fatal("Spanish shoe should not contain any ten")
+		}
+	}
+}
+
+// TestSurrender 测试后投降与加倍救援的结算
+func TestSurrender(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ranks    []entities.Rank // 玩家、庄家交替发牌，随后为加倍的牌
+		double   bool
+		expected int
+	}{
+		{
+			name:     "late surrender loses half the bet",
+			ranks:    []entities.Rank{entities.Ten, entities.Ten, entities.Six, entities.Nine},
+			expected: 990,
+		},
+		{
+			name:     "rescue loses the original bet",
+			ranks:    []entities.Rank{entities.Five, entities.Ten, entities.Six, entities.Nine, entities.Two},
+			double:   true,
+			expected: 980,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.Spanish21Rules()))
+			stackDeck(t, service, 20, tt.ranks...)
+
+			if tt.double {
+				result, err := service.ProcessPlayerAction(entities.ActionDoubleDown)
+				if err != nil {
+					t.Fatalf("Unexpected double error: %v", err)
+				}
+				if !result.Continue {
+					t.Fatal("Expected the rescue option after doubling")
+				}
+				if service.CanPlayerHit() {
+					t.Error("Should not hit after doubling")
+				}
+			}
+
+			if !service.CanPlayerSurrender() {
+				t.Fatal("Expected surrender to be allowed")
+			}
+			result, err := service.ProcessPlayerAction(entities.ActionSurrender)
+			if err != nil {
+				t.Fatalf("Unexpected surrender error: %v", err)
+			}
+			if result.Continue {
+				t.Error("Expected the player turn to end after surrendering")
+			}
+
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+			game := service.EvaluateGame()
+			if game.Type != entities.PlayerSurrender {
+				t.Errorf("Expected surrender result, got %v", game.Type)
+			}
+			if game.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, game.PlayerChips)
+			}
+		})
+	}
+}
+
+// TestSplitHandSurrender 测试分牌后的手牌不推荐也不评分投降
+func TestSplitHandSurrender(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.LateSurrender = true
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+	stackDeck(t, service, 10, entities.Eight, entities.Ten, entities.Eight, entities.Seven, entities.Seven, entities.Seven)
+
+	if !service.GetStrategyEVs(StrategyBasic).CanSurrender {
+		t.Fatal("Expected surrender available before splitting")
+	}
+	if _, err := service.ProcessPlayerAction(entities.ActionSplit); err != nil {
+		t.Fatalf("Unexpected split error: %v", err)
+	}
+	if service.CanPlayerSurrender() {
+		t.Fatal("Should not surrender a split hand")
+	}
+
+	// 分牌后第一手为8-7共15点，对庄家10点本应投降
+	for _, source := range []StrategySource{StrategyBasic, StrategyComposition} {
+		evs := service.GetStrategyEVs(source)
+		if evs.CanSurrender {
+			t.Errorf("Expected surrender unavailable on a split hand for %v", source)
+		}
+		if action, _ := evs.Best(); action == entities.ActionSurrender {
+			t.Errorf("Expected a playable action on a split hand for %v, got surrender", source)
+		}
+	}
+	if feedback := service.gradePlayerAction(entities.ActionSurrender); feedback != nil {
+		t.Errorf("Expected surrender not graded on a split hand, got %+v", feedback)
+	}
+}
+
+// TestSpanishBonus 测试西班牙21点奖励赔付与Blackjack对庄家Blackjack
+func TestSpanishBonus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cards    []entities.Card // 玩家、庄家交替发牌，随后为要牌
+		hits     int
+		result   entities.ResultType
+		expected int
+	}{
+		{
+			name: "suited 6-7-8 pays 2:1",
+			cards: []entities.Card{
+				{Suit: entities.Hearts, Rank: entities.Six},
+				{Suit: entities.Clubs, Rank: entities.King},
+				{Suit: entities.Hearts, Rank: entities.Seven},
+				{Suit: entities.Clubs, Rank: entities.Nine},
+				{Suit: entities.Hearts, Rank: entities.Eight},
+			},
+			hits:     1,
+			result:   entities.PlayerBonus,
+			expected: 1040,
+		},
+		{
+			name: "five card 21 pays 3:2",
+			cards: []entities.Card{
+				{Suit: entities.Hearts, Rank: entities.Two},
+				{Suit: entities.Clubs, Rank: entities.King},
+				{Suit: entities.Spades, Rank: entities.Three},
+				{Suit: entities.Clubs, Rank: entities.Nine},
+				{Suit: entities.Hearts, Rank: entities.Four},
+				{Suit: entities.Diamonds, Rank: entities.Five},
+				{Suit: entities.Clubs, Rank: entities.Seven},
+			},
+			hits:     3,
+			result:   entities.PlayerBonus,
+			expected: 1030,
+		},
+		{
+			name: "blackjack beats dealer blackjack",
+			cards: []entities.Card{
+				{Suit: entities.Hearts, Rank: entities.Ace},
+				{Suit: entities.Clubs, Rank: entities.Ace},
+				{Suit: entities.Spades, Rank: entities.King},
+				{Suit: entities.Clubs, Rank: entities.Queen},
+			},
+			result:   entities.PlayerBlackjack,
+			expected: 1030,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.Spanish21Rules()))
+			if err := service.StartNewRound(); err != nil {
+				t.Fatalf("Unexpected start error: %v", err)
+			}
+			if err := service.PlaceBet(20); err != nil {
+				t.Fatalf("Unexpected bet error: %v", err)
+			}
+			service.game.Deck.Cards = append(tt.cards, service.game.Deck.Cards...)
+			if err := service.DealInitialCards(); err != nil {
+				t.Fatalf("Unexpected deal error: %v", err)
+			}
+
+			for range tt.hits {
+				if _, err := service.ProcessPlayerAction(entities.ActionHit); err != nil {
+					t.Fatalf("Unexpected hit error: %v", err)
+				}
+			}
+			if service.GetGameState().State == entities.StatePlayerTurn {
+				service.StartDealerTurn()
+			}
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+
+			result := service.EvaluateGame()
+			if result.Type != tt.result {
+				t.Errorf("Expected result %v, got %v", tt.result, result.Type)
+			}
+			if result.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, result.PlayerChips)
+			}
+		})
+	}
+}
+
+// TestBlackjackSwitch 测试换牌玩法的换牌、Blackjack 1:1赔付与庄家22点平局
+func TestBlackjackSwitch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ranks    []entities.Rank // 每轮依次发给第一手、第二手、庄家，随后为庄家要牌
+		switched bool
+		results  []entities.ResultType
+		expected int
+	}{
+		{
+			name: "switched ace-ten is only 21",
+			ranks: []entities.Rank{
+				entities.Ace, entities.Nine, entities.Nine,
+				entities.Six, entities.Ten, entities.Eight,
+			},
+			switched: true,
+			results:  []entities.ResultType{entities.PlayerWin, entities.DealerWin},
+			expected: 1000,
+		},
+		{
+			name: "blackjack pays even money",
+			ranks: []entities.Rank{
+				entities.Ace, entities.Ten, entities.Nine,
+				entities.King, entities.Nine, entities.Eight,
+			},
+			results:  []entities.ResultType{entities.PlayerBlackjack, entities.PlayerWin},
+			expected: 1040,
+		},
+		{
+			name: "dealer 22 pushes",
+			ranks: []entities.Rank{
+				entities.Ten, entities.Ten, entities.Six,
+				entities.Eight, entities.Seven, entities.Ten,
+				entities.Six,
+			},
+			results:  []entities.ResultType{entities.Dealer22Push, entities.Dealer22Push},
+			expected: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.BlackjackSwitchRules()))
+			stackDeck(t, service, 20, tt.ranks...)
+			if !service.CanPlayerSwitch() {
+				t.Fatal("Expected switching to be allowed before acting")
+			}
+
+			if tt.switched {
+				before := service.GetGameState().PlayerHands
+				firstSecond, secondSecond := before[1].Hand.Cards[1], before[0].Hand.Cards[1]
+				if _, err := service.ProcessPlayerAction(entities.ActionSwitch); err != nil {
+					t.Fatalf("Unexpected switch error: %v", err)
+				}
+				after := service.GetGameState().PlayerHands
+				if *after[0].Hand.Cards[1] != *firstSecond || *after[1].Hand.Cards[1] != *secondSecond {
+					t.Fatal("Expected the second cards to be swapped")
+				}
+				if service.CanPlayerSwitch() {
+					t.Error("Should not switch twice")
+				}
+			}
+
+			for service.GetGameState().State == entities.StatePlayerTurn {
+				if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+					t.Fatalf("Unexpected stand error: %v", err)
+				}
+			}
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+
+			game := service.EvaluateGame()
+			if len(game.Hands) != len(tt.results) {
+				t.Fatalf("Expected %d hand results, got %d", len(tt.results), len(game.Hands))
+			}
+			for i, hand := range game.Hands {
+				if hand.Type != tt.results[i] {
+					t.Errorf("Hand %d: expected %v, got %v", i+1, tt.results[i], hand.Type)
+				}
+			}
+			if game.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, game.PlayerChips)
+			}
+		})
+	}
+}
+
+// TestSwitchSplitLimit 测试换牌玩法每手初始牌分别计算分牌上限
+func TestSwitchSplitLimit(t *testing.T) {
+	t.Parallel()
+
+	service := NewGameApplicationService("test", entities.WithRules(entities.BlackjackSwitchRules()))
+	stackDeck(t, service, 10,
+		entities.Eight, entities.Nine, entities.Ten,
+		entities.Eight, entities.Nine, entities.Seven,
+		entities.Eight, entities.Eight, entities.Eight,
+		entities.Ten, entities.Ten, entities.Ten, entities.Ten)
+
+	// 第一手连续分牌到4手
+	for split := range entities.MaxSplitHands - 1 {
+		if !service.CanPlayerSplit() {
+			t.Fatalf("Expected split %d of the first hand to be allowed", split+1)
+		}
+		if _, err := service.ProcessPlayerAction(entities.ActionSplit); err != nil {
+			t.Fatalf("Unexpected split error: %v", err)
+		}
+	}
+	if service.CanPlayerSplit() {
+		t.Fatalf("Expected the first hand to stop at %d split hands", entities.MaxSplitHands)
+	}
+
+	for range entities.MaxSplitHands {
+		if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+			t.Fatalf("Unexpected stand error: %v", err)
+		}
+	}
+	if !service.CanPlayerSplit() {
+		t.Error("Expected the second hand to keep its own split limit")
+	}
+}
+
+// TestFreeBet 测试免费加倍与免费分牌的注码只在赢时赔付
+func TestFreeBet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ranks    []entities.Rank // 玩家、庄家交替发牌，随后为玩家补牌与庄家要牌
+		action   entities.PlayerAction
+		expected int
+	}{
+		{
+			name:     "free double win pays both bets",
+			ranks:    []entities.Rank{entities.Six, entities.Ten, entities.Five, entities.Seven, entities.Ten},
+			action:   entities.ActionDoubleDown,
+			expected: 1040,
+		},
+		{
+			name:     "free double loss costs the original bet",
+			ranks:    []entities.Rank{entities.Six, entities.Ten, entities.Five, entities.Seven, entities.Two},
+			action:   entities.ActionDoubleDown,
+			expected: 980,
+		},
+		{
+			name: "free split win pays both hands",
+			ranks: []entities.Rank{
+				entities.Eight, entities.Ten, entities.Eight, entities.Six,
+				entities.Ten, entities.Ten, entities.Ten,
+			},
+			action:   entities.ActionSplit,
+			expected: 1040,
+		},
+		{
+			name: "free split loss costs the original bet",
+			ranks: []entities.Rank{
+				entities.Eight, entities.Ten, entities.Eight, entities.Nine,
+				entities.Ten, entities.Ten,
+			},
+			action:   entities.ActionSplit,
+			expected: 980,
+		},
+		{
+			name: "dealer 22 pushes the split hands",
+			ranks: []entities.Rank{
+				entities.Eight, entities.Ten, entities.Eight, entities.Six,
+				entities.Ten, entities.Ten, entities.Six,
+			},
+			action:   entities.ActionSplit,
+			expected: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.FreeBetRules()))
+			stackDeck(t, service, 20, tt.ranks...)
+
+			switch tt.action {
+			case entities.ActionDoubleDown:
+				if !service.IsFreeDouble() {
+					t.Fatal("Expected a free double on hard 11")
+				}
+			case entities.ActionSplit:
+				if !service.IsFreeSplit() {
+					t.Fatal("Expected a free split on eights")
+				}
+			}
+			if _, err := service.ProcessPlayerAction(tt.action); err != nil {
+				t.Fatalf("Unexpected action error: %v", err)
+			}
+			if chips := service.GetGameState().PlayerChips; chips != 980 {
+				t.Errorf("Expected the free bet to cost nothing up front, got %d chips", chips)
+			}
+
+			for service.GetGameState().State == entities.StatePlayerTurn {
+				if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+					t.Fatalf("Unexpected stand error: %v", err)
+				}
+			}
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+			if game := service.EvaluateGame(); game.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, game.PlayerChips)
+			}
+		})
+	}
+}
+
+// TestDoubleExposure 测试双明牌规则下平局庄家赢、Blackjack赔1:1并胜过庄家Blackjack
+func TestDoubleExposure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ranks    []entities.Rank // 玩家、庄家交替发牌
+		result   entities.ResultType
+		expected int
+	}{
+		{
+			name:     "tie loses",
+			ranks:    []entities.Rank{entities.Ten, entities.Ten, entities.Eight, entities.Eight},
+			result:   entities.DealerWin,
+			expected: 980,
+		},
+		{
+			name:     "blackjack pays even money",
+			ranks:    []entities.Rank{entities.Ace, entities.Ten, entities.King, entities.Seven},
+			result:   entities.PlayerBlackjack,
+			expected: 1020,
+		},
+		{
+			name:     "blackjack beats dealer blackjack",
+			ranks:    []entities.Rank{entities.Ace, entities.Ace, entities.King, entities.Queen},
+			result:   entities.PlayerBlackjack,
+			expected: 1020,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.DoubleExposureRules()))
+			stackDeck(t, service, 20, tt.ranks...)
+			if service.GetGameState().State == entities.StatePlayerTurn {
+				if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+					t.Fatalf("Unexpected stand error: %v", err)
+				}
+			}
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+
+			game := service.EvaluateGame()
+			if game.Type != tt.result {
+				t.Errorf("Expected %v, got %v", tt.result, game.Type)
+			}
+			if game.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, game.PlayerChips)
+			}
+		})
+	}
+}
+
+func TestPontoon(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ranks    []entities.Rank // 玩家、庄家交替发牌，之后为要牌顺序
+		actions  []entities.PlayerAction
+		result   entities.ResultType
+		expected int
+	}{
+		{
+			name:     "tie loses",
+			ranks:    []entities.Rank{entities.Ten, entities.Ten, entities.Eight, entities.Eight},
+			actions:  []entities.PlayerAction{entities.ActionStand},
+			result:   entities.DealerWin,
+			expected: 980,
+		},
+		{
+			name:     "pontoon pays two to one",
+			ranks:    []entities.Rank{entities.Ace, entities.Ten, entities.King, entities.Seven},
+			result:   entities.PlayerPontoon,
+			expected: 1040,
+		},
+		{
+			name:     "dealer pontoon beats player pontoon",
+			ranks:    []entities.Rank{entities.Ace, entities.Ace, entities.King, entities.Queen},
+			result:   entities.DealerPontoon,
+			expected: 980,
+		},
+		{
+			name:     "five card trick pays two to one",
+			ranks:    []entities.Rank{entities.Two, entities.Ten, entities.Three, entities.Seven, entities.Two, entities.Three, entities.Four},
+			actions:  []entities.PlayerAction{entities.ActionHit, entities.ActionHit, entities.ActionHit},
+			result:   entities.PlayerFiveCardTrick,
+			expected: 1040,
+		},
+		{
+			name:     "buy then twist",
+			ranks:    []entities.Rank{entities.Five, entities.Ten, entities.Four, entities.Seven, entities.Three, entities.Six},
+			actions:  []entities.PlayerAction{entities.ActionDoubleDown, entities.ActionHit, entities.ActionStand},
+			result:   entities.PlayerWin,
+			expected: 1040,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewGameApplicationService("test", entities.WithRules(entities.PontoonRules()))
+			stackDeck(t, service, 20, tt.ranks...)
+			for _, action := range tt.actions {
+				if _, err := service.ProcessPlayerAction(action); err != nil {
+					t.Fatalf("Unexpected %v error: %v", action, err)
+				}
+			}
+			if service.GetGameState().State == entities.StateDealerTurn {
+				if err := service.ProcessDealerTurn(); err != nil {
+					t.Fatalf("Unexpected dealer error: %v", err)
+				}
+			}
+
+			game := service.EvaluateGame()
+			if game.Type != tt.result {
+				t.Errorf("Expected %v, got %v", tt.result, game.Type)
+			}
+			if game.PlayerChips != tt.expected {
+				t.Errorf("Expected %d chips, got %d", tt.expected, game.PlayerChips)
+			}
+		})
+	}
+}
+
+func TestPontoonMinimumStick(t *testing.T) {
+	t.Parallel()
+
+	// 买牌后12点：不能停牌，仍可继续要牌，但不能再买
+	service := NewGameApplicationService("test", entities.WithRules(entities.PontoonRules()))
+	stackDeck(t, service, 20, entities.Five, entities.Ten, entities.Four, entities.Seven, entities.Three)
+	if _, err := service.ProcessPlayerAction(entities.ActionDoubleDown); err != nil {
+		t.Fatalf("Unexpected buy error: %v", err)
+	}
+
+	if service.CanPlayerStand() {
+		t.Error("Expected sticking on 12 to be refused")
+	}
+	if _, err := service.ProcessPlayerAction(entities.ActionStand); err == nil {
+		t.Error("Expected an error when sticking below 15")
+	}
+	if !service.CanPlayerHit() {
+		t.Error("Expected twisting to be allowed after a buy")
+	}
+	if service.CanPlayerDoubleDown() {
+		t.Error("Expected only one buy per hand")
+	}
+}
+
+func TestContinuousShuffle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		continuous bool
+		remaining  int
+	}{
+		// stackDeck在整副牌之前叠放了4张牌，牌靴共56张
+		{name: "cut card shoe keeps discards out", continuous: false, remaining: 52},
+		{name: "continuous shuffler returns discards", continuous: true, remaining: 56},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.ContinuousShuffle = tt.continuous
+			service := NewGameApplicationService("test", entities.WithRules(rules))
+			stackDeck(t, service, 10, entities.Ten, entities.Ten, entities.Nine, entities.Eight)
+			if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+				t.Fatalf("Unexpected stand error: %v", err)
+			}
+			if err := service.ProcessDealerTurn(); err != nil {
+				t.Fatalf("Unexpected dealer error: %v", err)
+			}
+			service.EvaluateGame()
+
+			if remaining := len(service.game.GetRemainingCards()); remaining != tt.remaining {
+				t.Errorf("Expected %d cards in the shoe after settlement, got %d", tt.remaining, remaining)
+			}
+		})
+	}
+}
+
+func TestShoeRunsOutMidRound(t *testing.T) {
+	t.Parallel()
+
+	// 牌靴只剩发初始牌的4张，要牌时换新牌靴并去掉桌上的牌
+	service := NewGameApplicationService("test")
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	service.game.Deck.Cards = cardsOf(entities.Ten, entities.Ten, entities.Two, entities.Seven)
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+	if _, err := service.ProcessPlayerAction(entities.ActionHit); err != nil {
+		t.Fatalf("Unexpected hit error: %v", err)
+	}
+
+	if remaining := len(service.game.GetRemainingCards()); remaining != 47 {
+		t.Errorf("Expected a fresh shoe without the 5 cards on the table, got %d cards", remaining)
+	}
+}
+
+func TestDiscardTray(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.BurnCard = true
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+	if tray := service.GetDiscardTray(); tray.Burned != 1 || tray.CardsRemaining != 51 {
+		t.Fatalf("Expected one burned card and 51 cards in the shoe, got %d and %d", tray.Burned, tray.CardsRemaining)
+	}
+	if remaining := len(service.game.GetRemainingCards()); remaining != 52 {
+		t.Errorf("Expected the burned card to count as unseen, got %d remaining cards", remaining)
+	}
+
+	seen := func(rank entities.Rank) int {
+		return service.GetDiscardTray().Seen[rank-entities.Ace].Count
+	}
+
+	// 玩家回合庄家底牌8还没翻开
+	stackDeck(t, service, 10, entities.Ten, entities.Ten, entities.Nine, entities.Eight)
+	if seen(entities.Ten) != 2 || seen(entities.Nine) != 1 || seen(entities.Eight) != 0 {
+		t.Errorf("Expected the hole card to stay unseen, got %d tens, %d nines, %d eights",
+			seen(entities.Ten), seen(entities.Nine), seen(entities.Eight))
+	}
+	if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+		t.Fatalf("Unexpected stand error: %v", err)
+	}
+	if err := service.ProcessDealerTurn(); err != nil {
+		t.Fatalf("Unexpected dealer error: %v", err)
+	}
+	service.EvaluateGame()
+
+	// 下一局开始时上一局的牌进入弃牌架
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if tray := service.GetDiscardTray(); tray.Discarded != 4 || seen(entities.Eight) != 1 {
+		t.Errorf("Expected the 4 cards of the last round in the tray, got %d", tray.Discarded)
+	}
+
+	// 换牌靴时弃牌架清空，重新烧牌
+	service.game.Deck.Cards = service.game.Deck.Cards[:5]
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if tray := service.GetDiscardTray(); tray.Discarded != 0 || tray.Burned != 1 || tray.CardsRemaining != 51 {
+		t.Errorf("Expected an emptied tray with one burned card after the shuffle, got %d discarded and %d burned",
+			tray.Discarded, tray.Burned)
+	}
+}
+
+// TestShoeComposition 测试牌靴组成、渗透率与移除效应
+func TestShoeComposition(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	scenario := &entities.Scenario{Rules: rules, Cards: cardsOf(entities.Five, entities.Ten, entities.Five, entities.Nine)}
+	service := NewGameApplicationService("test", entities.WithScenario(scenario))
+	fresh := service.GetShoeComposition()
+	if fresh.CardsRemaining != 52 || fresh.DecksRemaining != 1 || fresh.Penetration != 0 {
+		t.Fatalf("Expected a full single-deck shoe, got %d cards, %.2f decks, %.2f penetration",
+			fresh.CardsRemaining, fresh.DecksRemaining, fresh.Penetration)
+	}
+	if len(fresh.Ranks) != 13 || fresh.Ranks[0].Remaining != 4 || fresh.Ranks[0].Percent != fresh.Ranks[0].FreshPercent {
+		t.Errorf("Expected 4 aces matching the fresh shoe, got %+v", fresh.Ranks[0])
+	}
+	if want := NewEVCalculator(rules).OffTheTopEV(); math.Abs(fresh.PlayerEdge-want) > 1e-12 {
+		t.Errorf("Expected the off-the-top edge %.6f for a fresh shoe, got %.6f", want, fresh.PlayerEdge)
+	}
+
+	// 完整牌靴中移除A对玩家不利，移除5对玩家最有利
+	effects := make(map[string]float64)
+	for _, effect := range fresh.EffectsOfRemoval {
+		effects[effect.Rank] = effect.Effect
+	}
+	if effects["A"] >= 0 || effects["10"] >= 0 {
+		t.Errorf("Expected removing an ace or a ten to hurt the player, got %.4f and %.4f", effects["A"], effects["10"])
+	}
+	for rank, effect := range effects {
+		if effect > effects["5"] {
+			t.Errorf("Expected the 5 to have the largest effect of removal, got %s with %.4f", rank, effect)
+		}
+	}
+
+	// 上一局的牌进入弃牌架后不再计入余牌
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+
+	// 玩家回合庄家底牌还没翻开，仍计入没看到的牌
+	during := service.GetShoeComposition()
+	if during.CardsRemaining != 49 {
+		t.Errorf("Expected 49 unseen cards with the hole card hidden, got %d", during.CardsRemaining)
+	}
+	if nines := during.Ranks[entities.Nine-entities.Ace]; nines.Remaining != 4 {
+		t.Errorf("Expected the hidden 9 counted as unseen, got %d nines left", nines.Remaining)
+	}
+
+	if _, err := service.ProcessPlayerAction(entities.ActionStand); err != nil {
+		t.Fatalf("Unexpected stand error: %v", err)
+	}
+	if err := service.ProcessDealerTurn(); err != nil {
+		t.Fatalf("Unexpected dealer error: %v", err)
+	}
+	service.EvaluateGame()
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+
+	composition := service.GetShoeComposition()
+	if composition.CardsRemaining != 48 || composition.Discarded != 4 {
+		t.Fatalf("Expected 48 cards left and 4 discards, got %d and %d", composition.CardsRemaining, composition.Discarded)
+	}
+	if math.Abs(composition.Penetration-4.0/52) > 1e-12 {
+		t.Errorf("Expected penetration 4/52, got %.4f", composition.Penetration)
+	}
+	if fives := composition.Ranks[entities.Five-entities.Ace]; fives.Remaining != 2 || fives.Fresh != 4 {
+		t.Errorf("Expected 2 of 4 fives left, got %d of %d", fives.Remaining, fives.Fresh)
+	}
+	// 两张5出局对玩家有利
+	if composition.PlayerEdge <= fresh.PlayerEdge {
+		t.Errorf("Expected the edge to rise once two fives are gone, got %.4f%% from %.4f%%",
+			composition.PlayerEdge*100, fresh.PlayerEdge*100)
+	}
+
+	// Pontoon庄家两张都是暗牌
+	pontoon := &entities.Scenario{Rules: entities.PontoonRules(), Cards: cardsOf(entities.Five, entities.Ten, entities.Five, entities.Nine)}
+	service = NewGameApplicationService("test", entities.WithScenario(pontoon))
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+	if unseen := service.GetShoeComposition().CardsRemaining; unseen != 50 {
+		t.Errorf("Expected both Pontoon dealer cards unseen, got %d unseen cards", unseen)
+	}
+}
+
+// TestProvablyFair 测试可验证公平牌靴：承诺在客户端种子确定之前公布，更换的种子从下一个牌靴生效，公开后可验证
+func TestProvablyFair(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.ProvablyFair = true
+	rules.BurnCard = true
+	service := NewGameApplicationService("test", entities.WithRules(rules))
+	committed := service.GetFairCommitment()
+	if committed == nil || committed.Number != 1 || committed.ServerSeed != "" || committed.ClientSeed != "" || len(committed.Commitment) != 64 {
+		t.Fatalf("Expected a commitment for shoe #1 before any client seed, got %+v", committed)
+	}
+	if service.GetFairShoe() != nil {
+		t.Fatal("Expected no shoe before the player sets a client seed")
+	}
+
+	// 没有客户端种子时不能洗出牌靴，也没有服务器选择的默认种子
+	if err := service.StartNewRound(); err == nil {
+		t.Fatal("Expected an error when starting without a client seed")
+	}
+	if err := service.SetClientSeed(""); err == nil {
+		t.Error("Expected an error for an empty client seed")
+	}
+	if err := service.SetClientSeed("lucky"); err != nil {
+		t.Fatalf("Unexpected seed error: %v", err)
+	}
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	shoe := service.GetFairShoe()
+	if shoe == nil || shoe.Commitment != committed.Commitment || shoe.ClientSeed != "lucky" {
+		t.Fatalf("Expected shoe #1 to use the published commitment and the player's seed, got %+v", shoe)
+	}
+	next := service.GetFairCommitment()
+	if next == nil || next.Number != 2 || next.Commitment == committed.Commitment {
+		t.Fatalf("Expected a fresh commitment for shoe #2, got %+v", next)
+	}
+
+	// 更换的客户端种子从下一个牌靴开始生效
+	if err := service.SetClientSeed("rotated"); err != nil {
+		t.Fatalf("Unexpected seed error: %v", err)
+	}
+	if service.GetFairShoe().ClientSeed != "lucky" {
+		t.Error("Expected the current shoe to keep its client seed")
+	}
+
+	// 发掉大部分牌后下一局换牌靴，上一个牌靴公开服务器种子
+	for range 42 {
+		if _, err := service.game.Deck.Deal(); err != nil {
+			t.Fatalf("Unexpected deal error: %v", err)
+		}
+	}
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	history := service.GetFairHistory()
+	if shoe := service.GetFairShoe(); len(history) != 1 || shoe.Number != 2 || shoe.Commitment != next.Commitment || shoe.ClientSeed != "rotated" {
+		t.Fatalf("Expected the first shoe revealed and shoe #2 dealt with the rotated seed, got %d revealed", len(history))
+	}
+	revealed := history[0]
+	if revealed.Commitment != committed.Commitment || len(revealed.Dealt) != 43 {
+		t.Fatalf("Expected the revealed shoe to keep its commitment and record 43 dealt cards, got %d", len(revealed.Dealt))
+	}
+
+	cards, err := VerifyFairShoe(revealed)
+	if err != nil {
+		t.Fatalf("Unexpected verify error: %v", err)
+	}
+	if len(cards) != 52 {
+		t.Errorf("Expected the full 52-card shoe reproduced, got %d cards", len(cards))
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(record *dtos.FairShoeDTO)
+	}{
+		{"client seed", func(record *dtos.FairShoeDTO) { record.ClientSeed = "unlucky" }},
+		{"commitment", func(record *dtos.FairShoeDTO) { record.Commitment = committed.Commitment[1:] + "0" }},
+		{"dealt card", func(record *dtos.FairShoeDTO) { record.Dealt[0], record.Dealt[1] = record.Dealt[1], record.Dealt[0] }},
+		{"unrevealed", func(record *dtos.FairShoeDTO) { record.ServerSeed = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			record := *revealed
+			record.Dealt = slices.Clone(revealed.Dealt)
+			tt.tamper(&record)
+			if _, err := VerifyFairShoe(&record); err == nil {
+				t.Errorf("Expected a tampered %s to fail verification", tt.name)
+			}
+		})
+	}
+
+	// 局中换牌靴时留在桌上的牌从承诺的基础牌序中去掉后再洗
+	fair, err := entities.NewFairCommitment(rules, 3)
+	if err != nil {
+		t.Fatalf("Unexpected commitment error: %v", err)
+	}
+	deck, err := fair.Shuffle("lucky", cardsOf(entities.Ace, entities.King))
+	if err != nil {
+		t.Fatalf("Unexpected shuffle error: %v", err)
+	}
+	for range 5 {
+		if _, err := deck.Deal(); err != nil {
+			t.Fatalf("Unexpected deal error: %v", err)
+		}
+	}
+	fair.Reveal(deck)
+	if cards, err := VerifyFairShoe(convertFairShoeToDTO(fair)); err != nil || len(cards) != 50 {
+		t.Errorf("Expected a 50-card shoe without the excluded cards to verify, got %d cards and %v", len(cards), err)
+	}
+}
+
+// TestInfiniteDeckReference 测试概率分析附带的无限副牌参考
+func TestInfiniteDeckReference(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	scenario := &entities.Scenario{Rules: rules, Cards: cardsOf(entities.Ten, entities.Seven, entities.Six, entities.Ten)}
+	service := NewGameApplicationService("test", entities.WithScenario(scenario))
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+
+	if service.CalculateWinProbabilities().InfiniteDeck != nil {
+		t.Error("Expected no infinite deck reference unless enabled")
+	}
+
+	service.SetInfiniteDeckReference(true)
+	reference := service.CalculateWinProbabilities().InfiniteDeck
+	if reference == nil {
+		t.Fatal("Expected an infinite deck reference")
+	}
+
+	// 两次分析都是精确计算，结果完全相同
+	if again := service.CalculateWinProbabilities().InfiniteDeck; *again != *reference {
+		t.Errorf("Expected a stable reference, got %+v then %+v", *reference, *again)
+	}
+
+	// 16对7：无限副牌按固定概率计算，当前牌堆已移除两张10点牌，要牌更有利
+	_, want := NewEVCalculator(rules, WithEVInfiniteDeck()).CalculateBasicStrategyEVs(
+		cardsOf(entities.Ten, entities.Six), entities.Card{Rank: entities.Seven}, true, false, false).Best()
+	if math.Abs(reference.ExpectedValue-want) > 1e-12 {
+		t.Errorf("Expected infinite deck EV %f, got %f", want, reference.ExpectedValue)
+	}
+	if reference.RecommendedAction != "hit" {
+		t.Errorf("Expected hit against a 7, got %s", reference.RecommendedAction)
+	}
+	if effect := reference.ShoeExpectedValue - reference.ExpectedValue; math.Abs(effect-reference.CompositionEffect) > 1e-12 {
+		t.Errorf("Expected composition effect %f, got %f", effect, reference.CompositionEffect)
+	}
+	if reference.CompositionEffect <= 0 || reference.CompositionEffect > 0.05 {
+		t.Errorf("Expected a small positive composition effect, got %f", reference.CompositionEffect)
+	}
+}
+
+// TestSplitAces 测试分A规则：默认只补一张且不能再分，可再分A与分A后要牌规则下可以继续行动
+func TestSplitAces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		resplit    bool
+		hit        bool
+		playerTurn bool // 分牌后是否仍轮到玩家行动
+	}{
+		{"one_card", false, false, false},
+		{"resplit", true, false, true},
+		{"hit_split_aces", false, true, true},
+		{"resplit_and_hit", true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.DefaultRules()
+			rules.ResplitAces = tt.resplit
+			rules.HitSplitAces = tt.hit
+			rules.DoubleAfterSplit = true
+			service := NewGameApplicationService("test", entities.WithRules(rules))
+			// 玩家A-A对庄家6，分牌后第一手补到A，第二手补到5
+			stackDeck(t, service, 10, entities.Ace, entities.Six, entities.Ace, entities.Nine, entities.Ace, entities.Five, entities.Four, entities.Three)
+			if _, err := service.ProcessPlayerAction(entities.ActionSplit); err != nil {
+				t.Fatalf("Unexpected split error: %v", err)
+			}
+
+			if playerTurn := service.game.State == entities.StatePlayerTurn; playerTurn != tt.playerTurn {
+				t.Fatalf("Expected player turn %v after splitting aces, got %v", tt.playerTurn, playerTurn)
+			}
+			if !tt.playerTurn {
+				return
+			}
+			if service.CanPlayerSplit() != tt.resplit {
+				t.Errorf("Expected can resplit A-A %v, got %v", tt.resplit, service.CanPlayerSplit())
+			}
+			if service.CanPlayerHit() != tt.hit || service.CanPlayerDoubleDown() != tt.hit {
+				t.Errorf("Expected hit and double on split aces %v, got %v and %v",
+					tt.hit, service.CanPlayerHit(), service.CanPlayerDoubleDown())
+			}
+			if _, err := service.ProcessPlayerAction(entities.ActionHit); (err == nil) != tt.hit {
+				t.Errorf("Expected hit allowed %v, got error %v", tt.hit, err)
+			}
+		})
+	}
+}
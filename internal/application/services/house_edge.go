@@ -0,0 +1,495 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// ruleVariation 可单独计算影响的规则项
+type ruleVariation struct {
+	name     string
+	describe func(rules entities.Rules) string
+	apply    func(rules *entities.Rules, target entities.Rules)
+}
+
+// ruleVariations 按顺序逐项从参考规则切换到目标规则
+var ruleVariations = []ruleVariation{
+	{
+		name:     "deck_count",
+		describe: func(rules entities.Rules) string { return fmt.Sprintf("%d 副牌", rules.DeckCount) },
+		apply:    func(rules *entities.Rules, target entities.Rules) { rules.DeckCount = target.DeckCount },
+	},
+	{
+		name:     "hole_card",
+		describe: func(rules entities.Rules) string { return "庄家底牌 " + rules.HoleCard.String() },
+		apply:    func(rules *entities.Rules, target entities.Rules) { rules.HoleCard = target.HoleCard },
+	},
+	{
+		name: "dealer_hits_soft_17",
+		describe: func(rules entities.Rules) string {
+			if rules.DealerHitsSoft17 {
+				return "庄家软17要牌 (H17)"
+			}
+			return "庄家软17停牌 (S17)"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.DealerHitsSoft17 = target.DealerHitsSoft17 },
+	},
+	{
+		name:     "double_restriction",
+		describe: func(rules entities.Rules) string { return "加倍点数限制 " + rules.DoubleRestriction.String() },
+		apply:    func(rules *entities.Rules, target entities.Rules) { rules.DoubleRestriction = target.DoubleRestriction },
+	},
+	{
+		name: "double_after_split",
+		describe: func(rules entities.Rules) string {
+			if rules.DoubleAfterSplit {
+				return "分牌后可加倍 (DAS)"
+			}
+			return "分牌后不可加倍"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.DoubleAfterSplit = target.DoubleAfterSplit },
+	},
+	{
+		name: "double_any_cards",
+		describe: func(rules entities.Rules) string {
+			if rules.DoubleAnyCards {
+				return "任意张数可加倍"
+			}
+			return "只有前两张牌可加倍"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.DoubleAnyCards = target.DoubleAnyCards },
+	},
+	{
+		name: "resplit_aces",
+		describe: func(rules entities.Rules) string {
+			if rules.ResplitAces {
+				return "可再分A (RSA)"
+			}
+			return "A不能再分"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.ResplitAces = target.ResplitAces },
+	},
+	{
+		name: "hit_split_aces",
+		describe: func(rules entities.Rules) string {
+			if rules.HitSplitAces {
+				return "分A后可要牌与加倍"
+			}
+			return "分A只补一张"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.HitSplitAces = target.HitSplitAces },
+	},
+	{
+		name: "charlie_cards",
+		describe: func(rules entities.Rules) string {
+			if rules.CharlieCards > 0 {
+				return fmt.Sprintf("%d 张查理自动获胜", rules.CharlieCards)
+			}
+			return "无查理规则"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.CharlieCards = target.CharlieCards },
+	},
+	{
+		name: "player_21_always_wins",
+		describe: func(rules entities.Rules) string {
+			if rules.Player21AlwaysWins {
+				return "玩家21点必胜"
+			}
+			return "玩家21点与庄家21点平局"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) {
+			rules.Player21AlwaysWins = target.Player21AlwaysWins
+		},
+	},
+	{
+		name: "spanish_deck",
+		describe: func(rules entities.Rules) string {
+			if rules.SpanishDeck {
+				return "西班牙牌 (无10点)"
+			}
+			return "标准52张牌"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.SpanishDeck = target.SpanishDeck },
+	},
+	{
+		name: "bonus_payouts",
+		describe: func(rules entities.Rules) string {
+			if rules.BonusPayouts {
+				return "西班牙21点奖励赔付"
+			}
+			return "无奖励赔付"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.BonusPayouts = target.BonusPayouts },
+	},
+	{
+		name: "late_surrender",
+		describe: func(rules entities.Rules) string {
+			if rules.LateSurrender {
+				return "可后投降"
+			}
+			return "不可投降"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.LateSurrender = target.LateSurrender },
+	},
+	{
+		name: "double_down_rescue",
+		describe: func(rules entities.Rules) string {
+			if rules.DoubleDownRescue {
+				return "加倍救援"
+			}
+			return "加倍后不可投降"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.DoubleDownRescue = target.DoubleDownRescue },
+	},
+	{
+		name: "switch_hands",
+		describe: func(rules entities.Rules) string {
+			if rules.SwitchHands {
+				return "两手牌可换牌"
+			}
+			return "单手牌不可换牌"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.SwitchHands = target.SwitchHands },
+	},
+	{
+		name: "dealer_22_push",
+		describe: func(rules entities.Rules) string {
+			if rules.Dealer22Push {
+				return "庄家22点平局"
+			}
+			return "庄家22点爆牌"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.Dealer22Push = target.Dealer22Push },
+	},
+	{
+		name: "free_doubles",
+		describe: func(rules entities.Rules) string {
+			if rules.FreeDoubles {
+				return "硬9-11点免费加倍"
+			}
+			return "无免费加倍"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.FreeDoubles = target.FreeDoubles },
+	},
+	{
+		name: "free_splits",
+		describe: func(rules entities.Rules) string {
+			if rules.FreeSplits {
+				return "非10点对子免费分牌"
+			}
+			return "无免费分牌"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.FreeSplits = target.FreeSplits },
+	},
+	{
+		name: "double_exposure",
+		describe: func(rules entities.Rules) string {
+			if rules.DoubleExposure {
+				return "庄家双明牌，平局庄家赢"
+			}
+			return "庄家一张明牌"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.DoubleExposure = target.DoubleExposure },
+	},
+	{
+		name: "pontoon",
+		describe: func(rules entities.Rules) string {
+			if rules.Pontoon {
+				return "Pontoon暗牌，平局庄家赢，15点起停牌"
+			}
+			return "非Pontoon规则"
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.Pontoon = target.Pontoon },
+	},
+	{
+		name: "blackjack_payout",
+		describe: func(rules entities.Rules) string {
+			return fmt.Sprintf("Blackjack 赔付 %.2g:1", rules.BlackjackPayout)
+		},
+		apply: func(rules *entities.Rules, target entities.Rules) { rules.BlackjackPayout = target.BlackjackPayout },
+	},
+}
+
+// HouseEdgeService 赌场优势计算服务
+type HouseEdgeService struct {
+	reference entities.Rules
+}
+
+// NewHouseEdgeService 创建赌场优势计算服务，规则影响相对于默认规则计算
+func NewHouseEdgeService() *HouseEdgeService {
+	return &HouseEdgeService{
+		reference: entities.DefaultRules(),
+	}
+}
+
+// Calculate 计算规则集下按最优基本策略的首手期望值，并逐项给出规则影响
+func (s *HouseEdgeService) Calculate(rules entities.Rules) *dtos.HouseEdgeDTO {
+	referenceEV := NewEVCalculator(s.reference).OffTheTopEV()
+	result := &dtos.HouseEdgeDTO{
+		Rules:       rules,
+		ReferenceEV: referenceEV,
+	}
+
+	// 逐项累积切换，各项影响之和恰好等于总差值
+	current := s.reference
+	currentEV := referenceEV
+	for _, variation := range ruleVariations {
+		next := current
+		variation.apply(&next, rules)
+		if next == current {
+			continue
+		}
+
+		nextEV := NewEVCalculator(next).OffTheTopEV()
+		result.Breakdown = append(result.Breakdown, &dtos.RuleContributionDTO{
+			Rule:        variation.name,
+			Description: variation.describe(next),
+			EVChange:    nextEV - currentEV,
+		})
+		current, currentEV = next, nextEV
+	}
+
+	result.PlayerEV = currentEV
+	result.HouseEdge = -currentEV
+	return result
+}
+
+// OffTheTopEV 完整牌靴首手的玩家期望值（以初始注码为单位）
+// 枚举玩家两张牌与庄家明牌的所有组合，每个局面按最优操作计值
+func (ev *EVCalculator) OffTheTopEV() float64 {
+	return ev.shoeEV(ev.fullShoe())
+}
+
+// shoeEV 从给定牌堆组成发出首手的玩家期望值
+func (ev *EVCalculator) shoeEV(shoe shoeComposition) float64 {
+	if ev.rules.SwitchHands {
+		return ev.switchOffTheTopEV(shoe)
+	}
+	if ev.rules.DoubleExposure {
+		return ev.doubleExposureOffTheTopEV(shoe)
+	}
+	if ev.rules.Pontoon {
+		return ev.pontoonOffTheTopEV(shoe)
+	}
+
+	total := 0.0
+
+	for first := aceValue; first <= tenValue; first++ {
+		for second := first; second <= tenValue; second++ {
+			for up := aceValue; up <= tenValue; up++ {
+				prob, remaining := drawProbability(shoe, first, second, up)
+				if prob == 0 {
+					continue
+				}
+				if first != second {
+					// 两张不同的牌有两种发牌顺序
+					prob *= 2
+				}
+				total += prob * ev.initialHandEV(first, second, up, remaining)
+			}
+		}
+	}
+
+	return total
+}
+
+// effectsOfRemoval 当前牌堆的首手期望值，以及从牌堆中移除一张各点数的牌后期望值的变化
+func (ev *EVCalculator) effectsOfRemoval(shoe shoeComposition) (float64, [tenValue + 1]float64) {
+	var effects [tenValue + 1]float64
+	base := ev.shoeEV(shoe)
+	for value := aceValue; value <= tenValue; value++ {
+		if shoe.counts[value] == 0 {
+			continue
+		}
+		removed := shoe
+		removed.remove(value)
+		effects[value] = ev.shoeEV(removed) - base
+	}
+	return base, effects
+}
+
+// initialHandEV 首两张牌局面的最优期望值
+func (ev *EVCalculator) initialHandEV(first, second, up int, shoe shoeComposition) float64 {
+	dealer := ev.dealerOutcomes(up, shoe)
+	value := ev.openingHandEV(first, second, true, dealer, shoe)
+	if ev.rules.HoleCard == entities.HoleCardPeek {
+		return dealer.blackjack*ev.dealerBlackjackEV(first, second, true) + (1-dealer.blackjack)*value
+	}
+	return value
+}
+
+// doubleExposureOffTheTopEV 双明牌玩法首手期望值，玩家按庄家两张牌决策
+func (ev *EVCalculator) doubleExposureOffTheTopEV(shoe shoeComposition) float64 {
+	total := 0.0
+
+	for first := aceValue; first <= tenValue; first++ {
+		for second := first; second <= tenValue; second++ {
+			for up := aceValue; up <= tenValue; up++ {
+				for hole := aceValue; hole <= tenValue; hole++ {
+					prob, remaining := drawProbability(shoe, first, second, up, hole)
+					if prob == 0 {
+						continue
+					}
+					if first != second {
+						prob *= 2
+					}
+
+					dealer := &dealerOutcome{}
+					ev.dealerDraw(handState{}.draw(up).draw(hole), remaining, 1.0, dealer)
+					if dealer.blackjack > 0 {
+						// 庄家Blackjack明牌可见，本局直接结算
+						total += prob * ev.dealerBlackjackEV(first, second, true)
+						continue
+					}
+					total += prob * ev.openingHandEV(first, second, true, dealer, remaining)
+				}
+			}
+		}
+	}
+
+	return total
+}
+
+// pontoonOffTheTopEV Pontoon首手期望值，庄家两张牌都是暗牌，玩家按庄家各种明牌的混合分布决策
+func (ev *EVCalculator) pontoonOffTheTopEV(shoe shoeComposition) float64 {
+	total := 0.0
+
+	for first := aceValue; first <= tenValue; first++ {
+		for second := first; second <= tenValue; second++ {
+			prob, remaining := drawProbability(shoe, first, second)
+			if prob == 0 {
+				continue
+			}
+			if first != second {
+				prob *= 2
+			}
+
+			dealer := ev.hiddenDealerOutcomes(remaining)
+			value := ev.openingHandEV(first, second, true, dealer, remaining)
+			// 庄家Pontoon在玩家行动前揭晓，通吃所有手牌
+			total += prob * (dealer.blackjack*ev.dealerBlackjackEV(first, second, true) + (1-dealer.blackjack)*value)
+		}
+	}
+
+	return total
+}
+
+// hiddenDealerOutcomes 庄家两张牌都未知时的最终结果分布（按明牌点数加权混合）
+func (ev *EVCalculator) hiddenDealerOutcomes(shoe shoeComposition) *dealerOutcome {
+	mixed := &dealerOutcome{}
+	for up := aceValue; up <= tenValue; up++ {
+		upProb, remaining := drawProbability(shoe, up)
+		if upProb == 0 {
+			continue
+		}
+		outcome := ev.dealerOutcomes(up, remaining)
+		mixed.bust += upProb * outcome.bust
+		mixed.push22 += upProb * outcome.push22
+		mixed.blackjack += upProb * outcome.blackjack
+		for total, p := range outcome.totals {
+			mixed.totals[total] += upProb * p
+		}
+	}
+	return mixed
+}
+
+// switchOffTheTopEV 换牌玩法首局每手牌的期望值
+// 两手共四张牌的组合过多，按无限副牌近似：玩家的牌按移除庄家明牌后的比例独立抽取
+func (ev *EVCalculator) switchOffTheTopEV(shoe shoeComposition) float64 {
+	total := 0.0
+
+	for up := aceValue; up <= tenValue; up++ {
+		upProb, remaining := drawProbability(shoe, up)
+		if upProb == 0 {
+			continue
+		}
+		dealer := ev.dealerOutcomes(up, remaining)
+		probs := remaining.probabilities()
+
+		// 每种两张牌组合保持原样与换牌后的期望值
+		var kept, switched [tenValue + 1][tenValue + 1]float64
+		for first := aceValue; first <= tenValue; first++ {
+			for second := aceValue; second <= tenValue; second++ {
+				kept[first][second] = ev.openingHandEV(first, second, true, dealer, remaining)
+				switched[first][second] = ev.openingHandEV(first, second, false, dealer, remaining)
+			}
+		}
+
+		upTotal := 0.0
+		for a1 := aceValue; a1 <= tenValue; a1++ {
+			for b1 := aceValue; b1 <= tenValue; b1++ {
+				for a2 := aceValue; a2 <= tenValue; a2++ {
+					for b2 := aceValue; b2 <= tenValue; b2++ {
+						prob := probs[a1] * probs[b1] * probs[a2] * probs[b2]
+						if prob == 0 {
+							continue
+						}
+						value := max(kept[a1][b1]+kept[a2][b2], switched[a1][b2]+switched[a2][b1])
+						if ev.rules.HoleCard == entities.HoleCardPeek {
+							// 庄家Blackjack在换牌之前揭晓，按原始手牌结算
+							clash := ev.dealerBlackjackEV(a1, b1, true) + ev.dealerBlackjackEV(a2, b2, true)
+							value = dealer.blackjack*clash + (1-dealer.blackjack)*value
+						}
+						upTotal += prob * value
+					}
+				}
+			}
+		}
+		total += upProb * upTotal
+	}
+
+	return total / 2
+}
+
+// openingHandEV 首两张牌按最优操作的期望值，美式偷看规则下为庄家没有Blackjack时的条件期望
+// natural为false时（换牌后）A+10只算21点
+func (ev *EVCalculator) openingHandEV(first, second int, natural bool, dealer *dealerOutcome, shoe shoeComposition) float64 {
+	if natural && isBlackjackPair(first, second) {
+		// 玩家Blackjack：庄家同为Blackjack时平局，21点必胜或双明牌规则下仍然获胜
+		if ev.rules.BlackjackBeatsDealerBlackjack() || ev.rules.HoleCard == entities.HoleCardPeek {
+			return ev.rules.BlackjackPayout
+		}
+		return ev.rules.BlackjackPayout * (1 - dealer.blackjack)
+	}
+
+	playerCards := []entities.Card{cardOfPoint(first), cardOfPoint(second)}
+	_, best := ev.actionEVsAgainst(playerCards, dealer, shoe, true, true, true).Best()
+	return best
+}
+
+// dealerBlackjackEV 偷看到庄家Blackjack时首两张牌的结算（只输原始注码）
+func (ev *EVCalculator) dealerBlackjackEV(first, second int, natural bool) float64 {
+	// Pontoon庄家Pontoon连玩家Pontoon也赢
+	if natural && isBlackjackPair(first, second) && !ev.rules.Pontoon {
+		if ev.rules.BlackjackBeatsDealerBlackjack() {
+			return ev.rules.BlackjackPayout
+		}
+		return 0
+	}
+	return -1
+}
+
+// isBlackjackPair 两张牌是否为A与10点牌
+func isBlackjackPair(first, second int) bool {
+	return (first == aceValue && second == tenValue) || (first == tenValue && second == aceValue)
+}
+
+// drawProbability 依次抽出指定点数的概率及剩余牌堆
+func drawProbability(shoe shoeComposition, values ...int) (float64, shoeComposition) {
+	prob := 1.0
+	for _, value := range values {
+		if shoe.counts[value] == 0 {
+			return 0, shoe
+		}
+		prob *= float64(shoe.counts[value]) / float64(shoe.total)
+		shoe.remove(value)
+	}
+	return prob, shoe
+}
+
+// cardOfPoint 由点数下标构造代表卡牌（10点用10）
+func cardOfPoint(value int) entities.Card {
+	return entities.Card{Suit: entities.Spades, Rank: entities.Rank(value)}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestOffTheTopEV 测试首手期望值随牌副数变化且在合理范围内
+func TestOffTheTopEV(t *testing.T) {
+	t.Parallel()
+
+	previous := 1.0
+	for _, decks := range []int{1, 2, 6, 8} {
+		rules := entities.DefaultRules()
+		rules.DeckCount = decks
+
+		ev := NewEVCalculator(rules).OffTheTopEV()
+		if ev < -0.01 || ev > 0.005 {
+			t.Errorf("%d decks: player EV %.4f out of expected range", decks, ev)
+		}
+		if ev >= previous {
+			t.Errorf("%d decks: expected EV to drop with more decks, got %.4f after %.4f", decks, ev, previous)
+		}
+		previous = ev
+	}
+}
+
+// TestHouseEdgeBreakdown 测试规则影响逐项累加等于总期望
+func TestHouseEdgeBreakdown(t *testing.T) {
+	t.Parallel()
+
+	service := NewHouseEdgeService()
+	if result := service.Calculate(entities.DefaultRules()); len(result.Breakdown) != 0 {
+		t.Errorf("Expected no breakdown for reference rules, got %d entries", len(result.Breakdown))
+	}
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	rules.BlackjackPayout = 1.2
+	result := service.Calculate(rules)
+
+	sum := result.ReferenceEV
+	for _, contribution := range result.Breakdown {
+		sum += contribution.EVChange
+	}
+	if math.Abs(sum-result.PlayerEV) > 1e-12 {
+		t.Errorf("Breakdown should sum to player EV: %.6f vs %.6f", sum, result.PlayerEV)
+	}
+	if result.HouseEdge != -result.PlayerEV {
+		t.Errorf("House edge should be the negated player EV")
+	}
+
+	// 6:5 赔付约损失 1.4%
+	payout := result.Breakdown[len(result.Breakdown)-1]
+	if payout.Rule != "blackjack_payout" || payout.EVChange > -0.012 || payout.EVChange < -0.016 {
+		t.Errorf("Unexpected 6:5 contribution %+v", payout)
+	}
+}
+
+// TestHoleCardRulesEV 测试底牌规则的期望差异：ENHC约-0.11%，OBO与偷看相同
+func TestHoleCardRulesEV(t *testing.T) {
+	t.Parallel()
+
+	evs := make(map[entities.HoleCardRule]float64)
+	for _, holeCard := range entities.HoleCardRules {
+		rules := entities.DefaultRules()
+		rules.DeckCount = 6
+		rules.HoleCard = holeCard
+		evs[holeCard] = NewEVCalculator(rules).OffTheTopEV()
+	}
+
+	if diff := evs[entities.HoleCardENHC] - evs[entities.HoleCardPeek]; diff > -0.0008 || diff < -0.0015 {
+		t.Errorf("Expected ENHC to cost about 0.11%%, got %.4f%%", diff*100)
+	}
+	if diff := evs[entities.HoleCardENHCOBO] - evs[entities.HoleCardPeek]; math.Abs(diff) > 1e-9 {
+		t.Errorf("Expected OBO to match peek, got %.6f", diff)
+	}
+}
+
+// TestDoubleRulesEV 测试加倍限制与DAS对期望值的影响与公开数据一致
+func TestDoubleRulesEV(t *testing.T) {
+	t.Parallel()
+
+	reference := entities.DefaultRules()
+	reference.DeckCount = 6
+	base := NewEVCalculator(reference).OffTheTopEV()
+
+	tests := []struct {
+		name     string
+		apply    func(rules *entities.Rules)
+		min, max float64 // 期望值变化范围（百分比）
+	}{
+		{"double 9-11", func(rules *entities.Rules) { rules.DoubleRestriction = entities.DoubleNineToEleven }, -0.12, -0.06},
+		{"double 10-11", func(rules *entities.Rules) { rules.DoubleRestriction = entities.DoubleTenToEleven }, -0.22, -0.14},
+		{"double after split", func(rules *entities.Rules) { rules.DoubleAfterSplit = true }, 0.09, 0.16},
+		{"double any cards", func(rules *entities.Rules) { rules.DoubleAnyCards = true }, 0.1, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := reference
+			tt.apply(&rules)
+			diff := (NewEVCalculator(rules).OffTheTopEV() - base) * 100
+			if diff < tt.min || diff > tt.max {
+				t.Errorf("Expected EV change in [%.2f%%, %.2f%%], got %.4f%%", tt.min, tt.max, diff)
+			}
+		})
+	}
+}
+
+// TestCharlieRulesEV 测试查理规则的期望值影响与公开数据一致
+func TestCharlieRulesEV(t *testing.T) {
+	t.Parallel()
+
+	reference := entities.DefaultRules()
+	reference.DeckCount = 6
+	base := NewEVCalculator(reference).OffTheTopEV()
+
+	tests := []struct {
+		cards    int
+		min, max float64 // 期望值变化范围（百分比）
+	}{
+		{5, 1.3, 1.7},
+		{6, 0.1, 0.2},
+		{7, 0, 0.03},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d_card_charlie", tt.cards), func(t *testing.T) {
+			t.Parallel()
+
+			rules := reference
+			rules.CharlieCards = tt.cards
+			diff := (NewEVCalculator(rules).OffTheTopEV() - base) * 100
+			if diff < tt.min || diff > tt.max {
+				t.Errorf("Expected EV change in [%.2f%%, %.2f%%], got %.4f%%", tt.min, tt.max, diff)
+			}
+		})
+	}
+}
+
+// TestSpanish21EV 测试西班牙21点各规则的期望值影响
+func TestSpanish21EV(t *testing.T) {
+	t.Parallel()
+
+	result := NewHouseEdgeService().Calculate(entities.Spanish21Rules())
+	changes := make(map[string]float64)
+	for _, contribution := range result.Breakdown {
+		changes[contribution.Rule] = contribution.EVChange * 100
+	}
+
+	tests := []struct {
+		rule     string
+		min, max float64 // 期望值变化范围（百分比）
+	}{
+		{"resplit_aces", 0.03, 0.12},
+		{"hit_split_aces", 0.1, 0.25},
+		{"spanish_deck", -2.2, -1.8},
+		{"bonus_payouts", 0.25, 0.45},
+		{"late_surrender", 0, 0.1},
+		{"double_down_rescue", 0, 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := changes[tt.rule]; diff < tt.min || diff > tt.max {
+				t.Errorf("Expected EV change in [%.2f%%, %.2f%%], got %.4f%%", tt.min, tt.max, diff)
+			}
+		})
+	}
+
+	// 公开的6副牌西班牙21点赌场优势：庄家软17要牌约0.76%，软17停牌约0.40%
+	references := []struct {
+		name       string
+		hitsSoft17 bool
+		edge       float64
+	}{
+		{"h17", true, 0.76},
+		{"s17", false, 0.40},
+	}
+	for _, reference := range references {
+		t.Run(reference.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules := entities.Spanish21Rules()
+			rules.DealerHitsSoft17 = reference.hitsSoft17
+			edge := NewHouseEdgeService().Calculate(rules).HouseEdge * 100
+			if math.Abs(edge-reference.edge) > 0.1 {
+				t.Errorf("Expected a house edge near %.2f%%, got %.4f%%", reference.edge, edge)
+			}
+		})
+	}
+}
+
+// TestSwitchAndFreeBetEV 测试换牌与免费下注玩法的规则影响与换牌建议，
+// 赌场优势对照Wizard of Odds按同样规则（6副牌，庄家软17要牌，可分牌后加倍，最多分到4手）给出的数据：
+// 换牌玩法约0.58%，免费下注约1.04%
+func TestSwitchAndFreeBetEV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rules entities.Rules
+		rule  string  // 应为正收益的规则项
+		edge  float64 // 公开的赌场优势
+	}{
+		{"switch", entities.BlackjackSwitchRules(), "switch_hands", 0.0058},
+		{"free bet", entities.FreeBetRules(), "free_splits", 0.0104},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := NewHouseEdgeService().Calculate(tt.rules)
+			changes := make(map[string]float64)
+			for _, contribution := range result.Breakdown {
+				changes[contribution.Rule] = contribution.EVChange
+			}
+			if changes["dealer_22_push"] >= 0 {
+				t.Errorf("Expected dealer 22 push to cost the player, got %.4f", changes["dealer_22_push"])
+			}
+			if changes[tt.rule] <= 0 {
+				t.Errorf("Expected %s to help the player, got %.4f", tt.rule, changes[tt.rule])
+			}
+			if math.Abs(result.HouseEdge-tt.edge) > 0.001 {
+				t.Errorf("Expected house edge %.2f%% ± 0.10%%, got %.4f%%", tt.edge*100, result.HouseEdge*100)
+			}
+		})
+	}
+
+	// 10+5与6+10对庄家6，换成20与11明显更好
+	ev := NewEVCalculator(entities.BlackjackSwitchRules())
+	keep, switched := ev.CalculateSwitchEVs(
+		cardsOf(entities.Ten, entities.Five),
+		cardsOf(entities.Six, entities.Ten),
+		entities.Card{Suit: entities.Hearts, Rank: entities.Six},
+	)
+	if switched <= keep {
+		t.Errorf("Expected switching to 20 and 11 to beat keeping, got keep %.4f switch %.4f", keep, switched)
+	}
+}
+
+// TestDoubleExposureEV 测试双明牌的信息优势与平局损失相抵，总优势与公开数据一致。
+// 参考Wizard of Odds双明牌规则：8副牌，庄家软17要牌，Blackjack 1:1且平局玩家赢，
+// 只能在9-11点加倍，可分牌后加倍，最多分到4手，赌场优势约0.69%
+func TestDoubleExposureEV(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DoubleExposureRules()
+	rules.DeckCount = 8
+	rules.DealerHitsSoft17 = true
+
+	result := NewHouseEdgeService().Calculate(rules)
+	for _, contribution := range result.Breakdown {
+		if contribution.Rule == "double_exposure" && contribution.EVChange <= 0 {
+			t.Errorf("Expected seeing both dealer cards to outweigh losing ties, got %.4f", contribution.EVChange)
+		}
+	}
+
+	const published = 0.0069
+	if math.Abs(result.HouseEdge-published) > 0.001 {
+		t.Errorf("Expected house edge %.2f%% ± 0.10%%, got %.4f%%", published*100, result.HouseEdge*100)
+	}
+}
+
+// TestPontoonEV 测试Pontoon赌场优势：精确计算与连续洗牌机下按同一策略模拟的结果一致
+func TestPontoonEV(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.PontoonRules()
+	result := NewHouseEdgeService().Calculate(rules)
+	for _, contribution := range result.Breakdown {
+		if contribution.Rule == "pontoon" && contribution.EVChange >= 0 {
+			t.Errorf("Expected hidden dealer cards and losing ties to cost the player, got %.4f", contribution.EVChange)
+		}
+	}
+
+	// 连续洗牌机每局都从接近完整的牌靴发牌，可与首手期望值比较；100万局的标准误约0.12%
+	rules.ContinuousShuffle = true
+	params := DefaultCountingSimulationParams()
+	params.Rounds = 1000000
+	params.Spread = 1
+	simulation, err := NewCountingSimulator(rules).Simulate(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := result.HouseEdge + simulation.FlatEV; math.Abs(diff) > 0.005 {
+		t.Errorf("Expected house edge %.4f%% to match the simulated %.4f%%", result.HouseEdge*100, -simulation.FlatEV*100)
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// pontoonTwistLimits Pontoon硬牌按手牌张数的要牌上限：点数不超过上限时要牌(twist)，否则停牌(stick)
+// 庄家两张牌都是暗牌，策略只看玩家手牌；平局庄家赢使15-17点停牌的价值相差无几，
+// 四张牌时离五张牌型(赔2:1)只差一张，值得冒更大的爆牌风险
+var pontoonTwistLimits = map[int]int{
+	2: entities.PontoonMinStick - 1,
+	3: entities.PontoonMinStick,
+	4: 17,
+}
+
+// pontoonSoftTwistLimit Pontoon软牌的要牌上限
+const pontoonSoftTwistLimit = 18
+
+// pontoonStrategyAction Pontoon基本策略：分A与8，两张硬9-11买牌，其余按张数查要牌上限
+func (pc *ProbabilityCalculator) pontoonStrategyAction(hand *entities.Hand, canBuy, canSplit bool) string {
+	value := hand.Value()
+	cards := len(hand.Cards)
+
+	switch {
+	case value >= 21 || pc.rules.IsAutoWin(hand):
+		return "stand"
+	case canSplit && (hand.Cards[0].Rank == entities.Ace || hand.Cards[0].Rank == entities.Eight):
+		return "split"
+	case canBuy && cards == 2 && !hand.IsSoft() && value >= 9 && value <= 11:
+		return "double"
+	case !pc.rules.CanStick(hand):
+		return "hit"
+	case hand.IsSoft():
+		// 四张软牌再要一张不会爆牌，必然凑成五张牌型
+		if value <= pontoonSoftTwistLimit || cards == pc.rules.CharlieCards-1 {
+			return "hit"
+		}
+		return "stand"
+	}
+
+	if limit, ok := pontoonTwistLimits[cards]; ok && value <= limit {
+		return "hit"
+	}
+	return "stand"
+}
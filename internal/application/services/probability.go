@@ -3,7 +3,6 @@ package services
 import (
 	"math/rand/v2"
 	"slices"
-	"time"
 
 	"github.com/luffy050596/go-blackjack/internal/domain/entities"
 )
@@ -11,17 +10,37 @@ import (
 // ProbabilityCalculator 概率计算器
 type ProbabilityCalculator struct {
 	deck   *entities.Deck
+	rules  entities.Rules
 	trials int // 蒙特卡洛模拟次数
 	rng    *rand.Rand
 }
 
+// ProbabilityOption is a function type for configuring the probability calculator
+type ProbabilityOption func(pc *ProbabilityCalculator)
+
+// WithProbabilityRules configures the table rules used by the simulation
+func WithProbabilityRules(rules entities.Rules) ProbabilityOption {
+	return func(pc *ProbabilityCalculator) {
+		pc.rules = rules
+	}
+}
+
 // NewProbabilityCalculator 创建概率计算器
-func NewProbabilityCalculator(deck *entities.Deck) *ProbabilityCalculator {
-	return &ProbabilityCalculator{
+func NewProbabilityCalculator(deck *entities.Deck, options ...ProbabilityOption) *ProbabilityCalculator {
+	pc := &ProbabilityCalculator{
 		deck:   deck,
+		rules:  entities.DefaultRules(),
 		trials: 10000,
-		rng:    rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()<<32))),
 	}
+
+	for _, option := range options {
+		option(pc)
+	}
+
+	// 概率模拟只决定建议、不决定发牌，始终用PCG；规则给定种子时结果可重现
+	pc.rng = entities.RandomPCG.NewRand(pc.rules.ShuffleSeed)
+
+	return pc
 }
 
 // ProbabilityResult 概率计算结果
@@ -255,23 +274,17 @@ func (pc *ProbabilityCalculator) calculateProbabilitiesFor21Player(
 
 	for i := 0; i < pc.trials; i++ {
 		// 创建庄家模拟手牌
-		simDealerHand := entities.NewHand()
-		if len(dealerHand.Cards) > 0 {
-			simDealerHand.AddCard(dealerHand.Cards[0])
-		}
+		simDealerHand := pc.visibleDealerHand(dealerHand)
 
 		// 创建剩余牌的副本并洗牌
 		simDeck := pc.createShuffledDeckWithHiddenCard(remainingCards, dealerHand)
 		deckIndex := 0
 
-		// 如果庄家有隐藏牌，先为庄家发隐藏牌
-		if len(dealerHand.Cards) > 1 && deckIndex < len(simDeck) {
-			simDealerHand.AddCard(simDeck[deckIndex])
-			deckIndex++
-		}
+		// 先为庄家发底牌
+		deckIndex = pc.dealHoleCard(simDealerHand, simDeck, deckIndex)
 
 		// 庄家按规则要牌
-		for simDealerHand.Value() < 17 && deckIndex < len(simDeck) {
+		for pc.rules.DealerShouldHit(simDealerHand) && deckIndex < len(simDeck) {
 			simDealerHand.AddCard(simDeck[deckIndex])
 			deckIndex++
 		}
@@ -293,11 +306,19 @@ func (pc *ProbabilityCalculator) calculateProbabilitiesFor21Player(
 
 		// 判断胜负（玩家固定21点）
 		switch {
+		case pc.rules.IsDealer22Push(simDealerHand) && !pc.rules.Player21AlwaysWins && playerBlackjackProb < 1.0:
+			// 庄家22点平局
+			pushes++
 		case dealerBust:
 			// 玩家胜利（已计入playerWins）
 		case dealerBlackjack && playerBlackjackProb < 1.0:
 			// 庄家Blackjack而玩家不是Blackjack
 			dealerWins++
+		case dealerValue == 21 && pc.rules.Player21AlwaysWins:
+			// 21点必胜规则下玩家获胜（已计入playerWins）
+		case dealerValue == 21 && pc.rules.DealerWinsTies() && playerBlackjackProb < 1.0:
+			// 双明牌与Pontoon规则下平局庄家赢
+			dealerWins++
 		case dealerValue == 21:
 			// 平局
 			pushes++
@@ -358,24 +379,17 @@ func (pc *ProbabilityCalculator) simulateGame(
 	simPlayerHand := pc.copyHand(playerHand)
 
 	// 创建庄家模拟手牌 - 只包含明牌
-	simDealerHand := entities.NewHand()
-	if len(dealerHand.Cards) > 0 {
-		// 只添加第一张牌（明牌）
-		simDealerHand.AddCard(dealerHand.Cards[0])
-	}
+	simDealerHand := pc.visibleDealerHand(dealerHand)
 
 	// 创建剩余牌的副本并洗牌，包含庄家的隐藏牌
 	simDeck := pc.createShuffledDeckWithHiddenCard(remainingCards, dealerHand)
 	deckIndex := 0
 
-	// 先为庄家发隐藏牌
-	if len(dealerHand.Cards) > 1 && deckIndex < len(simDeck) {
-		simDealerHand.AddCard(simDeck[deckIndex])
-		deckIndex++
-	}
+	// 先为庄家发底牌
+	deckIndex = pc.dealHoleCard(simDealerHand, simDeck, deckIndex)
 
 	// 玩家决策（使用基本策略）
-	for !simPlayerHand.IsBust() && simPlayerHand.Value() < 21 {
+	for !simPlayerHand.IsBust() && simPlayerHand.Value() < 21 && !pc.rules.IsAutoWin(simPlayerHand) {
 		action := pc.getBasicStrategyAction(simPlayerHand, simDealerHand)
 		if action == "stand" {
 			break
@@ -388,8 +402,8 @@ func (pc *ProbabilityCalculator) simulateGame(
 		}
 	}
 
-	// 庄家按规则要牌
-	for simDealerHand.Value() < 17 && deckIndex < len(simDeck) {
+	// 庄家按规则要牌（玩家自动获胜时无需要牌）
+	for !pc.rules.IsAutoWin(simPlayerHand) && pc.rules.DealerShouldHit(simDealerHand) && deckIndex < len(simDeck) {
 		simDealerHand.AddCard(simDeck[deckIndex])
 		deckIndex++
 	}
@@ -402,6 +416,11 @@ func (pc *ProbabilityCalculator) simulateGame(
 func (pc *ProbabilityCalculator) getBasicStrategyAction(playerHand *entities.Hand, dealerHand *entities.Hand) string {
 	playerValue := playerHand.Value()
 
+	// Pontoon庄家没有明牌，按玩家手牌查Pontoon策略表
+	if pc.rules.Pontoon {
+		return pc.pontoonStrategyAction(playerHand, false, false)
+	}
+
 	// 如果庄家手牌为空，无法获取明牌
 	if len(dealerHand.Cards) < 1 {
 		// 默认策略：小于17点继续要牌
@@ -414,6 +433,28 @@ func (pc *ProbabilityCalculator) getBasicStrategyAction(playerHand *entities.Han
 	// 只使用庄家的第一张牌（明牌）进行决策
 	dealerUpCard := dealerHand.Cards[0].Value()
 
+	// 查理规则下差一张牌时，软牌或11点以下再要一张不会爆牌，必然凑成查理
+	if pc.rules.CharlieCards > 0 && len(playerHand.Cards) == pc.rules.CharlieCards-1 &&
+		(playerValue <= 11 || playerHand.IsSoft()) {
+		return "hit"
+	}
+
+	// 双明牌规则下庄家两张牌已知：庄家已停牌时必须超过其点数（平局也输），硬12-16时不冒爆牌风险
+	if pc.rules.DoubleExposure && len(dealerHand.Cards) >= 2 {
+		dealerValue := dealerHand.Value()
+		switch {
+		case !pc.rules.DealerShouldHit(dealerHand):
+			if playerValue <= dealerValue {
+				return "hit"
+			}
+			return "stand"
+		case dealerValue >= 12 && !dealerHand.IsSoft():
+			if playerValue >= 12 && !playerHand.IsSoft() {
+				return "stand"
+			}
+		}
+	}
+
 	// 简化的基本策略
 	if playerHand.IsSoft() {
 		// 软牌策略
@@ -442,6 +483,33 @@ func (pc *ProbabilityCalculator) getBasicStrategyAction(playerHand *entities.Han
 	return "hit"
 }
 
+// dealHoleCard 为只有明牌的模拟庄家补发底牌，返回新的发牌位置
+// 美式偷看规则下玩家行动时已知庄家没有Blackjack，底牌不会与明牌组成Blackjack；
+// Pontoon庄家两张牌都未知，先补发第一张牌
+func (pc *ProbabilityCalculator) dealHoleCard(simDealerHand *entities.Hand, simDeck []entities.Card, deckIndex int) int {
+	if len(simDealerHand.Cards) == 0 && pc.rules.Pontoon && deckIndex < len(simDeck) {
+		simDealerHand.AddCard(simDeck[deckIndex])
+		deckIndex++
+	}
+
+	if len(simDealerHand.Cards) != 1 || deckIndex >= len(simDeck) {
+		return deckIndex
+	}
+
+	if pc.rules.HoleCard == entities.HoleCardPeek {
+		upCard := simDealerHand.Cards[0]
+		for i := deckIndex; i < len(simDeck); i++ {
+			if upCard.Value()+simDeck[i].Value() != 21 {
+				simDeck[deckIndex], simDeck[i] = simDeck[i], simDeck[deckIndex]
+				break
+			}
+		}
+	}
+
+	simDealerHand.AddCard(simDeck[deckIndex])
+	return deckIndex + 1
+}
+
 // copyHand 复制手牌
 func (pc *ProbabilityCalculator) copyHand(hand *entities.Hand) *entities.Hand {
 	newHand := entities.NewHand()
@@ -449,14 +517,30 @@ func (pc *ProbabilityCalculator) copyHand(hand *entities.Hand) *entities.Hand {
 	return newHand
 }
 
+// visibleDealerHand 玩家可见的庄家手牌：通常只有明牌，双明牌规则下两张牌都可见，Pontoon两张都不可见
+func (pc *ProbabilityCalculator) visibleDealerHand(dealerHand *entities.Hand) *entities.Hand {
+	if pc.rules.DoubleExposure {
+		return pc.copyHand(dealerHand)
+	}
+
+	simDealerHand := entities.NewHand()
+	if len(dealerHand.Cards) > 0 && !pc.rules.Pontoon {
+		simDealerHand.AddCard(dealerHand.Cards[0])
+	}
+	return simDealerHand
+}
+
 // createShuffledDeckWithHiddenCard 创建包含庄家隐藏牌的洗牌牌组
 func (pc *ProbabilityCalculator) createShuffledDeckWithHiddenCard(remainingCards []entities.Card, dealerHand *entities.Hand) []entities.Card {
 	// 复制剩余卡牌
-	deck := make([]entities.Card, 0, len(remainingCards)+1)
+	deck := make([]entities.Card, 0, len(remainingCards)+len(dealerHand.Cards))
 	deck = append(deck, remainingCards...)
 
-	// 如果庄家有隐藏牌（第二张牌），将其添加到牌堆中
-	if len(dealerHand.Cards) > 1 {
+	// 如果庄家有隐藏牌（第二张牌），将其添加到牌堆中；双明牌规则下第二张牌已知，Pontoon两张都是暗牌
+	switch {
+	case pc.rules.Pontoon:
+		deck = append(deck, dealerHand.Cards...)
+	case len(dealerHand.Cards) > 1 && !pc.rules.DoubleExposure:
 		deck = append(deck, dealerHand.Cards[1])
 	}
 
@@ -484,17 +568,24 @@ func (pc *ProbabilityCalculator) evaluateResult(playerHand *entities.Hand, deale
 	switch {
 	case result.PlayerBust:
 		result.Winner = "dealer"
+	case pc.rules.IsDealer22Push(dealerHand) && !result.PlayerBlackjack && !pc.rules.IsAutoWin(playerHand):
+		result.Winner = "push"
 	case result.DealerBust:
 		result.Winner = "player"
-	case result.PlayerBlackjack && result.DealerBlackjack:
+	case result.DealerBlackjack && pc.rules.Pontoon:
+		// 庄家Pontoon通吃，包括玩家Pontoon
+		result.Winner = "dealer"
+	case result.PlayerBlackjack && result.DealerBlackjack && !pc.rules.BlackjackBeatsDealerBlackjack():
 		result.Winner = "push"
 	case result.PlayerBlackjack:
 		result.Winner = "player"
 	case result.DealerBlackjack:
 		result.Winner = "dealer"
+	case pc.rules.IsAutoWin(playerHand):
+		result.Winner = "player"
 	case result.PlayerFinalValue > result.DealerFinalValue:
 		result.Winner = "player"
-	case result.PlayerFinalValue < result.DealerFinalValue:
+	case result.PlayerFinalValue < result.DealerFinalValue || pc.rules.DealerWinsTies():
 		result.Winner = "dealer"
 	default:
 		result.Winner = "push"
@@ -510,10 +601,9 @@ func (pc *ProbabilityCalculator) calculateActionAnalysis(playerHand *entities.Ha
 
 	// 检查操作可用性
 	canHit := currentValue < 21 && !playerHand.IsBust()
-	canStand := true
-	canDouble := isFirstTurn && canHit
-	canSplit := isFirstTurn && len(playerHand.Cards) == 2 &&
-		playerHand.Cards[0].Rank == playerHand.Cards[1].Rank
+	canStand := pc.rules.CanStick(playerHand)
+	canDouble := canHit && pc.rules.CanDouble(playerHand)
+	canSplit := isFirstTurn && pc.rules.CanSplit(playerHand)
 
 	actionAnalysis := &ActionAnalysis{
 		CanHit:    canHit,
@@ -544,7 +634,11 @@ func (pc *ProbabilityCalculator) calculateActionAnalysis(playerHand *entities.Ha
 	// 计算加倍胜率
 	doubleWinRate := 0.0
 	if canDouble {
-		doubleWinRate = pc.calculateDoubleWinRate(playerHand, dealerHand, remainingCards)
+		// Pontoon买牌后仍可继续要牌，胜率按要牌估计
+		doubleWinRate = hitWinRate
+		if !pc.rules.Pontoon {
+			doubleWinRate = pc.calculateDoubleWinRate(playerHand, dealerHand, remainingCards)
+		}
 		actionAnalysis.DoubleWinRate = doubleWinRate
 	}
 
@@ -555,31 +649,56 @@ func (pc *ProbabilityCalculator) calculateActionAnalysis(playerHand *entities.Ha
 		actionAnalysis.SplitWinRate = splitWinRate
 	}
 
-	// 确定推荐操作
+	// 确定推荐操作：免费加倍或免费分牌时追加的注码输了不赔，
+	// 按胜1.5倍计值比较（赢时多赢一份，输时不多输）
 	bestAction := "stand"
 	bestValue := standWinRate
+	bestScore := standWinRate
 
-	if canHit && hitWinRate > bestValue {
+	if canHit && hitWinRate > bestScore {
 		bestAction = "hit"
-		bestValue = hitWinRate
+		bestValue, bestScore = hitWinRate, hitWinRate
 	}
 
-	if canDouble && doubleWinRate > bestValue {
+	if score := pc.freeScore(doubleWinRate, pc.rules.IsFreeDouble(playerHand)); canDouble && score > bestScore {
 		bestAction = "double"
-		bestValue = doubleWinRate
+		bestValue, bestScore = doubleWinRate, score
 	}
 
-	if canSplit && splitWinRate > bestValue {
+	if score := pc.freeScore(splitWinRate, pc.rules.IsFreeSplit(playerHand)); canSplit && score > bestScore {
 		bestAction = "split"
 		bestValue = splitWinRate
 	}
 
+	// Pontoon看不到庄家的牌，推荐行动按Pontoon策略表
+	if pc.rules.Pontoon {
+		bestAction = pc.pontoonStrategyAction(playerHand, canDouble, canSplit)
+		switch bestAction {
+		case "hit":
+			bestValue = hitWinRate
+		case "double":
+			bestValue = doubleWinRate
+		case "split":
+			bestValue = splitWinRate
+		default:
+			bestValue = standWinRate
+		}
+	}
+
 	actionAnalysis.RecommendedAction = bestAction
 	actionAnalysis.ExpectedValue = bestValue
 
 	return actionAnalysis
 }
 
+// freeScore 免费加倍或分牌的比较分值：追加注码只在赢时多赢一份
+func (pc *ProbabilityCalculator) freeScore(winRate float64, free bool) float64 {
+	if free {
+		return winRate * 1.5
+	}
+	return winRate
+}
+
 // calculateStandWinRate 计算停牌胜率
 func (pc *ProbabilityCalculator) calculateStandWinRate(playerHand *entities.Hand, dealerHand *entities.Hand, remainingCards []entities.Card) float64 {
 	playerWins := 0
@@ -650,7 +769,7 @@ func (pc *ProbabilityCalculator) calculateStandWinRateSimple(playerHand *entitie
 
 // calculateDoubleWinRate 计算加倍胜率（只要一张牌然后停牌）
 func (pc *ProbabilityCalculator) calculateDoubleWinRate(playerHand *entities.Hand, dealerHand *entities.Hand, remainingCards []entities.Card) float64 {
-	if len(remainingCards) == 0 {
+	if len(remainingCards) == 0 || !pc.rules.CanDouble(playerHand) {
 		return 0.0
 	}
 
@@ -681,67 +800,131 @@ func (pc *ProbabilityCalculator) calculateDoubleWinRate(playerHand *entities.Han
 	return totalWinRate / float64(totalCards)
 }
 
-// calculateSplitWinRate 计算分牌胜率
+// calculateSplitWinRate 计算分牌胜率：每次模拟中所有分出的手牌与庄家从同一副洗好的牌中依次补牌，
+// 补到同点数的牌时再分牌（最多MaxSplitHands手，A不能再分），分A只补一张，DAS规则下两张牌时可加倍；
+// 胜率为各手牌按注码加权的获胜比例
 func (pc *ProbabilityCalculator) calculateSplitWinRate(playerHand *entities.Hand, dealerHand *entities.Hand, remainingCards []entities.Card) float64 {
-	if len(playerHand.Cards) != 2 || playerHand.Cards[0].Rank != playerHand.Cards[1].Rank {
+	if !pc.rules.CanSplit(playerHand) || len(remainingCards) == 0 {
 		return 0.0
 	}
 
-	// 简化处理：计算单张牌开始的期望胜率的平均值
-	// 实际实现可能需要更复杂的逻辑来处理分牌后的游戏
-	card := playerHand.Cards[0]
+	pair := playerHand.Cards[0]
+	wins, stakes := 0.0, 0.0
+
+	for range pc.trials {
+		simDealerHand := pc.visibleDealerHand(dealerHand)
+		simDeck := pc.createShuffledDeckWithHiddenCard(remainingCards, dealerHand)
+		deckIndex := pc.dealHoleCard(simDealerHand, simDeck, 0)
 
-	// 创建两个新手牌，每个都从一张相同的牌开始
-	hand1 := entities.NewHand()
-	hand1.AddCard(card)
+		hands := []*entities.Hand{pc.splitHand(pair), pc.splitHand(pair)}
+		handStakes := []float64{1, 1}
+		for i := 0; i < len(hands) && deckIndex < len(simDeck); i++ {
+			hand := hands[i]
+			card := simDeck[deckIndex]
+			deckIndex++
 
-	hand2 := entities.NewHand()
-	hand2.AddCard(card)
+			if card.Rank == pair.Rank && (!pair.IsAce() || pc.rules.ResplitAces) && len(hands) < entities.MaxSplitHands {
+				// 再分牌：当前手牌保持一张牌重新补牌
+				hands = append(hands, pc.splitHand(pair))
+				handStakes = append(handStakes, 1)
+				i--
+				continue
+			}
+			hand.AddCard(card)
+			if pair.IsAce() && !pc.rules.HitSplitAces {
+				continue
+			}
 
-	// 计算两手牌的最优胜率平均值
-	winRate1 := pc.calculateOptimalWinRate(hand1, dealerHand, remainingCards)
-	winRate2 := pc.calculateOptimalWinRate(hand2, dealerHand, remainingCards)
+			if pc.rules.DoubleAfterSplit && !pc.rules.Pontoon && deckIndex < len(simDeck) &&
+				pc.rules.CanDouble(hand) && pc.shouldDoubleAfterSplit(hand, simDealerHand) {
+				hand.AddCard(simDeck[deckIndex])
+				deckIndex++
+				handStakes[i] = 2
+				continue
+			}
+			for !hand.IsBust() && hand.Value() < 21 && !pc.rules.IsAutoWin(hand) && deckIndex < len(simDeck) {
+				if pc.getBasicStrategyAction(hand, simDealerHand) != "hit" {
+					break
+				}
+				hand.AddCard(simDeck[deckIndex])
+				deckIndex++
+			}
+		}
 
-	return (winRate1 + winRate2) / 2.0
+		// 所有手牌都已爆牌或自动获胜时庄家无需要牌
+		dealerPlays := slices.ContainsFunc(hands, func(hand *entities.Hand) bool {
+			return !hand.IsBust() && !pc.rules.IsAutoWin(hand)
+		})
+		for dealerPlays && pc.rules.DealerShouldHit(simDealerHand) && deckIndex < len(simDeck) {
+			simDealerHand.AddCard(simDeck[deckIndex])
+			deckIndex++
+		}
+
+		for i, hand := range hands {
+			stakes += handStakes[i]
+			if pc.splitHandWins(hand, simDealerHand) {
+				wins += handStakes[i]
+			}
+		}
+	}
+
+	return wins / stakes
 }
 
-// calculateOptimalWinRate 计算当前手牌的最优策略胜率
-func (pc *ProbabilityCalculator) calculateOptimalWinRate(playerHand *entities.Hand, dealerHand *entities.Hand, remainingCards []entities.Card) float64 {
-	// 计算停牌和要牌的胜率，选择更高的
-	standRate := pc.calculateStandWinRate(playerHand, dealerHand, remainingCards)
+// splitHand 分牌得到的只有一张对子牌的手牌
+func (pc *ProbabilityCalculator) splitHand(card entities.Card) *entities.Hand {
+	hand := entities.NewHand()
+	hand.AddCard(card)
+	return hand
+}
 
-	if playerHand.Value() >= 21 {
-		return standRate
+// shouldDoubleAfterSplit 简化的分牌后加倍策略：硬9对3-6、硬10对2-9、硬11对A以外的明牌加倍
+func (pc *ProbabilityCalculator) shouldDoubleAfterSplit(hand *entities.Hand, dealerHand *entities.Hand) bool {
+	if hand.IsSoft() || len(dealerHand.Cards) == 0 || dealerHand.Cards[0].IsAce() {
+		return false
 	}
 
-	hitRate := pc.calculateHitWinRate(playerHand, dealerHand, remainingCards)
+	upCard := dealerHand.Cards[0].Value()
+	switch hand.Value() {
+	case 9:
+		return upCard >= 3 && upCard <= 6
+	case 10:
+		return upCard <= 9
+	case 11:
+		return true
+	default:
+		return false
+	}
+}
 
-	if hitRate > standRate {
-		return hitRate
+// splitHandWins 判断分牌得到的手牌是否获胜：两张牌的21点不算Blackjack
+func (pc *ProbabilityCalculator) splitHandWins(hand *entities.Hand, dealerHand *entities.Hand) bool {
+	if !hand.IsBlackjack() {
+		return pc.playerWins(hand, dealerHand)
+	}
+	if pc.rules.Player21AlwaysWins {
+		return true
 	}
-	return standRate
+	if dealerHand.IsBust() {
+		return !pc.rules.IsDealer22Push(dealerHand)
+	}
+	return !dealerHand.IsBlackjack() && dealerHand.Value() < 21
 }
 
 // simulateDealerPlay 模拟庄家完成手牌
 func (pc *ProbabilityCalculator) simulateDealerPlay(dealerHand *entities.Hand, remainingCards []entities.Card) *entities.Hand {
 	// 创建庄家模拟手牌
-	simDealerHand := entities.NewHand()
-	if len(dealerHand.Cards) > 0 {
-		simDealerHand.AddCard(dealerHand.Cards[0])
-	}
+	simDealerHand := pc.visibleDealerHand(dealerHand)
 
 	// 创建剩余牌的副本并洗牌
 	simDeck := pc.createShuffledDeckWithHiddenCard(remainingCards, dealerHand)
 	deckIndex := 0
 
-	// 如果庄家有隐藏牌，先为庄家发隐藏牌
-	if len(dealerHand.Cards) > 1 && deckIndex < len(simDeck) {
-		simDealerHand.AddCard(simDeck[deckIndex])
-		deckIndex++
-	}
+	// 先为庄家发底牌
+	deckIndex = pc.dealHoleCard(simDealerHand, simDeck, deckIndex)
 
 	// 庄家按规则要牌
-	for simDealerHand.Value() < 17 && deckIndex < len(simDeck) {
+	for pc.rules.DealerShouldHit(simDealerHand) && deckIndex < len(simDeck) {
 		simDealerHand.AddCard(simDeck[deckIndex])
 		deckIndex++
 	}
@@ -755,7 +938,8 @@ func (pc *ProbabilityCalculator) playerWins(playerHand *entities.Hand, dealerHan
 		return false
 	}
 	if dealerHand.IsBust() {
-		return true
+		// 庄家22点平局规则下只有Blackjack与自动获胜的手牌赢
+		return !pc.rules.IsDealer22Push(dealerHand) || playerHand.IsBlackjack() || pc.rules.IsAutoWin(playerHand)
 	}
 
 	playerValue := playerHand.Value()
@@ -765,7 +949,7 @@ func (pc *ProbabilityCalculator) playerWins(playerHand *entities.Hand, dealerHan
 	playerBlackjack := playerHand.IsBlackjack()
 	dealerBlackjack := dealerHand.IsBlackjack()
 
-	if playerBlackjack && !dealerBlackjack {
+	if playerBlackjack && (!dealerBlackjack || pc.rules.BlackjackBeatsDealerBlackjack()) {
 		return true
 	}
 	if !playerBlackjack && dealerBlackjack {
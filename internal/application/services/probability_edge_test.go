@@ -463,3 +463,66 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+// TestDoubleExposureProbabilities 测试双明牌规则下概率计算使用已知的庄家第二张牌
+func TestDoubleExposureProbabilities(t *testing.T) {
+	t.Parallel()
+
+	pc := NewProbabilityCalculator(entities.NewDeck(), WithProbabilityRules(entities.DoubleExposureRules()))
+	playerCards := []entities.Card{
+		{Suit: entities.Hearts, Rank: entities.Ten},
+		{Suit: entities.Spades, Rank: entities.Eight},
+	}
+	dealerCards := []entities.Card{
+		{Suit: entities.Diamonds, Rank: entities.Ten},
+		{Suit: entities.Clubs, Rank: entities.Eight},
+	}
+
+	result := runProbabilityTest(t, pc, playerCards, dealerCards, 1000)
+	// 庄家18点停牌，玩家18点停牌必输
+	if result.ActionAnalysis.StandWinRate != 0 {
+		t.Errorf("Expected standing on a tie to never win, got %.4f", result.ActionAnalysis.StandWinRate)
+	}
+	if result.ActionAnalysis.RecommendedAction != "hit" {
+		t.Errorf("Expected hit against a known 18, got %s", result.ActionAnalysis.RecommendedAction)
+	}
+	if result.PushProbability != 0 {
+		t.Errorf("Expected no pushes when ties lose, got %.4f", result.PushProbability)
+	}
+}
+
+func TestPontoonProbabilities(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		playerRanks []entities.Rank
+		recommended string
+		canStand    bool
+	}{
+		{name: "must twist below 15", playerRanks: []entities.Rank{entities.Ten, entities.Four}, recommended: "hit", canStand: false},
+		{name: "buy hard 10", playerRanks: []entities.Rank{entities.Six, entities.Four}, recommended: "double", canStand: false},
+		{name: "stick on 17", playerRanks: []entities.Rank{entities.Ten, entities.Seven}, recommended: "stand", canStand: true},
+		{name: "twist four card soft hand", playerRanks: []entities.Rank{entities.Ace, entities.Two, entities.Three, entities.Four}, recommended: "hit", canStand: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// 庄家两张暗牌是什么都不影响推荐
+			pc := NewProbabilityCalculator(entities.NewDeck(), WithProbabilityRules(entities.PontoonRules()))
+			dealerCards := []entities.Card{
+				{Suit: entities.Diamonds, Rank: entities.Ten},
+				{Suit: entities.Clubs, Rank: entities.Six},
+			}
+			result := runProbabilityTest(t, pc, cardsOf(tt.playerRanks...), dealerCards, 1000)
+			if result.ActionAnalysis.RecommendedAction != tt.recommended {
+				t.Errorf("Expected %s, got %s", tt.recommended, result.ActionAnalysis.RecommendedAction)
+			}
+			if result.ActionAnalysis.CanStand != tt.canStand {
+				t.Errorf("Expected CanStand %v, got %v", tt.canStand, result.ActionAnalysis.CanStand)
+			}
+		})
+	}
+}
@@ -871,3 +871,85 @@ func createRemainingCards(playerCards, dealerCards []entities.Card) []entities.C
 func cardKey(card entities.Card) string {
 	return string(rune(card.Suit)) + string(rune(card.Rank))
 }
+
+// TestSimulationHoleCardRules 测试模拟按底牌规则处理庄家Blackjack
+func TestSimulationHoleCardRules(t *testing.T) {
+	t.Parallel()
+
+	playerHand := entities.NewHand()
+	playerHand.AddCard(entities.Card{Suit: entities.Hearts, Rank: entities.Ten})
+	playerHand.AddCard(entities.Card{Suit: entities.Spades, Rank: entities.Six})
+
+	dealerHand := entities.NewHand()
+	dealerHand.AddCard(entities.Card{Suit: entities.Clubs, Rank: entities.Ace})
+
+	for _, holeCard := range entities.HoleCardRules {
+		rules := entities.DefaultRules()
+		rules.HoleCard = holeCard
+		deck := entities.NewShoe(2)
+		pc := NewProbabilityCalculator(deck, WithProbabilityRules(rules))
+
+		result := pc.CalculateWinProbabilities(playerHand, dealerHand, deck.Cards, 1000)
+		peeked := holeCard == entities.HoleCardPeek
+		if peeked != (result.DealerBlackjackProb == 0) {
+			t.Errorf("%v: unexpected dealer blackjack probability %.4f", holeCard, result.DealerBlackjackProb)
+		}
+	}
+}
+
+// TestSimulateDealerPlaySoft17 测试模拟庄家在H17规则下不停在软17
+func TestSimulateDealerPlaySoft17(t *testing.T) {
+	t.Parallel()
+
+	dealerHand := entities.NewHand()
+	dealerHand.AddCard(entities.Card{Suit: entities.Clubs, Rank: entities.Ace})
+
+	for _, hitsSoft17 := range []bool{false, true} {
+		rules := entities.DefaultRules()
+		rules.DealerHitsSoft17 = hitsSoft17
+		deck := entities.NewShoe(2)
+		pc := NewProbabilityCalculator(deck, WithProbabilityRules(rules))
+
+		soft17 := 0
+		for range 2000 {
+			final := pc.simulateDealerPlay(dealerHand, deck.Cards)
+			if final.Value() == 17 && final.IsSoft() {
+				soft17++
+			}
+		}
+		if hitsSoft17 != (soft17 == 0) {
+			t.Errorf("h17=%v: dealer stood on soft 17 %d times", hitsSoft17, soft17)
+		}
+	}
+}
+
+// TestSplitWinRate 测试分牌胜率模拟：对庄家弱牌的分牌胜率高于对庄家10点牌，非对子不能分牌
+func TestSplitWinRate(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	rules.DeckCount = 6
+	rules.DoubleAfterSplit = true
+	shoe := rules.NewShoe()
+	pc := NewProbabilityCalculator(shoe, WithProbabilityRules(rules))
+	pc.trials = 4000
+
+	eights := &entities.Hand{Cards: cardsOf(entities.Eight, entities.Eight)}
+	winRate := func(up entities.Rank) float64 {
+		dealerHand := &entities.Hand{Cards: cardsOf(up, entities.Two)}
+		return pc.calculateSplitWinRate(eights, dealerHand, shoe.Cards)
+	}
+
+	weak, strong := winRate(entities.Six), winRate(entities.King)
+	if weak <= strong {
+		t.Errorf("Expected split win rate vs 6 above vs 10, got %.3f and %.3f", weak, strong)
+	}
+	if weak < 0.4 || weak > 0.6 || strong < 0.2 || strong > 0.4 {
+		t.Errorf("Expected win rates near 50%% and 30%%, got %.3f and %.3f", weak, strong)
+	}
+
+	mixed := &entities.Hand{Cards: cardsOf(entities.Eight, entities.Nine)}
+	if rate := pc.calculateSplitWinRate(mixed, &entities.Hand{Cards: cardsOf(entities.Six)}, shoe.Cards); rate != 0 {
+		t.Errorf("Expected 0 for a non-pair, got %f", rate)
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestScenarioFiles 按仓库中的场景文件走完整的游戏流程
+func TestScenarioFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file    string
+		actions []entities.PlayerAction
+		chips   int
+	}{
+		// 分牌后两手11点都加倍，庄家16点要牌爆牌：每手赢20
+		{
+			file: "split-eights-vs-six.json",
+			actions: []entities.PlayerAction{
+				entities.ActionSplit, entities.ActionDoubleDown, entities.ActionDoubleDown,
+			},
+			chips: 540,
+		},
+		// 五张牌16点赔2:1
+		{
+			file:    "pontoon-five-card-trick.json",
+			actions: []entities.PlayerAction{entities.ActionHit, entities.ActionHit, entities.ActionHit},
+			chips:   220,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(filepath.Join("..", "..", "..", "scenarios", tt.file))
+			if err != nil {
+				t.Fatalf("Unexpected read error: %v", err)
+			}
+			scenario, err := entities.ParseScenario(data, entities.DefaultRules())
+			if err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+
+			service := NewGameApplicationService("test", entities.WithScenario(scenario))
+			if err := service.StartNewRound(); err != nil {
+				t.Fatalf("Unexpected start error: %v", err)
+			}
+			if err := service.PlaceBet(10); err != nil {
+				t.Fatalf("Unexpected bet error: %v", err)
+			}
+			if err := service.DealInitialCards(); err != nil {
+				t.Fatalf("Unexpected deal error: %v", err)
+			}
+			for _, action := range tt.actions {
+				if _, err := service.ProcessPlayerAction(action); err != nil {
+					t.Fatalf("Unexpected %v error: %v", action, err)
+				}
+			}
+			if service.game.State == entities.StateDealerTurn {
+				if err := service.ProcessDealerTurn(); err != nil {
+					t.Fatalf("Unexpected dealer error: %v", err)
+				}
+			}
+
+			if result := service.EvaluateGame(); result == nil || result.PlayerChips != tt.chips {
+				t.Errorf("Expected %d chips after the scenario, got %+v", tt.chips, result)
+			}
+		})
+	}
+}
+
+// TestParseScenario 场景文件的规则覆盖与错误检查
+func TestParseScenario(t *testing.T) {
+	t.Parallel()
+
+	base := entities.DefaultRules()
+	base.DeckCount = 6
+	scenario, err := entities.ParseScenario([]byte(`{"rules": {"hole_card": "enhc", "double_restriction": "10-11"}, "cards": ["A♠", "t", "10d"]}`), base)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if scenario.Rules.DeckCount != 6 || scenario.Rules.HoleCard != entities.HoleCardENHC ||
+		scenario.Rules.DoubleRestriction != entities.DoubleTenToEleven {
+		t.Errorf("Expected the scenario rules laid over the base rules, got %+v", scenario.Rules)
+	}
+	want := []entities.Card{
+		{Suit: entities.Spades, Rank: entities.Ace},
+		{Suit: entities.Hearts, Rank: entities.Ten},
+		{Suit: entities.Diamonds, Rank: entities.Ten},
+	}
+	for i, card := range want {
+		if scenario.Cards[i] != card {
+			t.Errorf("Expected card %d to be %s, got %s", i, card, scenario.Cards[i])
+		}
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown card", `{"cards": ["1S"]}`},
+		{"unknown rule name", `{"rules": {"hole_card": "none"}, "cards": ["AS"]}`},
+		{"unknown variant", `{"variant": "baccarat", "cards": ["AS"]}`},
+		{"more cards than the shoe", `{"cards": ["AS", "AS"]}`},
+		{"tens in a spanish shoe", `{"variant": "spanish21", "cards": ["10S"]}`},
+		{"no cards", `{"name": "empty"}`},
+		{"provably fair", `{"rules": {"provably_fair": true}, "cards": ["AS"]}`},
+		{"no decks", `{"rules": {"deck_count": 0}, "cards": ["AS"]}`},
+		{"one-card charlie", `{"rules": {"charlie_cards": 1}, "cards": ["AS"]}`},
+		{"no chip denomination", `{"rules": {"chip_denomination": 0}, "cards": ["AS"]}`},
+		{"conflicting variants", `{"variant": "spanish21", "rules": {"switch_hands": true}, "cards": ["AS"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := entities.ParseScenario([]byte(tt.data), entities.DefaultRules()); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
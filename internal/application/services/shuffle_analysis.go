@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// ShuffleAnalysisParams 洗牌相关性统计参数
+type ShuffleAnalysisParams struct {
+	Model        entities.ShuffleModel
+	Imperfection float64
+	Cards        int    // 洗牌的张数
+	Stub         int    // 排在最后的余牌张数（赌场流程插入弃牌）
+	Trials       int    // 重复洗牌的次数
+	Seed         uint64 // 随机种子，0为当前时间
+}
+
+// DefaultShuffleAnalysisParams 默认统计参数：一副牌理想洗牌1000次
+func DefaultShuffleAnalysisParams() ShuffleAnalysisParams {
+	return ShuffleAnalysisParams{
+		Model:  entities.ShuffleRandom,
+		Cards:  52,
+		Trials: 1000,
+	}
+}
+
+// AnalyzeShuffle 重复洗一叠按顺序编号的牌，统计洗牌后牌序与洗牌前的残留相关性，
+// 用于评估人工洗牌方式对追踪洗牌(shuffle tracking)的可利用程度
+func AnalyzeShuffle(params ShuffleAnalysisParams) (*dtos.ShuffleAnalysisDTO, error) {
+	switch {
+	case params.Cards < 2:
+		return nil, errors.New("at least two cards are required")
+	case params.Trials <= 0:
+		return nil, errors.New("trials must be positive")
+	case params.Stub < 0 || params.Stub > params.Cards:
+		return nil, errors.New("stub must be between zero and the card count")
+	}
+
+	n := params.Cards
+	shuffler := entities.NewShuffler(params.Model, params.Imperfection, entities.RandomPCG.NewRand(params.Seed))
+	var correlation, adjacent, rising float64
+	for range params.Trials {
+		perm := shuffler.Permutation(n, params.Stub)
+		correlation += rankCorrelation(perm)
+		adjacent += float64(adjacentPairs(perm)) / float64(n-1)
+		rising += float64(risingSequences(perm))
+	}
+
+	trials := float64(params.Trials)
+	return &dtos.ShuffleAnalysisDTO{
+		Model:                   params.Model.String(),
+		Imperfection:            params.Imperfection,
+		Cards:                   n,
+		Trials:                  params.Trials,
+		RankCorrelation:         correlation / trials,
+		AdjacentPairs:           adjacent / trials,
+		ExpectedAdjacentPairs:   1 / float64(n),
+		RisingSequences:         rising / trials,
+		ExpectedRisingSequences: float64(n+1) / 2,
+	}, nil
+}
+
+// rankCorrelation 置换前后位置的Spearman秩相关系数
+func rankCorrelation(perm []int) float64 {
+	n := float64(len(perm))
+	var squares float64
+	for i, from := range perm {
+		d := float64(i - from)
+		squares += d * d
+	}
+	return 1 - 6*squares/(n*(n*n-1))
+}
+
+// adjacentPairs 原本相邻的两张牌洗牌后仍按原顺序紧挨的对数
+func adjacentPairs(perm []int) int {
+	pairs := 0
+	for i := 1; i < len(perm); i++ {
+		if perm[i] == perm[i-1]+1 {
+			pairs++
+		}
+	}
+	return pairs
+}
+
+// risingSequences 上升序列数：原顺序中后一张牌排到前一张之前的次数加一
+func risingSequences(perm []int) int {
+	positions := make([]int, len(perm))
+	for i, from := range perm {
+		positions[from] = i
+	}
+	sequences := 1
+	for card := 1; card < len(positions); card++ {
+		if positions[card] < positions[card-1] {
+			sequences++
+		}
+	}
+	return sequences
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestAnalyzeShuffle 各洗牌方式残留的牌序：理想洗牌接近理论值，人工洗牌的上升序列明显偏少
+func TestAnalyzeShuffle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		model        entities.ShuffleModel
+		imperfection float64
+		maxRising    float64 // 平均上升序列数的上限
+		minAdjacent  float64 // 相邻保留比例的下限
+	}{
+		{"理想洗牌", entities.ShuffleRandom, 0, 28, 0},
+		// 三次鸽尾最多8个上升序列，切牌再多1个
+		{"鸽尾", entities.ShuffleRiffle, 0, 9.5, 0.08},
+		{"不完美鸽尾", entities.ShuffleRiffle, 0.5, 9.5, 0.3},
+		{"切条", entities.ShuffleStrip, 0, 16, 0.4},
+		{"赌场流程", entities.ShuffleCasino, 0, 20, 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params := DefaultShuffleAnalysisParams()
+			params.Model = tt.model
+			params.Imperfection = tt.imperfection
+			params.Stub = 10
+			params.Seed = 42
+			analysis, err := AnalyzeShuffle(params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if analysis.RisingSequences > tt.maxRising {
+				t.Errorf("Expected at most %.1f rising sequences, got %.1f", tt.maxRising, analysis.RisingSequences)
+			}
+			if analysis.AdjacentPairs < tt.minAdjacent {
+				t.Errorf("Expected at least %.3f adjacent pairs kept, got %.3f", tt.minAdjacent, analysis.AdjacentPairs)
+			}
+			if tt.model == entities.ShuffleRandom {
+				if math.Abs(analysis.RisingSequences-analysis.ExpectedRisingSequences) > 1 {
+					t.Errorf("Expected about %.1f rising sequences, got %.1f", analysis.ExpectedRisingSequences, analysis.RisingSequences)
+				}
+				if math.Abs(analysis.RankCorrelation) > 0.02 {
+					t.Errorf("Expected rank correlation about 0, got %.3f", analysis.RankCorrelation)
+				}
+			}
+		})
+	}
+}
+
+// TestAnalyzeShuffleValidation 参数检查
+func TestAnalyzeShuffleValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(*ShuffleAnalysisParams)
+	}{
+		{"牌数太少", func(p *ShuffleAnalysisParams) { p.Cards = 1 }},
+		{"次数为0", func(p *ShuffleAnalysisParams) { p.Trials = 0 }},
+		{"余牌超过牌数", func(p *ShuffleAnalysisParams) { p.Stub = 53 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			params := DefaultShuffleAnalysisParams()
+			tt.modify(&params)
+			if _, err := AnalyzeShuffle(params); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// TestHumanShuffleReshuffle 人工洗牌换牌靴时用原牌靴的弃牌与余牌重洗，牌的组成不变
+func TestHumanShuffleReshuffle(t *testing.T) {
+	t.Parallel()
+
+	for _, model := range entities.ShuffleModels {
+		t.Run(model.String(), func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := entities.ParseShuffleModel(model.String())
+			if err != nil || parsed != model {
+				t.Fatalf("Expected %q to parse back to itself, got %v, %v", model.String(), parsed, err)
+			}
+
+			rules := entities.DefaultRules()
+			rules.DeckCount = 2
+			rules.ShuffleModel = model
+			rules.ShuffleImperfection = 0.3
+			game := entities.NewGame("test", entities.WithRules(rules))
+			for len(game.Deck.Cards) >= entities.CutCardReserve*rules.DeckCount {
+				if _, err := game.Deck.Deal(); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+			if err := game.StartNewRound(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			want := entities.NewShoe(rules.DeckCount).Cards
+			got := slices.Clone(game.Deck.Cards)
+			slices.SortFunc(want, compareCards)
+			slices.SortFunc(got, compareCards)
+			if !slices.Equal(got, want) {
+				t.Errorf("Expected the reshuffled shoe to hold the full %d-card shoe, got %d cards", len(want), len(got))
+			}
+		})
+	}
+
+	if _, err := entities.ParseShuffleModel("overhand"); err == nil {
+		t.Error("Expected an error for an unknown shuffle model")
+	}
+}
+
+// compareCards 按点数与花色排序卡牌
+func compareCards(a, b entities.Card) int {
+	if a.Rank != b.Rank {
+		return int(a.Rank) - int(b.Rank)
+	}
+	return int(a.Suit) - int(b.Suit)
+}
+
+// TestShuffleRandomSource 固定种子的PCG牌靴（含换牌靴后）可重现，crypto随机源忽略种子
+func TestShuffleRandomSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		source     string
+		model      entities.ShuffleModel
+		reproduced bool
+	}{
+		{"seeded pcg", "pcg", entities.ShuffleRandom, true},
+		{"seeded pcg casino", "pcg", entities.ShuffleCasino, true},
+		{"crypto", "crypto", entities.ShuffleRandom, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := entities.ParseRandomSource(tt.source)
+			if err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+			rules := entities.DefaultRules()
+			rules.RandomSource = source
+			rules.ShuffleModel = tt.model
+			rules.ShuffleSeed = 7
+
+			// 每个牌靴发到切牌位置，记录连续三个牌靴的牌序
+			shoes := func() [][]entities.Card {
+				game := entities.NewGame("test", entities.WithRules(rules))
+				var orders [][]entities.Card
+				for range 3 {
+					orders = append(orders, slices.Clone(game.Deck.Cards))
+					game.Deck.Cards = game.Deck.Cards[len(game.Deck.Cards)-1:]
+					if err := game.StartNewRound(); err != nil {
+						t.Fatalf("Unexpected start error: %v", err)
+					}
+				}
+				return orders
+			}
+			first, second := shoes(), shoes()
+
+			if slices.EqualFunc(first, second, slices.Equal) != tt.reproduced {
+				t.Errorf("Expected reproduced shoes to be %v", tt.reproduced)
+			}
+			if slices.Equal(first[0], first[1]) {
+				t.Error("Expected each reshuffle to continue the random stream, got the same shoe twice")
+			}
+		})
+	}
+
+	if _, err := entities.ParseRandomSource("time"); err == nil {
+		t.Error("Expected an error for an unknown random source")
+	}
+}
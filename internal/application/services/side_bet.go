@@ -0,0 +1,175 @@
+package services
+
+import (
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 一副牌中不同的卡牌数（13种点数 × 4种花色）
+const distinctCards = int(entities.King) * 4
+
+// cardComposition 按具体卡牌（点数与花色）统计的牌堆构成
+type cardComposition struct {
+	counts [distinctCards]int
+	total  int
+}
+
+// newCardComposition 根据剩余卡牌创建牌堆构成
+func newCardComposition(cards []entities.Card) cardComposition {
+	var comp cardComposition
+	for _, card := range cards {
+		comp.counts[cardIndex(card)]++
+		comp.total++
+	}
+	return comp
+}
+
+// cardIndex 卡牌在构成数组中的下标
+func cardIndex(card entities.Card) int {
+	return int(card.Rank-entities.Ace)*4 + int(card.Suit)
+}
+
+// cardAt 下标对应的卡牌
+func cardAt(index int) entities.Card {
+	return entities.Card{Rank: entities.Rank(index/4) + entities.Ace, Suit: entities.Suit(index % 4)}
+}
+
+// SideBetCalculator 边注期望值计算器，按剩余牌堆构成精确枚举
+type SideBetCalculator struct {
+	rules entities.Rules
+}
+
+// NewSideBetCalculator 创建边注期望值计算器
+func NewSideBetCalculator(rules entities.Rules) *SideBetCalculator {
+	return &SideBetCalculator{rules: rules}
+}
+
+// Calculate 计算边注在剩余牌堆下各牌型的概率与期望值（以边注金额为单位）
+func (c *SideBetCalculator) Calculate(betType entities.SideBetType, remainingCards []entities.Card) *dtos.SideBetEVDTO {
+	comp := newCardComposition(remainingCards)
+	probs := make(map[entities.SideBetHand]float64)
+
+	switch betType {
+	case entities.SideBetTwentyOnePlusThree:
+		c.enumerateThreeCards(comp, probs)
+	default:
+		c.enumerateTwoCards(betType, comp, probs)
+	}
+
+	result := &dtos.SideBetEVDTO{Type: betType, EV: -1}
+	for _, line := range entities.SideBetPayTables[betType] {
+		prob := probs[line.Hand]
+		result.EV += prob * float64(line.Payout+1)
+		result.Outcomes = append(result.Outcomes, &dtos.SideBetOutcomeDTO{
+			Hand:        line.Hand.String(),
+			Payout:      line.Payout,
+			Probability: prob,
+		})
+	}
+
+	return result
+}
+
+// enumerateTwoCards 枚举玩家前两张牌（对子与幸运女士边注）
+func (c *SideBetCalculator) enumerateTwoCards(
+	betType entities.SideBetType,
+	comp cardComposition,
+	probs map[entities.SideBetHand]float64,
+) {
+	if comp.total < 2 {
+		return
+	}
+
+	for first := range distinctCards {
+		if comp.counts[first] == 0 {
+			continue
+		}
+		p1 := float64(comp.counts[first]) / float64(comp.total)
+		comp.counts[first]--
+		comp.total--
+
+		for second := range distinctCards {
+			if comp.counts[second] == 0 {
+				continue
+			}
+			p2 := p1 * float64(comp.counts[second]) / float64(comp.total)
+			cards := []entities.Card{cardAt(first), cardAt(second)}
+			hand := entities.EvaluateSideBet(betType, cards, nil)
+			if hand == entities.QueenOfHeartsPair {
+				// 发牌后即结算，只有偷看底牌规则下庄家Blackjack才会在结算时已知
+				comp.counts[second]--
+				comp.total--
+				bj := c.dealerBlackjackProbability(comp)
+				comp.counts[second]++
+				comp.total++
+				probs[entities.QueenOfHeartsWithBlackjack] += p2 * bj
+				p2 *= 1 - bj
+			}
+			probs[hand] += p2
+		}
+
+		comp.counts[first]++
+		comp.total++
+	}
+}
+
+// enumerateThreeCards 枚举玩家两张牌与庄家明牌（21+3边注）
+func (c *SideBetCalculator) enumerateThreeCards(comp cardComposition, probs map[entities.SideBetHand]float64) {
+	if comp.total < 3 {
+		return
+	}
+
+	cards := make([]entities.Card, 2)
+	for first := range distinctCards {
+		if comp.counts[first] == 0 {
+			continue
+		}
+		p1 := float64(comp.counts[first]) / float64(comp.total)
+		comp.counts[first]--
+		comp.total--
+		cards[0] = cardAt(first)
+
+		for second := range distinctCards {
+			if comp.counts[second] == 0 {
+				continue
+			}
+			p2 := p1 * float64(comp.counts[second]) / float64(comp.total)
+			comp.counts[second]--
+			comp.total--
+			cards[1] = cardAt(second)
+
+			for up := range distinctCards {
+				if comp.counts[up] == 0 {
+					continue
+				}
+				p3 := p2 * float64(comp.counts[up]) / float64(comp.total)
+				hand := entities.EvaluateSideBet(entities.SideBetTwentyOnePlusThree, cards, []entities.Card{cardAt(up)})
+				probs[hand] += p3
+			}
+
+			comp.counts[second]++
+			comp.total++
+		}
+
+		comp.counts[first]++
+		comp.total++
+	}
+}
+
+// dealerBlackjackProbability 庄家两张牌为Blackjack且在边注结算时已知的概率
+func (c *SideBetCalculator) dealerBlackjackProbability(comp cardComposition) float64 {
+	if c.rules.HoleCard != entities.HoleCardPeek || comp.total < 2 {
+		return 0
+	}
+
+	aces, tens := 0, 0
+	for index, count := range comp.counts {
+		switch value := cardAt(index).BaseValue(); value {
+		case 11:
+			aces += count
+		case 10:
+			tens += count
+		}
+	}
+	return 2 * float64(aces) * float64(tens) / (float64(comp.total) * float64(comp.total-1))
+}
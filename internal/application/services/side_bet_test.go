@@ -0,0 +1,132 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestEvaluateSideBet 测试边注牌型判断
+func TestEvaluateSideBet(t *testing.T) {
+	t.Parallel()
+
+	card := func(rank entities.Rank, suit entities.Suit) entities.Card {
+		return entities.Card{Rank: rank, Suit: suit}
+	}
+	queenOfHearts := card(entities.Queen, entities.Hearts)
+
+	tests := []struct {
+		name     string
+		betType  entities.SideBetType
+		player   []entities.Card
+		dealer   []entities.Card
+		expected entities.SideBetHand
+	}{
+		{"perfect pair", entities.SideBetPerfectPairs, []entities.Card{card(entities.Eight, entities.Spades), card(entities.Eight, entities.Spades)}, nil, entities.PerfectPair},
+		{"colored pair", entities.SideBetPerfectPairs, []entities.Card{card(entities.Eight, entities.Hearts), card(entities.Eight, entities.Diamonds)}, nil, entities.ColoredPair},
+		{"mixed pair", entities.SideBetPerfectPairs, []entities.Card{card(entities.Eight, entities.Hearts), card(entities.Eight, entities.Clubs)}, nil, entities.MixedPair},
+		{"no pair", entities.SideBetPerfectPairs, []entities.Card{card(entities.Eight, entities.Hearts), card(entities.Nine, entities.Hearts)}, nil, entities.SideBetNoHand},
+		{"suited trips", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.Seven, entities.Clubs), card(entities.Seven, entities.Clubs)}, []entities.Card{card(entities.Seven, entities.Clubs)}, entities.SuitedTrips},
+		{"straight flush", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.Queen, entities.Clubs), card(entities.Ace, entities.Clubs)}, []entities.Card{card(entities.King, entities.Clubs)}, entities.StraightFlush},
+		{"three of a kind", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.Seven, entities.Clubs), card(entities.Seven, entities.Hearts)}, []entities.Card{card(entities.Seven, entities.Clubs)}, entities.ThreeOfAKind},
+		{"wheel straight", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.Three, entities.Clubs), card(entities.Ace, entities.Hearts)}, []entities.Card{card(entities.Two, entities.Clubs)}, entities.Straight},
+		{"no wraparound", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.King, entities.Clubs), card(entities.Ace, entities.Hearts)}, []entities.Card{card(entities.Two, entities.Clubs)}, entities.SideBetNoHand},
+		{"flush", entities.SideBetTwentyOnePlusThree, []entities.Card{card(entities.Two, entities.Hearts), card(entities.Nine, entities.Hearts)}, []entities.Card{card(entities.King, entities.Hearts)}, entities.Flush},
+		{"queen of hearts with blackjack", entities.SideBetLuckyLadies, []entities.Card{queenOfHearts, queenOfHearts}, []entities.Card{card(entities.Ace, entities.Clubs), card(entities.Ten, entities.Clubs)}, entities.QueenOfHeartsWithBlackjack},
+		{"queen of hearts pair", entities.SideBetLuckyLadies, []entities.Card{queenOfHearts, queenOfHearts}, []entities.Card{card(entities.Ace, entities.Clubs)}, entities.QueenOfHeartsPair},
+		{"matched twenty", entities.SideBetLuckyLadies, []entities.Card{card(entities.King, entities.Spades), card(entities.King, entities.Spades)}, nil, entities.MatchedTwenty},
+		{"suited twenty", entities.SideBetLuckyLadies, []entities.Card{card(entities.Ace, entities.Spades), card(entities.Nine, entities.Spades)}, nil, entities.SuitedTwenty},
+		{"any twenty", entities.SideBetLuckyLadies, []entities.Card{card(entities.Jack, entities.Spades), card(entities.Ten, entities.Hearts)}, nil, entities.AnyTwenty},
+		{"nineteen", entities.SideBetLuckyLadies, []entities.Card{card(entities.Jack, entities.Spades), card(entities.Nine, entities.Spades)}, nil, entities.SideBetNoHand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := entities.EvaluateSideBet(tt.betType, tt.player, tt.dealer); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestSideBetEV 测试边注期望值与按组合数手算的结果一致
+func TestSideBetEV(t *testing.T) {
+	t.Parallel()
+
+	calculator := NewSideBetCalculator(entities.DefaultRules())
+	shoe := entities.NewShoe(6).Cards
+
+	// 6副牌：同花色5/311，同色异花6/311，异色12/311
+	perfectPairs := calculator.Calculate(entities.SideBetPerfectPairs, shoe)
+	expected := (5.0*26+6.0*13+12.0*7)/311 - 1
+	if math.Abs(perfectPairs.EV-expected) > 1e-9 {
+		t.Errorf("Expected perfect pairs EV %.6f, got %.6f", expected, perfectPairs.EV)
+	}
+
+	for _, betType := range entities.SideBetTypes {
+		ev := calculator.Calculate(betType, shoe)
+		if ev.EV >= 0 || ev.EV < -0.3 {
+			t.Errorf("%v: expected a house edge between 0 and 30%%, got EV %.4f", betType, ev.EV)
+		}
+	}
+
+	// 单副牌不可能出现完美对子
+	single := calculator.Calculate(entities.SideBetPerfectPairs, entities.NewShoe(1).Cards)
+	for _, outcome := range single.Outcomes {
+		if outcome.Hand == entities.PerfectPair.String() && outcome.Probability != 0 {
+			t.Errorf("Expected no perfect pairs from a single deck, got %.6f", outcome.Probability)
+		}
+	}
+}
+
+// TestSideBetSettlement 测试边注在发牌后立即结算
+func TestSideBetSettlement(t *testing.T) {
+	t.Parallel()
+
+	service := NewGameApplicationService("test")
+	if err := service.StartNewRound(); err != nil {
+		t.Fatalf("Unexpected start error: %v", err)
+	}
+	if err := service.PlaceSideBet(entities.SideBetPerfectPairs, 10); err == nil {
+		t.Error("Expected a side bet before the main bet to be rejected")
+	}
+	if err := service.PlaceBet(10); err != nil {
+		t.Fatalf("Unexpected bet error: %v", err)
+	}
+	if err := service.PlaceSideBet(entities.SideBetPerfectPairs, 10); err != nil {
+		t.Fatalf("Unexpected side bet error: %v", err)
+	}
+	if err := service.PlaceSideBet(entities.SideBetLuckyLadies, 5); err != nil {
+		t.Fatalf("Unexpected side bet error: %v", err)
+	}
+
+	// 玩家8♥ 8♦（同色对子），庄家明牌7
+	service.game.Deck.Cards = append([]entities.Card{
+		{Rank: entities.Eight, Suit: entities.Hearts},
+		{Rank: entities.Seven, Suit: entities.Clubs},
+		{Rank: entities.Eight, Suit: entities.Diamonds},
+		{Rank: entities.Nine, Suit: entities.Clubs},
+	}, service.game.Deck.Cards...)
+	if err := service.DealInitialCards(); err != nil {
+		t.Fatalf("Unexpected deal error: %v", err)
+	}
+
+	results := service.GetSideBetResults()
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 side bet results, got %d", len(results))
+	}
+	if results[0].Hand != entities.ColoredPair.String() || results[0].NetValue != 120 {
+		t.Errorf("Expected colored pair paying 120, got %s %d", results[0].Hand, results[0].NetValue)
+	}
+	if results[1].Payout != 0 || results[1].NetValue != -5 {
+		t.Errorf("Expected lucky ladies to lose 5, got %+v", results[1])
+	}
+
+	// 1000 - 10主注 - 10 - 5边注 + 10 + 120
+	if chips := service.GetGameState().PlayerChips; chips != 1105 {
+		t.Errorf("Expected 1105 chips after settlement, got %d", chips)
+	}
+}
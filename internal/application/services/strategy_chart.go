@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// chartValues 策略表各列的庄家明牌与各对子行的点数：2到10，A在最后
+var chartValues = []int{2, 3, 4, 5, 6, 7, 8, 9, tenValue, aceValue}
+
+// GenerateStrategyChart 按规则生成总点数基本策略表：硬牌、软牌与对子各行对庄家每张明牌的最优操作
+// 每行用一手代表牌计算，options传入WithEVInfiniteDeck()时按无限副牌计算，结果与移除的牌无关
+func GenerateStrategyChart(rules entities.Rules, options ...EVOption) (*dtos.StrategyChartDTO, error) {
+	if rules.Pontoon || rules.DoubleExposure {
+		return nil, errors.New("strategy chart needs exactly one dealer upcard")
+	}
+
+	ev := NewEVCalculator(rules, options...)
+	chart := &dtos.StrategyChartDTO{}
+	for _, up := range chartValues {
+		chart.DealerUpCards = append(chart.DealerUpCards, entities.Rank(up).String())
+	}
+
+	row := func(label string, first, second int) *dtos.StrategyChartRowDTO {
+		cards := []entities.Card{cardOfPoint(first), cardOfPoint(second)}
+		result := &dtos.StrategyChartRowDTO{Hand: label}
+		for _, up := range chartValues {
+			action, _ := ev.CalculateBasicStrategyEVs(cards, cardOfPoint(up), true, first == second, true).Best()
+			result.Actions = append(result.Actions, action)
+		}
+		return result
+	}
+
+	// 硬5到硬11用2加另一张牌，硬12到硬19用10加另一张牌，避开对子
+	for total := 5; total <= 19; total++ {
+		first := 2
+		if total >= 12 {
+			first = tenValue
+		}
+		chart.Hard = append(chart.Hard, row(fmt.Sprint(total), first, total-first))
+	}
+	for second := 2; second <= 9; second++ {
+		chart.Soft = append(chart.Soft, row("A-"+entities.Rank(second).String(), aceValue, second))
+	}
+	for _, pair := range chartValues {
+		name := entities.Rank(pair).String()
+		chart.Pairs = append(chart.Pairs, row(name+"-"+name, pair, pair))
+	}
+	return chart, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// TestGenerateStrategyChart 测试基本策略表：无限副牌下与多副牌基本策略一致，单副牌下9点对2改为加倍
+func TestGenerateStrategyChart(t *testing.T) {
+	t.Parallel()
+
+	rules := entities.DefaultRules()
+	infinite, err := GenerateStrategyChart(rules, WithEVInfiniteDeck())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	finite, err := GenerateStrategyChart(rules)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(infinite.Hard) != 15 || len(infinite.Soft) != 8 || len(infinite.Pairs) != 10 {
+		t.Errorf("Expected 15 hard, 8 soft and 10 pair rows, got %d, %d and %d",
+			len(infinite.Hard), len(infinite.Soft), len(infinite.Pairs))
+	}
+
+	tests := []struct {
+		name     string
+		rows     []*dtos.StrategyChartRowDTO
+		hand     string
+		up       string
+		expected entities.PlayerAction
+	}{
+		{"infinite 16 vs 10", infinite.Hard, "16", "10", entities.ActionHit},
+		{"infinite 12 vs 3", infinite.Hard, "12", "3", entities.ActionHit},
+		{"infinite 11 vs A", infinite.Hard, "11", "A", entities.ActionHit},
+		{"infinite A-7 vs 9", infinite.Soft, "A-7", "9", entities.ActionHit},
+		{"infinite 9-9 vs 7", infinite.Pairs, "9-9", "7", entities.ActionStand},
+		{"infinite 8-8 vs A", infinite.Pairs, "8-8", "A", entities.ActionSplit},
+		{"infinite 9 vs 2", infinite.Hard, "9", "2", entities.ActionHit},
+		{"single deck 9 vs 2", finite.Hard, "9", "2", entities.ActionDoubleDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			row := tt.rows[slices.IndexFunc(tt.rows, func(row *dtos.StrategyChartRowDTO) bool { return row.Hand == tt.hand })]
+			if action := row.Actions[slices.Index(infinite.DealerUpCards, tt.up)]; action != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, action)
+			}
+		})
+	}
+
+	if _, err := GenerateStrategyChart(entities.VariantRules(entities.VariantPontoon)); err == nil {
+		t.Error("Expected an error for Pontoon, whose dealer cards are both hidden")
+	}
+}
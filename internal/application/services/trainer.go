@@ -0,0 +1,238 @@
+package services
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// HandCategory 手牌类别
+type HandCategory int
+
+const (
+	// CategoryHard represents hands without an ace counted as 11
+	CategoryHard HandCategory = iota
+	// CategorySoft represents hands with an ace counted as 11
+	CategorySoft
+	// CategoryPair represents two cards of the same rank
+	CategoryPair
+)
+
+// HandCategories 所有手牌类别
+var HandCategories = []HandCategory{CategoryHard, CategorySoft, CategoryPair}
+
+func (c HandCategory) String() string {
+	switch c {
+	case CategoryHard:
+		return "hard"
+	case CategorySoft:
+		return "soft"
+	case CategoryPair:
+		return "pair"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyHand 判断手牌类别
+func ClassifyHand(hand *entities.Hand) HandCategory {
+	if len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank {
+		return CategoryPair
+	}
+	if hand.IsSoft() {
+		return CategorySoft
+	}
+	return CategoryHard
+}
+
+// DecisionGrade 单次决策评分
+type DecisionGrade struct {
+	Category     HandCategory
+	ChosenAction entities.PlayerAction
+	BestAction   entities.PlayerAction
+	ChosenEV     float64
+	BestEV       float64
+	EVCost       float64 // 选择错误的期望损失（以初始注码为单位）
+	IsCorrect    bool
+}
+
+// CategoryStats 单个类别的训练统计
+type CategoryStats struct {
+	Category    HandCategory
+	Decisions   int
+	Correct     int
+	TotalEVCost float64
+}
+
+// Accuracy 正确率
+func (s CategoryStats) Accuracy() float64 {
+	if s.Decisions == 0 {
+		return 0
+	}
+	return float64(s.Correct) / float64(s.Decisions)
+}
+
+// StrategyDrill 专项练习题目
+type StrategyDrill struct {
+	Category     HandCategory
+	PlayerHand   *entities.Hand
+	DealerUpCard entities.Card
+	CanDouble    bool
+	CanSplit     bool
+	CanSurrender bool
+}
+
+// StrategyTrainer 基本策略训练器
+type StrategyTrainer struct {
+	ev    *EVCalculator
+	rules entities.Rules
+	stats map[HandCategory]*CategoryStats
+	rng   *rand.Rand
+}
+
+// NewStrategyTrainer 创建基本策略训练器
+func NewStrategyTrainer(rules entities.Rules) *StrategyTrainer {
+	stats := make(map[HandCategory]*CategoryStats, len(HandCategories))
+	for _, category := range HandCategories {
+		stats[category] = &CategoryStats{Category: category}
+	}
+
+	return &StrategyTrainer{
+		ev:    NewEVCalculator(rules),
+		rules: rules,
+		stats: stats,
+		rng:   rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()<<32))),
+	}
+}
+
+// Grade 将玩家选择与基本策略比较并记录统计，选择不可用的操作时返回nil
+func (t *StrategyTrainer) Grade(
+	playerHand *entities.Hand,
+	dealerUpCard entities.Card,
+	chosen entities.PlayerAction,
+	canDouble bool,
+	canSplit bool,
+	canSurrender bool,
+) *DecisionGrade {
+	evs := t.ev.CalculateBasicStrategyEVs(playerHand.Cards, dealerUpCard, canDouble, canSplit, canSurrender)
+	bestAction, bestEV := evs.Best()
+
+	chosenEV, ok := evs.ValueOf(chosen)
+	if !ok {
+		return nil
+	}
+
+	grade := &DecisionGrade{
+		Category:     ClassifyHand(playerHand),
+		ChosenAction: chosen,
+		BestAction:   bestAction,
+		ChosenEV:     chosenEV,
+		BestEV:       bestEV,
+		EVCost:       max(0, bestEV-chosenEV),
+		IsCorrect:    chosen == bestAction || chosenEV >= bestEV,
+	}
+
+	stats := t.stats[grade.Category]
+	stats.Decisions++
+	stats.TotalEVCost += grade.EVCost
+	if grade.IsCorrect {
+		stats.Correct++
+	}
+
+	return grade
+}
+
+// Stats 返回各类别的训练统计
+func (t *StrategyTrainer) Stats() []CategoryStats {
+	result := make([]CategoryStats, 0, len(HandCategories))
+	for _, category := range HandCategories {
+		result = append(result, *t.stats[category])
+	}
+	return result
+}
+
+// WeakestCategory 返回错误率最高的类别（未练习的类别按50%估计）
+func (t *StrategyTrainer) WeakestCategory() HandCategory {
+	weakest := CategoryHard
+	highest := -1.0
+	for _, category := range HandCategories {
+		if rate := t.errorRate(category); rate > highest {
+			weakest = category
+			highest = rate
+		}
+	}
+	return weakest
+}
+
+// errorRate 平滑后的错误率
+func (t *StrategyTrainer) errorRate(category HandCategory) float64 {
+	stats := t.stats[category]
+	return float64(stats.Decisions-stats.Correct+1) / float64(stats.Decisions+2)
+}
+
+// NextDrill 生成专项练习题目，错误率越高的类别出现概率越大
+func (t *StrategyTrainer) NextDrill() *StrategyDrill {
+	total := 0.0
+	for _, category := range HandCategories {
+		total += t.errorRate(category)
+	}
+
+	category := HandCategories[len(HandCategories)-1]
+	pick := t.rng.Float64() * total
+	for _, c := range HandCategories {
+		pick -= t.errorRate(c)
+		if pick < 0 {
+			category = c
+			break
+		}
+	}
+
+	hand := t.drillHand(category)
+	return &StrategyDrill{
+		Category:     category,
+		PlayerHand:   hand,
+		DealerUpCard: t.randomCard(entities.Rank(t.rng.IntN(int(entities.King)) + 1)),
+		CanDouble:    t.rules.CanDouble(hand),
+		CanSplit:     category == CategoryPair,
+		CanSurrender: t.rules.LateSurrender,
+	}
+}
+
+// drillHand 生成指定类别的两张牌
+func (t *StrategyTrainer) drillHand(category HandCategory) *entities.Hand {
+	hand := entities.NewHand()
+
+	switch category {
+	case CategoryPair:
+		rank := entities.Rank(t.rng.IntN(int(entities.Ten)) + 1)
+		hand.AddCard(t.randomCard(rank))
+		hand.AddCard(t.randomCard(rank))
+	case CategorySoft:
+		// A2 - A9
+		hand.AddCard(t.randomCard(entities.Ace))
+		hand.AddCard(t.randomCard(entities.Rank(t.rng.IntN(8) + 2)))
+	default:
+		// 硬点数5-17，不含对子和A
+		for {
+			first := entities.Rank(t.rng.IntN(9) + 2)
+			second := entities.Rank(t.rng.IntN(12) + 2)
+			total := entities.Card{Rank: first}.BaseValue() + entities.Card{Rank: second}.BaseValue()
+			if first != second && total <= 17 {
+				hand.AddCard(t.randomCard(first))
+				hand.AddCard(t.randomCard(second))
+				break
+			}
+		}
+	}
+
+	return hand
+}
+
+// randomCard 生成随机花色的指定牌面
+func (t *StrategyTrainer) randomCard(rank entities.Rank) entities.Card {
+	return entities.Card{
+		Suit: entities.Suit(t.rng.IntN(int(entities.Spades) + 1)),
+		Rank: rank,
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// handOf 创建手牌
+func handOf(ranks ...entities.Rank) *entities.Hand {
+	hand := entities.NewHand()
+	for _, card := range cardsOf(ranks...) {
+		hand.AddCard(card)
+	}
+	return hand
+}
+
+// TestClassifyHand 测试手牌分类
+func TestClassifyHand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hand     *entities.Hand
+		expected HandCategory
+	}{
+		{"hard", handOf(entities.Ten, entities.Six), CategoryHard},
+		{"soft", handOf(entities.Ace, entities.Six), CategorySoft},
+		{"pair", handOf(entities.Eight, entities.Eight), CategoryPair},
+		{"pair_aces", handOf(entities.Ace, entities.Ace), CategoryPair},
+		{"hard_after_hit", handOf(entities.Ace, entities.Six, entities.Nine), CategoryHard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ClassifyHand(tt.hand); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestStrategyTrainerGrade 测试决策评分与统计
+func TestStrategyTrainerGrade(t *testing.T) {
+	t.Parallel()
+
+	trainer := NewStrategyTrainer(entities.DefaultRules())
+	dealerUp := entities.Card{Suit: entities.Clubs, Rank: entities.Six}
+
+	correct := trainer.Grade(handOf(entities.Six, entities.Five), dealerUp, entities.ActionDoubleDown, true, false, true)
+	if correct == nil || !correct.IsCorrect || correct.EVCost != 0 {
+		t.Fatalf("Expected doubling 11 vs 6 to be correct, got %+v", correct)
+	}
+
+	wrong := trainer.Grade(handOf(entities.Six, entities.Five), dealerUp, entities.ActionStand, true, false, true)
+	if wrong == nil || wrong.IsCorrect || wrong.EVCost <= 0 {
+		t.Fatalf("Expected standing on 11 vs 6 to be a mistake, got %+v", wrong)
+	}
+	if wrong.BestAction != entities.ActionDoubleDown {
+		t.Errorf("Expected best action double, got %v", wrong.BestAction)
+	}
+
+	if unavailable := trainer.Grade(handOf(entities.Six, entities.Five), dealerUp, entities.ActionSplit, true, false, true); unavailable != nil {
+		t.Error("Expected nil grade for unavailable action")
+	}
+
+	stats := trainer.Stats()
+	hard := stats[CategoryHard]
+	if hard.Decisions != 2 || hard.Correct != 1 {
+		t.Errorf("Expected 1/2 hard decisions correct, got %d/%d", hard.Correct, hard.Decisions)
+	}
+	if trainer.WeakestCategory() != CategoryHard {
+		t.Errorf("Expected weakest category hard, got %v", trainer.WeakestCategory())
+	}
+}
+
+// TestStrategyTrainerDrill 测试专项练习题目生成
+func TestStrategyTrainerDrill(t *testing.T) {
+	t.Parallel()
+
+	trainer := NewStrategyTrainer(entities.DefaultRules())
+
+	for range 200 {
+		drill := trainer.NextDrill()
+		if len(drill.PlayerHand.Cards) != 2 {
+			t.Fatalf("Expected two-card drill hand, got %d cards", len(drill.PlayerHand.Cards))
+		}
+		if got := ClassifyHand(drill.PlayerHand); got != drill.Category {
+			t.Errorf("Drill hand %s classified as %v, expected %v", drill.PlayerHand, got, drill.Category)
+		}
+		if drill.CanSplit != (drill.Category == CategoryPair) {
+			t.Errorf("Split availability mismatch for %v", drill.Category)
+		}
+		if drill.DealerUpCard.Rank < entities.Ace || drill.DealerUpCard.Rank > entities.King {
+			t.Errorf("Invalid dealer up card %v", drill.DealerUpCard)
+		}
+	}
+}
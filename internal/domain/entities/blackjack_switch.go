@@ -0,0 +1,41 @@
+package entities
+
+import "errors"
+
+// BlackjackSwitchRules 换牌21点的常见规则：6副牌，H17，每局两手牌可交换第二张牌，
+// Blackjack只赔1:1，庄家22点与玩家平局，分牌后可加倍
+func BlackjackSwitchRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = VariantSwitch
+	rules.DeckCount = 6
+	rules.BlackjackPayout = 1
+	rules.DealerHitsSoft17 = true
+	rules.DoubleAfterSplit = true
+	rules.SwitchHands = true
+	rules.Dealer22Push = true
+	return rules
+}
+
+// CanPlayerSwitch 玩家是否可以交换两手牌的第二张牌（只能在第一手行动之前）
+func (g *Game) CanPlayerSwitch() bool {
+	if !g.Rules.SwitchHands || g.State != StatePlayerTurn || len(g.Player.Hands) != 2 {
+		return false
+	}
+
+	first, second := g.Player.Hands[0], g.Player.Hands[1]
+	return g.Player.PlayerHand == first && !first.Switched && !first.Finished &&
+		len(first.Hand.Cards) == 2 && len(second.Hand.Cards) == 2
+}
+
+// PlayerSwitch 交换两手牌的第二张牌，换牌后的A+10只算21点
+func (g *Game) PlayerSwitch() error {
+	if !g.CanPlayerSwitch() {
+		return errors.New("cannot switch cards")
+	}
+
+	first, second := g.Player.Hands[0].Hand, g.Player.Hands[1].Hand
+	first.Cards[1], second.Cards[1] = second.Cards[1], first.Cards[1]
+	g.Player.Hands[0].Switched = true
+	g.Player.Hands[1].Switched = true
+	return nil
+}
@@ -4,6 +4,7 @@ package entities
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // Suit 花色枚举
@@ -35,6 +36,11 @@ func (s Suit) String() string {
 	}
 }
 
+// IsRed 是否为红色花色
+func (s Suit) IsRed() bool {
+	return s == Hearts || s == Diamonds
+}
+
 // Rank 牌面枚举
 type Rank int
 
@@ -100,6 +106,47 @@ func (c Card) String() string {
 	return fmt.Sprintf("%s%s", c.Rank, c.Suit)
 }
 
+// suitSymbols 花色的文字写法（符号与英文首字母）
+var suitSymbols = map[Suit][]string{
+	Hearts:   {"♥", "H"},
+	Diamonds: {"♦", "D"},
+	Clubs:    {"♣", "C"},
+	Spades:   {"♠", "S"},
+}
+
+// ParseCard 解析卡牌文字：点数为A、2-10、T、J、Q、K，花色为♥♦♣♠或H、D、C、S，省略花色时为红心
+func ParseCard(text string) (Card, error) {
+	name := strings.ToUpper(strings.TrimSpace(text))
+	card := Card{Suit: Hearts}
+	for suit := Hearts; suit <= Spades; suit++ {
+		for _, symbol := range suitSymbols[suit] {
+			if rank, ok := strings.CutSuffix(name, symbol); ok && rank != "" {
+				card.Suit, name = suit, rank
+			}
+		}
+	}
+
+	switch name {
+	case "A":
+		card.Rank = Ace
+	case "T", "10":
+		card.Rank = Ten
+	case "J":
+		card.Rank = Jack
+	case "Q":
+		card.Rank = Queen
+	case "K":
+		card.Rank = King
+	default:
+		value, err := strconv.Atoi(name)
+		if err != nil || value < 2 || value > 9 {
+			return Card{}, fmt.Errorf("unknown card %q", text)
+		}
+		card.Rank = Rank(value)
+	}
+	return card, nil
+}
+
 // BaseValue 获取牌的基础点数
 func (c Card) BaseValue() int {
 	switch c.Rank {
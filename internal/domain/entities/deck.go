@@ -3,40 +3,84 @@ package entities
 import (
 	"errors"
 	"math/rand/v2"
-	"time"
+	"slices"
 )
 
+// CutCardReserve 切牌位置之后每副牌保留的张数，牌靴剩余不足时换新牌靴
+const CutCardReserve = 10
+
 // Deck 牌堆结构
 type Deck struct {
 	Cards []Card
+	order []Card     // 洗牌后的完整牌序，人工洗牌方式用它找回已发的弃牌
+	rng   *rand.Rand // 洗牌随机数生成器，换牌靴时沿用
 }
 
 // NewDeck 创建新牌堆
 func NewDeck() *Deck {
-	deck := &Deck{}
+	return NewShoe(1)
+}
 
-	// 创建52张牌
-	for suit := Hearts; suit <= Spades; suit++ {
-		for rank := Ace; rank <= King; rank++ {
-			deck.Cards = append(deck.Cards, Card{Suit: suit, Rank: rank})
-		}
+// NewShoe 创建由多副牌组成的牌靴
+func NewShoe(deckCount int) *Deck {
+	deck := newOrderedShoe(deckCount)
+	deck.Shuffle()
+	return deck
+}
+
+// newOrderedShoe 创建按出厂顺序排列的牌靴
+func newOrderedShoe(deckCount int) *Deck {
+	deckCount = max(deckCount, 1)
+	deck := &Deck{
+		Cards: make([]Card, 0, 52*deckCount),
 	}
 
-	deck.Shuffle()
+	// 每副创建52张牌
+	for range deckCount {
+		for suit := Hearts; suit <= Spades; suit++ {
+			for rank := Ace; rank <= King; rank++ {
+				deck.Cards = append(deck.Cards, Card{Suit: suit, Rank: rank})
+			}
+		}
+	}
 	return deck
 }
 
 // Shuffle 洗牌
 func (d *Deck) Shuffle() {
-	seed := uint64(time.Now().UnixNano())
-	rd := rand.New(rand.NewPCG(seed, seed>>32))
-
+	rd := d.random()
 	for i := len(d.Cards) - 1; i > 0; i-- {
 		j := rd.IntN(i + 1)
 		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 	}
 }
 
+// InsertRandomly 将卡牌逐张插回牌堆的随机位置（连续洗牌机回收弃牌）
+func (d *Deck) InsertRandomly(cards ...Card) {
+	rd := d.random()
+	for _, card := range cards {
+		d.Cards = slices.Insert(d.Cards, rd.IntN(len(d.Cards)+1), card)
+	}
+}
+
+// random 牌堆的随机数生成器，未指定时使用按当前时间播种的PCG
+func (d *Deck) random() *rand.Rand {
+	if d.rng == nil {
+		d.rng = RandomPCG.NewRand(0)
+	}
+	return d.rng
+}
+
+// Remove 从牌堆中移除一张指定的牌，返回是否找到
+func (d *Deck) Remove(card Card) bool {
+	i := slices.Index(d.Cards, card)
+	if i < 0 {
+		return false
+	}
+	d.Cards = slices.Delete(d.Cards, i, i+1)
+	return true
+}
+
 // Deal 发牌
 func (d *Deck) Deal() (Card, error) {
 	if len(d.Cards) == 0 {
@@ -0,0 +1,42 @@
+package entities
+
+// DiscardTray 弃牌架：收集每局结束后桌上的牌，以及洗牌后烧掉的暗牌
+type DiscardTray struct {
+	Cards  []Card // 亮过的弃牌，按放入顺序
+	Burned []Card // 烧掉的牌，牌面朝下，玩家看不到
+}
+
+// NewDiscardTray 创建空的弃牌架
+func NewDiscardTray() *DiscardTray {
+	return &DiscardTray{}
+}
+
+// Discard 放入亮过的弃牌
+func (t *DiscardTray) Discard(cards ...Card) {
+	t.Cards = append(t.Cards, cards...)
+}
+
+// Burn 放入一张烧掉的暗牌
+func (t *DiscardTray) Burn(card Card) {
+	t.Burned = append(t.Burned, card)
+}
+
+// Len 弃牌架上的总张数（含烧牌）
+func (t *DiscardTray) Len() int {
+	return len(t.Cards) + len(t.Burned)
+}
+
+// Composition 亮过的弃牌按点数的张数
+func (t *DiscardTray) Composition() map[Rank]int {
+	composition := make(map[Rank]int)
+	for _, card := range t.Cards {
+		composition[card.Rank]++
+	}
+	return composition
+}
+
+// Clear 洗牌时清空弃牌架
+func (t *DiscardTray) Clear() {
+	t.Cards = nil
+	t.Burned = nil
+}
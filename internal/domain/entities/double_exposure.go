@@ -0,0 +1,14 @@
+package entities
+
+// DoubleExposureRules 双明牌21点的常见规则：6副牌，庄家两张牌都明牌，
+// Blackjack只赔1:1，平局庄家赢（玩家Blackjack除外），只有硬9-11点可加倍
+func DoubleExposureRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = VariantDoubleExposure
+	rules.DeckCount = 6
+	rules.BlackjackPayout = 1
+	rules.DoubleRestriction = DoubleNineToEleven
+	rules.DoubleAfterSplit = true
+	rules.DoubleExposure = true
+	return rules
+}
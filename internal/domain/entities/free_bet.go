@@ -0,0 +1,15 @@
+package entities
+
+// FreeBetRules 免费下注21点的常见规则：6副牌，H17，硬9-11点免费加倍，
+// 10点牌以外的对子免费分牌，庄家22点与玩家平局，分牌后可加倍
+func FreeBetRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = VariantFreeBet
+	rules.DeckCount = 6
+	rules.DealerHitsSoft17 = true
+	rules.DoubleAfterSplit = true
+	rules.FreeDoubles = true
+	rules.FreeSplits = true
+	rules.Dealer22Push = true
+	return rules
+}
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 )
@@ -26,22 +28,59 @@ type Game struct {
 	Player      *Player
 	Dealer      *Dealer
 	Deck        *Deck
+	Discards    *DiscardTray
+	Rules       Rules
 	State       GameState
 	RoundNumber int
 	IsActive    bool
+
+	SideBetResults []*SideBetResult // 本局边注结算结果
+
+	Fair        *FairShoe   // 可验证公平模式下的当前牌靴
+	FairNext    *FairShoe   // 可验证公平模式下为下一个牌靴公布的承诺，玩家可在它开始前设置或更换客户端种子
+	FairHistory []*FairShoe // 已结束并公开服务器种子的牌靴
+	clientSeed  string
+	scenario    *Scenario
+}
+
+// GameOption is a function type for configuring a new game
+type GameOption func(game *Game)
+
+// WithRules configures the table rules of the game
+func WithRules(rules Rules) GameOption {
+	return func(game *Game) {
+		game.Rules = rules
+	}
 }
 
 // NewGame 创建新游戏
-func NewGame(playerName string) *Game {
-	return &Game{
+func NewGame(playerName string, options ...GameOption) *Game {
+	game := &Game{
 		ID:          generateGameID(),
 		Player:      NewPlayer(playerName, 1000),
 		Dealer:      NewDealer(),
-		Deck:        NewDeck(),
+		Discards:    NewDiscardTray(),
+		Rules:       DefaultRules(),
 		State:       StateWaitingToBet,
 		RoundNumber: 0,
 		IsActive:    true,
 	}
+
+	for _, option := range options {
+		option(game)
+	}
+
+	if game.Rules.ProvablyFair {
+		// 可验证公平牌靴要等玩家看到承诺并设置客户端种子，第一局开始时才洗出
+		game.Deck = &Deck{}
+		game.FairNext, _ = NewFairCommitment(game.Rules, 1)
+	} else if err := game.reshuffle(nil); err != nil {
+		game.Deck = game.Rules.NewShoe()
+	}
+	if game.scenario != nil {
+		game.scenario.stack(game)
+	}
+	return game
 }
 
 // StartNewRound 开始新一轮游戏
@@ -51,11 +90,15 @@ func (g *Game) StartNewRound() error {
 	}
 
 	g.RoundNumber++
+	g.SideBetResults = nil
+	// 上一局的牌收进弃牌架（连续洗牌机结算时已插回牌靴）
+	if !g.Rules.ContinuousShuffle {
+		g.Discards.Discard(g.GetUsedCards()...)
+	}
 	g.Player.ResetRound()
 	g.Dealer.ResetRound()
-	g.ensureDeckSize()
 
-	return nil
+	return g.ensureDeckSize()
 }
 
 // PlaceBet 下注
@@ -64,78 +107,323 @@ func (g *Game) PlaceBet(amount int) error {
 		return errors.New("cannot place bet in current state")
 	}
 
-	if !g.Player.PlaceBet(amount) {
-		return errors.New("cannot place bet")
+	if err := g.Rules.ValidateBet(amount); err != nil {
+		return err
+	}
+
+	// 换牌玩法每手下同样的注码
+	hands := g.Rules.HandsPerRound()
+	if !g.Player.CanBet(amount * hands) {
+		if hands > 1 {
+			return fmt.Errorf("bet %d on %d hands exceeds available chips %d", amount, hands, g.Player.Chips)
+		}
+		return fmt.Errorf("bet %d exceeds available chips %d", amount, g.Player.Chips)
+	}
+
+	g.Player.PlaceBet(amount)
+	for range hands - 1 {
+		g.Player.AddHand(amount)
 	}
 
 	g.State = StatePlayerTurn
 	return nil
 }
 
+// PlaceSideBet 下边注，须在主注之后、发牌之前
+func (g *Game) PlaceSideBet(betType SideBetType, amount int) error {
+	if g.State != StatePlayerTurn || len(g.Player.Hand.Cards) > 0 {
+		return errors.New("side bets must be placed after the main bet and before the deal")
+	}
+
+	if err := g.Rules.ValidateSideBet(amount); err != nil {
+		return err
+	}
+
+	if !g.Player.PlaceSideBet(betType, amount) {
+		return fmt.Errorf("side bet %d exceeds available chips %d", amount, g.Player.Chips)
+	}
+
+	return nil
+}
+
+// settleSideBets 发牌后立即结算所有边注
+func (g *Game) settleSideBets() {
+	for _, betType := range SideBetTypes {
+		amount, ok := g.Player.SideBets[betType]
+		if !ok {
+			continue
+		}
+
+		hand := EvaluateSideBet(betType, g.Player.Hand.Cards, g.Dealer.Hand.Cards)
+		result := &SideBetResult{
+			Type:     betType,
+			Amount:   amount,
+			Hand:     hand,
+			Payout:   SideBetPayoutOf(betType, hand),
+			NetValue: -amount,
+		}
+		if result.Payout > 0 {
+			result.NetValue = amount * result.Payout
+			g.Player.Chips += amount + result.NetValue
+		}
+		g.SideBetResults = append(g.SideBetResults, result)
+	}
+	g.Player.SideBets = nil
+}
+
 // DealInitialCards 发初始牌
 func (g *Game) DealInitialCards() error {
 	if g.State != StatePlayerTurn {
 		return errors.New("cannot deal cards in current state")
 	}
 
-	// 发两张牌给玩家和庄家
-	for range 2 {
-		card, err := g.Deck.Deal()
+	// 发两张牌给玩家每手牌和庄家（欧式无底牌规则下庄家只发明牌）
+	for i := range 2 {
+		for _, hand := range g.Player.Hands {
+			card, err := g.dealCard()
+			if err != nil {
+				return err
+			}
+			hand.Hand.AddCard(card)
+		}
+
+		if i > 0 && g.Rules.HoleCard != HoleCardPeek {
+			continue
+		}
+		card, err := g.dealCard()
 		if err != nil {
 			return err
 		}
-		g.Player.Hand.AddCard(card)
+		g.Dealer.Hand.AddCard(card)
+	}
+
+	g.settleSideBets()
+
+	// 庄家偷看到Blackjack时本局直接结算，玩家无需行动
+	if g.DealerPeekedBlackjack() {
+		g.State = StateDealerTurn
+		return nil
+	}
+
+	return g.startHand()
+}
 
-		card, err = g.Deck.Deal()
+// startHand 开始当前手牌：分牌后的手牌补第二张牌，分A只补一张（可再分A时补到A仍可分牌），Blackjack无需行动
+func (g *Game) startHand() error {
+	hand := g.Player.PlayerHand
+	if len(hand.Hand.Cards) == 1 {
+		card, err := g.dealCard()
 		if err != nil {
 			return err
 		}
-		g.Dealer.Hand.AddCard(card)
+		hand.Hand.AddCard(card)
+
+		if g.splitAcesLocked() && !g.CanPlayerSplit() {
+			return g.finishHand()
+		}
 	}
 
+	if (hand.IsBlackjack() || g.Rules.IsAutoWin(hand.Hand)) && !g.CanPlayerSwitch() {
+		return g.finishHand()
+	}
 	return nil
 }
 
+// finishHand 结束当前手牌并切换到下一手，所有手牌完成后进入庄家回合
+func (g *Game) finishHand() error {
+	g.Player.Finished = true
+	if g.Player.NextHand() {
+		return g.startHand()
+	}
+
+	g.State = StateDealerTurn
+	return nil
+}
+
+// DealerPeekedBlackjack 美式规则下庄家偷看底牌发现Blackjack
+func (g *Game) DealerPeekedBlackjack() bool {
+	return g.Rules.HoleCard == HoleCardPeek && g.Dealer.Hand.IsBlackjack()
+}
+
 // PlayerHit 玩家要牌
 func (g *Game) PlayerHit() (Card, error) {
 	if g.State != StatePlayerTurn {
 		return Card{}, errors.New("not player's turn")
 	}
 
-	card, err := g.Deck.Deal()
+	if !g.CanPlayerHit() {
+		return Card{}, errors.New("cannot hit this hand")
+	}
+
+	card, err := g.dealPlayerCard()
 	if err != nil {
 		return Card{}, err
 	}
+	g.Player.Twisted = true
 
-	g.Player.Hand.AddCard(card)
+	// 爆牌、查理或21点必胜时自动结束当前手牌
+	if g.Player.Hand.IsBust() || g.Rules.IsAutoWin(g.Player.Hand) {
+		return card, g.finishHand()
+	}
+	return card, nil
+}
 
+// dealCard 从牌靴发一张牌，局中牌靴发空时换新牌靴（去掉桌上的牌）继续发
+func (g *Game) dealCard() (Card, error) {
+	if len(g.Deck.Cards) == 0 {
+		if err := g.reshuffle(g.GetUsedCards()); err != nil {
+			return Card{}, err
+		}
+	}
+	return g.Deck.Deal()
+}
+
+// dealPlayerCard 给玩家当前手牌发一张牌
+func (g *Game) dealPlayerCard() (Card, error) {
+	card, err := g.dealCard()
+	if err != nil {
+		return Card{}, err
+	}
+
+	g.Player.Hand.AddCard(card)
 	return card, nil
 }
 
 // PlayerStand 玩家停牌
-func (g *Game) PlayerStand() {
+func (g *Game) PlayerStand() error {
+	if g.State != StatePlayerTurn {
+		return errors.New("not player's turn")
+	}
+	if !g.Rules.CanStick(g.Player.Hand) {
+		return fmt.Errorf("must reach %d to stick", PontoonMinStick)
+	}
+
+	return g.finishHand()
+}
+
+// CanPlayerHit 玩家是否可以要牌（加倍后与只补一张的分A不能再要牌，Pontoon买牌后可以）
+func (g *Game) CanPlayerHit() bool {
+	return g.State == StatePlayerTurn && (!g.Player.DoubledDown || g.Rules.Pontoon) && !g.splitAcesLocked()
+}
+
+// splitAcesLocked 当前手牌是否为只补一张的分A（只能停牌或再分A）
+func (g *Game) splitAcesLocked() bool {
+	hand := g.Player.PlayerHand
+	return hand.FromSplit && hand.Hand.Cards[0].IsAce() && !g.Rules.HitSplitAces
+}
+
+// CanPlayerStand 玩家当前手牌是否可以停牌
+func (g *Game) CanPlayerStand() bool {
+	return g.State == StatePlayerTurn && g.Rules.CanStick(g.Player.Hand)
+}
+
+// CanPlayerSurrender 玩家是否可以投降：后投降只能在未分牌的前两张牌时，加倍救援只能在加倍后未爆牌时
+func (g *Game) CanPlayerSurrender() bool {
+	if g.State != StatePlayerTurn || g.Player.Surrendered {
+		return false
+	}
+	if g.Player.DoubledDown {
+		return g.Rules.DoubleDownRescue && !g.Player.Hand.IsBust()
+	}
+	return g.Rules.LateSurrender && len(g.Player.Hand.Cards) == 2 && !g.Player.FromSplit
+}
+
+// PlayerSurrender 玩家投降，结算时退回一半注码（加倍救援时退回加倍部分）
+func (g *Game) PlayerSurrender() error {
+	if !g.CanPlayerSurrender() {
+		return errors.New("cannot surrender")
+	}
+
+	g.Player.Surrendered = true
+	// 欧式无底牌规则下仍需发出庄家底牌，庄家Blackjack时投降无效
+	return g.finishHand()
+}
+
+// CanPlayerDoubleDown 玩家当前手牌是否可以加倍
+func (g *Game) CanPlayerDoubleDown() bool {
+	return g.State == StatePlayerTurn && !g.Player.DoubledDown && g.canDoubleHand() && g.MaxDoubleAmount() > 0
+}
+
+// canDoubleHand 当前手牌是否满足加倍规则（分牌后的手牌须允许DAS，只补一张的分A不能加倍，Pontoon要牌后不能再买牌）
+func (g *Game) canDoubleHand() bool {
+	if (g.Player.FromSplit && !g.Rules.DoubleAfterSplit) || g.splitAcesLocked() {
+		return false
+	}
+	return g.Rules.CanDouble(g.Player.Hand) && !(g.Rules.Pontoon && g.Player.Twisted)
+}
+
+// MaxDoubleAmount 加倍最多可追加的金额：免费加倍或筹码足够时为原注，
+// 允许少加倍时为按筹码面额取整后的剩余筹码，否则为0
+func (g *Game) MaxDoubleAmount() int {
+	if g.Rules.IsFreeDouble(g.Player.Hand) || g.Player.Chips >= g.Player.Bet {
+		return g.Player.Bet
+	}
+	if !g.Rules.DoubleForLess {
+		return 0
+	}
+
+	amount := g.Player.Chips
+	if g.Rules.ChipDenomination > 1 {
+		amount -= amount % g.Rules.ChipDenomination
+	}
+	return amount
 }
 
-// PlayerDoubleDown 玩家加倍
-func (g *Game) PlayerDoubleDown() (Card, error) {
+// PlayerDoubleDown 玩家加倍，amount为追加的金额
+func (g *Game) PlayerDoubleDown(amount int) (Card, error) {
 	if g.State != StatePlayerTurn {
 		return Card{}, errors.New("not player's turn")
 	}
 
-	if !g.Player.CanDoubleDown() {
-		return Card{}, errors.New("cannot double down")
+	if !g.canDoubleHand() {
+		return Card{}, errors.New("cannot double down on this hand")
+	}
+
+	if err := g.Rules.ValidateDouble(amount, g.Player.Bet); err != nil {
+		return Card{}, err
 	}
 
-	if !g.Player.DoubleBet() {
-		return Card{}, errors.New("cannot double down")
+	if g.Rules.IsFreeDouble(g.Player.Hand) && amount == g.Player.Bet {
+		g.Player.FreeDoubleBet(amount)
+	} else if !g.Player.DoubleBet(amount) {
+		return Card{}, fmt.Errorf("double amount %d exceeds available chips %d", amount, g.Player.Chips)
 	}
 
-	card, err := g.PlayerHit()
+	card, err := g.dealPlayerCard()
 	if err != nil {
 		return Card{}, err
 	}
 
-	return card, nil
+	// 加倍救援规则下未爆牌时仍可选择投降，Pontoon买牌后可以继续要牌
+	if (g.CanPlayerSurrender() || g.Rules.Pontoon) && !g.Player.Hand.IsBust() && !g.Rules.IsAutoWin(g.Player.Hand) {
+		return card, nil
+	}
+	return card, g.finishHand()
+}
+
+// CanPlayerSplit 玩家当前手牌是否可以分牌（规则允许时才能再分A，免费分牌无需筹码）
+func (g *Game) CanPlayerSplit() bool {
+	hand := g.Player.PlayerHand
+	if g.State != StatePlayerTurn || hand.DoubledDown || !g.Rules.CanSplit(hand.Hand) {
+		return false
+	}
+	if g.Player.OriginHands(hand.Origin) >= MaxSplitHands || (hand.FromSplit && hand.Hand.Cards[0].IsAce() && !g.Rules.ResplitAces) {
+		return false
+	}
+	return g.Rules.IsFreeSplit(hand.Hand) || g.Player.CanBet(hand.Bet)
+}
+
+// PlayerSplit 玩家分牌，先补第一手的第二张牌
+func (g *Game) PlayerSplit() error {
+	if !g.CanPlayerSplit() {
+		return errors.New("cannot split this hand")
+	}
+
+	if !g.Player.SplitHand(g.Rules.IsFreeSplit(g.Player.Hand)) {
+		return fmt.Errorf("split bet %d exceeds available chips %d", g.Player.Bet, g.Player.Chips)
+	}
+
+	return g.startHand()
 }
 
 // DealerTurn 庄家回合
@@ -144,15 +432,24 @@ func (g *Game) DealerTurn() error {
 		return errors.New("not dealer's turn")
 	}
 
-	// 如果玩家爆牌或任一方有Blackjack，庄家不需要额外要牌
-	if g.Player.Hand.IsBust() || g.Player.Hand.IsBlackjack() || g.Dealer.Hand.IsBlackjack() {
+	// 欧式无底牌规则下玩家行动结束后才发庄家第二张牌
+	if len(g.Dealer.Hand.Cards) < 2 {
+		card, err := g.dealCard()
+		if err != nil {
+			return err
+		}
+		g.Dealer.Hand.AddCard(card)
+	}
+
+	// 如果庄家Blackjack，或玩家每手牌都已爆牌、投降、自动获胜或为Blackjack，庄家不需要额外要牌
+	if g.Dealer.Hand.IsBlackjack() || !g.hasLiveHand() {
 		g.State = StateGameOver
 		return nil
 	}
 
 	// 庄家按规则要牌
-	for g.Dealer.Hand.Value() < 17 {
-		card, err := g.Deck.Deal()
+	for g.Rules.DealerShouldHit(g.Dealer.Hand) {
+		card, err := g.dealCard()
 		if err != nil {
 			return err
 		}
@@ -163,12 +460,52 @@ func (g *Game) DealerTurn() error {
 	return nil
 }
 
-// EvaluateResult 评估游戏结果
+// hasLiveHand 玩家是否还有需要与庄家比点数的手牌
+func (g *Game) hasLiveHand() bool {
+	for _, hand := range g.Player.Hands {
+		if !hand.Hand.IsBust() && !hand.Surrendered && !hand.IsBlackjack() && !g.Rules.IsAutoWin(hand.Hand) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateResult 评估游戏结果，多手牌时逐手结算
 func (g *Game) EvaluateResult() *GameResult {
 	if g.State != StateGameOver {
 		return nil
 	}
 
+	results := make([]*GameResult, 0, len(g.Player.Hands))
+	for _, hand := range g.Player.Hands {
+		g.Player.PlayerHand = hand
+		results = append(results, g.evaluateHand())
+	}
+	g.Player.PlayerHand = g.Player.Hands[0]
+	g.State = StateWaitingToBet
+
+	// 连续洗牌机：结算后本局的牌随机插回牌靴
+	if g.Rules.ContinuousShuffle {
+		g.Deck.InsertRandomly(g.GetUsedCards()...)
+	}
+
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	result := &GameResult{
+		ResultType: results[0].ResultType,
+		Hands:      results,
+	}
+	for _, hand := range results {
+		result.BetAmount += hand.BetAmount
+		result.IsDoubled = result.IsDoubled || hand.IsDoubled
+	}
+	return result
+}
+
+// evaluateHand 结算玩家当前手牌
+func (g *Game) evaluateHand() *GameResult {
 	result := &GameResult{
 		BetAmount: g.Player.Bet,
 		IsDoubled: g.Player.DoubledDown,
@@ -176,7 +513,7 @@ func (g *Game) EvaluateResult() *GameResult {
 
 	playerValue := g.Player.Hand.Value()
 	dealerValue := g.Dealer.Hand.Value()
-	playerBlackjack := g.Player.Hand.IsBlackjack()
+	playerBlackjack := g.Player.IsBlackjack()
 	dealerBlackjack := g.Dealer.Hand.IsBlackjack()
 
 	// 评估逻辑
@@ -184,10 +521,17 @@ func (g *Game) EvaluateResult() *GameResult {
 	case g.Player.Hand.IsBust():
 		result.ResultType = PlayerBust
 		g.Player.LoseBet()
-	case g.Dealer.Hand.IsBust():
-		result.ResultType = DealerBust
-		g.Player.WinBet(1.0)
-	case playerBlackjack && dealerBlackjack:
+	case g.Player.Surrendered && !dealerBlackjack:
+		result.ResultType = PlayerSurrender
+		g.Player.SurrenderBet()
+	case dealerBlackjack && g.Rules.Pontoon:
+		// 庄家Pontoon胜过玩家所有手牌，包括玩家Pontoon
+		result.ResultType = DealerPontoon
+		g.Player.LoseBet()
+	case playerBlackjack && g.Rules.Pontoon:
+		result.ResultType = PlayerPontoon
+		g.Player.WinBet(g.Rules.BlackjackPayout)
+	case playerBlackjack && dealerBlackjack && !g.Rules.BlackjackBeatsDealerBlackjack():
 		result.ResultType = Push
 		g.Player.PushBet()
 	case playerBlackjack:
@@ -195,15 +539,31 @@ func (g *Game) EvaluateResult() *GameResult {
 		if g.Player.DoubledDown {
 			g.Player.WinBet(1.0)
 		} else {
-			g.Player.WinBet(1.5)
+			g.Player.WinBet(g.Rules.BlackjackPayout)
 		}
 	case dealerBlackjack:
 		result.ResultType = DealerBlackjack
-		g.Player.LoseBet()
+		if g.Rules.HoleCard == HoleCardENHCOBO {
+			g.Player.LoseOriginalBet()
+		} else {
+			g.Player.LoseBet()
+		}
+	case g.Rules.IsCharlie(g.Player.Hand) && g.Rules.Pontoon:
+		result.ResultType = PlayerFiveCardTrick
+		g.Player.WinBet(FiveCardTrickPayout)
+	case g.Rules.IsCharlie(g.Player.Hand):
+		g.settleWin(result, PlayerCharlie)
+	case g.Rules.IsAutoWin(g.Player.Hand):
+		g.settleWin(result, PlayerTwentyOne)
+	case g.Rules.IsDealer22Push(g.Dealer.Hand):
+		result.ResultType = Dealer22Push
+		g.Player.PushBet()
+	case g.Dealer.Hand.IsBust():
+		g.settleWin(result, DealerBust)
 	case playerValue > dealerValue:
-		result.ResultType = PlayerWin
-		g.Player.WinBet(1.0)
-	case playerValue < dealerValue:
+		g.settleWin(result, PlayerWin)
+	case playerValue < dealerValue || g.Rules.DealerWinsTies():
+		// 双明牌与Pontoon规则下平局庄家赢
 		result.ResultType = DealerWin
 		g.Player.LoseBet()
 	default:
@@ -211,20 +571,120 @@ func (g *Game) EvaluateResult() *GameResult {
 		g.Player.PushBet()
 	}
 
-	g.State = StateWaitingToBet
 	return result
 }
 
-// IsGameOver 检查游戏是否结束
+// settleWin 结算玩家获胜，西班牙21点奖励牌型按奖励赔率赔付
+func (g *Game) settleWin(result *GameResult, resultType ResultType) {
+	payout := g.Rules.TwentyOneBonus(g.Player.Hand, g.Player.DoubledDown)
+	if payout > 1 {
+		resultType = PlayerBonus
+	}
+	result.ResultType = resultType
+	g.Player.WinBet(payout)
+}
+
+// IsGameOver 检查游戏是否结束（筹码不足每手牌的最低下注）
 func (g *Game) IsGameOver() bool {
-	return !g.Player.HasChips()
+	return !g.Player.HasChips() || g.Player.Chips < g.Rules.MinBet*g.Rules.HandsPerRound()
 }
 
-// ensureDeckSize 确保牌堆足够
-func (g *Game) ensureDeckSize() {
-	if len(g.Deck.Cards) < 10 {
-		g.Deck = NewDeck()
+// ensureDeckSize 确保牌堆足够（每副牌至少保留CutCardReserve张），连续洗牌机每局回收弃牌，牌靴不会耗尽
+func (g *Game) ensureDeckSize() error {
+	if len(g.Deck.Cards) < CutCardReserve*g.Rules.DeckCount || (g.Rules.ProvablyFair && g.Fair == nil) {
+		return g.reshuffle(nil)
 	}
+	return nil
+}
+
+// reshuffle 换上新洗好的牌靴：弃牌架清空，按规则烧掉第一张牌
+// excluded为局中牌靴发空时留在桌上的牌，不洗进新牌靴
+func (g *Game) reshuffle(excluded []Card) error {
+	deck, err := g.shuffledShoe(excluded)
+	if err != nil {
+		return err
+	}
+
+	g.Deck = deck
+	g.Discards.Clear()
+	if !g.Rules.BurnCard {
+		return nil
+	}
+	if card, err := g.Deck.Deal(); err == nil {
+		g.Discards.Burn(card)
+	}
+	return nil
+}
+
+// shuffledShoe 按规则洗出新牌靴：可验证公平模式先公开上一个牌靴，再生成带承诺的新牌靴
+func (g *Game) shuffledShoe(excluded []Card) (*Deck, error) {
+	switch {
+	case g.Rules.ProvablyFair:
+		return g.nextFairShoe(excluded)
+	case g.Deck == nil:
+		return g.Rules.NewShoe(), nil
+	case len(excluded) > 0:
+		deck := g.Rules.newShoe(g.Deck.random())
+		for _, card := range excluded {
+			deck.Remove(card)
+		}
+		return deck, nil
+	default:
+		return g.Rules.Reshuffle(g.Deck), nil
+	}
+}
+
+// nextFairShoe 公开上一个牌靴，用已公布承诺的服务器种子与玩家的客户端种子洗出新牌靴，
+// 同时为再下一个牌靴生成承诺
+func (g *Game) nextFairShoe(excluded []Card) (*Deck, error) {
+	if g.FairNext == nil {
+		var err error
+		if g.FairNext, err = NewFairCommitment(g.Rules, len(g.FairHistory)+1); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("the commitment for the next provably fair shoe was just published, set a client seed first")
+	}
+	next, err := NewFairCommitment(g.Rules, g.FairNext.Number+1)
+	if err != nil {
+		return nil, err
+	}
+	deck, err := g.FairNext.Shuffle(g.clientSeed, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	g.RevealFairShoe()
+	g.Fair, g.FairNext = g.FairNext, next
+	return deck, nil
+}
+
+// SetClientSeed 设置可验证公平模式的客户端种子，从下一个牌靴开始生效
+func (g *Game) SetClientSeed(seed string) error {
+	if !g.Rules.ProvablyFair {
+		return errors.New("client seeds are only used by provably fair shoes")
+	}
+	if seed == "" {
+		return errors.New("client seed cannot be empty")
+	}
+	g.clientSeed = seed
+	return nil
+}
+
+// ClientSeed 目前设置的客户端种子，还没设置时为空
+func (g *Game) ClientSeed() string {
+	return g.clientSeed
+}
+
+// RevealFairShoe 结束当前可验证公平牌靴并公开服务器种子，下一局开始前换新牌靴
+func (g *Game) RevealFairShoe() *FairShoe {
+	fair := g.Fair
+	if fair == nil {
+		return nil
+	}
+	fair.Reveal(g.Deck)
+	g.FairHistory = append(g.FairHistory, fair)
+	g.Fair = nil
+	return fair
 }
 
 // generateGameID 生成游戏ID
@@ -232,15 +692,49 @@ func generateGameID() string {
 	return uuid.New().String()
 }
 
-// GetRemainingCards 获取剩余卡牌（用于概率计算）
+// GetRemainingCards 获取玩家看不到的剩余卡牌（用于概率计算）：牌靴中的牌加上烧掉的暗牌
 func (g *Game) GetRemainingCards() []Card {
-	return g.Deck.Cards
+	if len(g.Discards.Burned) == 0 {
+		return g.Deck.Cards
+	}
+	return append(slices.Clone(g.Deck.Cards), g.Discards.Burned...)
+}
+
+// GetSeenCards 获取本牌靴玩家已看到的卡牌：弃牌架上亮过的牌加上桌上的明牌
+func (g *Game) GetSeenCards() []Card {
+	seen := slices.Clone(g.Discards.Cards)
+	for _, hand := range g.Player.Hands {
+		seen = append(seen, hand.Hand.Cards...)
+	}
+
+	return append(seen, g.Dealer.Hand.Cards[:g.dealerShown()]...)
+}
+
+// GetUnseenCards 获取本牌靴玩家还没看到的卡牌：牌靴余牌、烧掉的牌与庄家还没翻开的牌
+func (g *Game) GetUnseenCards() []Card {
+	unseen := slices.Clone(g.GetRemainingCards())
+	return append(unseen, g.Dealer.Hand.Cards[g.dealerShown():]...)
+}
+
+// dealerShown 庄家已亮出的牌数：玩家回合庄家底牌还没翻开，Pontoon两张都是暗牌，其余玩法只亮第一张
+func (g *Game) dealerShown() int {
+	cards := len(g.Dealer.Hand.Cards)
+	switch {
+	case g.State != StatePlayerTurn || g.Rules.DoubleExposure:
+		return cards
+	case g.Rules.Pontoon:
+		return 0
+	default:
+		return min(1, cards)
+	}
 }
 
 // GetUsedCards 获取已使用的卡牌（玩家和庄家手牌）
 func (g *Game) GetUsedCards() []Card {
 	used := make([]Card, 0)
-	used = append(used, g.Player.Hand.Cards...)
+	for _, hand := range g.Player.Hands {
+		used = append(used, hand.Hand.Cards...)
+	}
 	used = append(used, g.Dealer.Hand.Cards...)
 	return used
 }
@@ -1,23 +1,62 @@
 package entities
 
+import "slices"
+
+// PlayerHand 玩家的一手牌及其注码（分牌或换牌玩法中玩家有多手牌）
+type PlayerHand struct {
+	Hand         *Hand
+	Bet          int  // 当前下注金额（含免费注码）
+	FreeBet      int  // 其中由庄家出资的免费注码
+	DoubledDown  bool // 是否已经加倍
+	DoubleAmount int  // 加倍追加的金额
+	FreeDouble   bool // 是否为免费加倍
+	Surrendered  bool // 是否已经投降
+	FromSplit    bool // 是否由分牌得到
+	Origin       int  // 由第几手初始牌分出（换牌玩法第二手为1），分牌上限按初始手牌分别计算
+	SplitBet     bool // 注码是否由分牌追加
+	Switched     bool // 是否与另一手交换过第二张牌
+	Twisted      bool // 是否已经要过牌（Pontoon要牌后不能再买牌）
+	Finished     bool // 是否已经完成行动
+}
+
+// newPlayerHand 创建指定注码的空手牌
+func newPlayerHand(bet int) *PlayerHand {
+	return &PlayerHand{
+		Hand: NewHand(),
+		Bet:  bet,
+	}
+}
+
+// IsBlackjack 是否为Blackjack，分牌或换牌后的A+10只算21点
+func (h *PlayerHand) IsBlackjack() bool {
+	return !h.FromSplit && !h.Switched && h.Hand.IsBlackjack()
+}
+
+// paidBet 玩家自己出资的注码
+func (h *PlayerHand) paidBet() int {
+	return h.Bet - h.FreeBet
+}
+
 // Player 玩家结构
 type Player struct {
 	Name         string
-	Hand         *Hand
-	InitialChips int  // 初始筹码
-	Chips        int  // 玩家筹码总数
-	Bet          int  // 当前下注金额
-	DoubledDown  bool // 是否已经加倍
+	*PlayerHand                      // 当前行动的手牌
+	Hands        []*PlayerHand       // 本局所有手牌
+	InitialChips int                 // 初始筹码
+	Chips        int                 // 玩家筹码总数
+	LastBet      int                 // 上一次下注金额
+	SideBets     map[SideBetType]int // 本局边注金额
 }
 
 // NewPlayer 创建新玩家
 func NewPlayer(name string, initialChips int) *Player {
+	hand := newPlayerHand(0)
 	return &Player{
 		Name:         name,
-		Hand:         NewHand(),
+		PlayerHand:   hand,
+		Hands:        []*PlayerHand{hand},
 		InitialChips: initialChips,
 		Chips:        initialChips,
-		Bet:          0,
 	}
 }
 
@@ -32,14 +71,41 @@ func (p *Player) PlaceBet(amount int) bool {
 		return false
 	}
 	p.Bet = amount
+	p.LastBet = amount
 	p.Chips -= amount
 	return true
 }
 
-// WinBet 赢得下注（包括本金）
+// AddHand 以相同注码再开一手牌（换牌玩法）
+func (p *Player) AddHand(bet int) bool {
+	if !p.CanBet(bet) {
+		return false
+	}
+	p.Chips -= bet
+	hand := newPlayerHand(bet)
+	hand.Origin = len(p.Hands)
+	p.Hands = append(p.Hands, hand)
+	return true
+}
+
+// PlaceSideBet 下边注，同类边注再次下注时替换原金额
+func (p *Player) PlaceSideBet(betType SideBetType, amount int) bool {
+	previous := p.SideBets[betType]
+	if amount <= 0 || p.Chips < amount-previous {
+		return false
+	}
+	if p.SideBets == nil {
+		p.SideBets = make(map[SideBetType]int)
+	}
+	p.Chips += previous - amount
+	p.SideBets[betType] = amount
+	return true
+}
+
+// WinBet 赢得下注（返还自己出资的本金，免费注码也按赔率赢钱）
 func (p *Player) WinBet(multiplier float64) {
 	winnings := int(float64(p.Bet) * multiplier)
-	p.Chips += p.Bet + winnings // 返还本金 + 奖金
+	p.Chips += p.paidBet() + winnings // 返还本金 + 奖金
 	p.Bet = 0
 }
 
@@ -48,9 +114,31 @@ func (p *Player) LoseBet() {
 	p.Bet = 0
 }
 
-// PushBet 平局，返还下注
+// LoseOriginalBet 只输掉原始下注，加倍与分牌追加的部分退回
+func (p *Player) LoseOriginalBet() {
+	switch {
+	case p.SplitBet:
+		p.Chips += p.paidBet()
+	case p.DoubledDown && !p.FreeDouble:
+		p.Chips += p.DoubleAmount
+	}
+	p.Bet = 0
+}
+
+// SurrenderBet 投降：退回一半注码，加倍后投降（加倍救援）退回加倍部分
+func (p *Player) SurrenderBet() {
+	switch {
+	case p.DoubledDown && !p.FreeDouble:
+		p.Chips += p.DoubleAmount
+	case !p.DoubledDown:
+		p.Chips += p.paidBet() / 2
+	}
+	p.Bet = 0
+}
+
+// PushBet 平局，返还自己出资的注码
 func (p *Player) PushBet() {
-	p.Chips += p.Bet // 返还本金
+	p.Chips += p.paidBet() // 返还本金
 	p.Bet = 0
 }
 
@@ -59,26 +147,85 @@ func (p *Player) HasChips() bool {
 	return p.Chips > 0
 }
 
-// DoubleBet 加倍下注
-func (p *Player) DoubleBet() bool {
-	if !p.CanBet(p.Bet) {
+// DoubleBet 加倍下注，amount为追加的金额（全额加倍时等于原注）
+func (p *Player) DoubleBet(amount int) bool {
+	if !p.CanDoubleDown(amount) {
+		return false
+	}
+	p.Chips -= amount // 扣除额外的下注金额
+	p.Bet += amount
+	p.DoubleAmount = amount
+	p.DoubledDown = true
+	return true
+}
+
+// FreeDoubleBet 免费加倍，追加的注码由庄家出资
+func (p *Player) FreeDoubleBet(amount int) bool {
+	if p.DoubledDown || amount <= 0 {
 		return false
 	}
-	p.Chips -= p.Bet // 扣除额外的下注金额
-	p.Bet *= 2       // 下注金额翻倍
+	p.Bet += amount
+	p.FreeBet += amount
+	p.DoubleAmount = amount
 	p.DoubledDown = true
+	p.FreeDouble = true
 	return true
 }
 
-// CanDoubleDown 检查是否可以加倍
-func (p *Player) CanDoubleDown() bool {
-	// 只有在前两张牌且有足够筹码时才能加倍
-	return len(p.Hand.Cards) == 2 && !p.DoubledDown && p.CanBet(p.Bet)
+// CanDoubleDown 检查是否还没加倍且有足够筹码追加指定金额
+func (p *Player) CanDoubleDown(amount int) bool {
+	return !p.DoubledDown && p.CanBet(amount)
+}
+
+// SplitHand 将当前手牌的两张牌拆成两手，新手牌注码与原注相同，免费分牌时由庄家出资
+func (p *Player) SplitHand(free bool) bool {
+	if len(p.Hand.Cards) != 2 || (!free && !p.CanBet(p.Bet)) {
+		return false
+	}
+
+	split := newPlayerHand(p.Bet)
+	split.Hand.AddCard(p.Hand.Cards[1])
+	split.FromSplit = true
+	split.SplitBet = true
+	split.Origin = p.Origin
+	if free {
+		split.FreeBet = p.Bet
+	} else {
+		p.Chips -= p.Bet
+	}
+
+	p.Hand.Cards = p.Hand.Cards[:1]
+	p.FromSplit = true
+	index := slices.Index(p.Hands, p.PlayerHand)
+	p.Hands = slices.Insert(p.Hands, index+1, split)
+	return true
+}
+
+// OriginHands 由指定初始手牌分出的手牌数（含初始手牌本身）
+func (p *Player) OriginHands(origin int) int {
+	count := 0
+	for _, hand := range p.Hands {
+		if hand.Origin == origin {
+			count++
+		}
+	}
+	return count
+}
+
+// NextHand 切换到下一手未完成的牌，没有时返回false
+func (p *Player) NextHand() bool {
+	for _, hand := range p.Hands {
+		if !hand.Finished {
+			p.PlayerHand = hand
+			return true
+		}
+	}
+	return false
 }
 
 // ResetRound 重置回合状态
 func (p *Player) ResetRound() {
-	p.Hand = NewHand()
-	p.Bet = 0
-	p.DoubledDown = false
+	p.PlayerHand = newPlayerHand(0)
+	p.Hands = []*PlayerHand{p.PlayerHand}
+	p.SideBets = nil
 }
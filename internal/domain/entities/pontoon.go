@@ -0,0 +1,30 @@
+package entities
+
+// Pontoon 规则常量
+const (
+	PontoonMinStick     = 15  // 停牌的最低点数
+	FiveCardTrickPayout = 2.0 // 五张牌型（五张未爆牌）的赔率
+)
+
+// PontoonRules 英式Pontoon的常见规则：单副牌，庄家两张牌都是暗牌，Pontoon(A+10)赔2:1，
+// 五张牌型赔2:1且只输给庄家Pontoon，平局庄家赢，15点以上才能停牌，买牌可加注不超过原注
+func PontoonRules() Rules {
+	rules := DefaultRules()
+	rules.Variant = VariantPontoon
+	rules.BlackjackPayout = 2
+	rules.CharlieCards = 5
+	rules.DoubleAfterSplit = true
+	rules.DoubleForLess = true
+	rules.Pontoon = true
+	return rules
+}
+
+// CanStick 手牌点数是否允许停牌（Pontoon须达到15点）
+func (r Rules) CanStick(hand *Hand) bool {
+	return !r.Pontoon || hand.Value() >= PontoonMinStick
+}
+
+// CanBuy Pontoon买牌：五张牌之前、未到21点时可加注再要一张牌
+func (r Rules) CanBuy(hand *Hand) bool {
+	return r.Pontoon && len(hand.Cards) >= 2 && len(hand.Cards) < r.CharlieCards && hand.Value() < 21
+}
@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// 服务器种子的字节数
+const serverSeedSize = 32
+
+// FairShoe 可验证公平的牌靴记录：服务器种子与基础牌序的承诺在玩家确定客户端种子之前公布，
+// 实际牌序由服务器种子与客户端种子共同决定，牌靴结束时公开服务器种子供玩家验证
+type FairShoe struct {
+	Number      int      `json:"number"`                // 牌靴序号
+	ClientSeed  string   `json:"client_seed"`           // 玩家看到承诺后提供的种子
+	Commitment  string   `json:"commitment"`            // SHA-256(服务器种子, 基础牌序)，玩家确定客户端种子前公布
+	ServerSeed  string   `json:"server_seed,omitempty"` // 牌靴结束时才公开
+	DeckCount   int      `json:"deck_count"`
+	SpanishDeck bool     `json:"spanish_deck"`
+	Excluded    []string `json:"excluded,omitempty"` // 局中换牌靴时留在桌上、没有洗进牌靴的牌
+	Dealt       []string `json:"dealt,omitempty"`    // 牌靴结束时已发出的牌（含烧牌），按发牌顺序
+
+	serverSeed []byte
+	base       []Card
+	order      []Card
+}
+
+// NewFairCommitment 为第number个牌靴生成服务器种子并计算承诺，返回的牌靴还没有客户端种子与牌序
+// 承诺须在玩家确定这个牌靴的客户端种子之前公布，服务器不能再按客户端种子挑选有利的服务器种子
+func NewFairCommitment(rules Rules, number int) (*FairShoe, error) {
+	serverSeed := make([]byte, serverSeedSize)
+	if _, err := rand.Read(serverSeed); err != nil {
+		return nil, fmt.Errorf("generate server seed: %w", err)
+	}
+
+	shoe := &FairShoe{
+		Number:      number,
+		DeckCount:   max(rules.DeckCount, 1),
+		SpanishDeck: rules.SpanishDeck,
+		serverSeed:  serverSeed,
+	}
+	shoe.base = fairBaseOrder(serverSeed, shoe.cards())
+	shoe.Commitment = fairCommitment(serverSeed, shoe.base)
+	return shoe, nil
+}
+
+// Shuffle 用玩家的客户端种子洗出已公布承诺的牌靴，excluded为不洗进牌靴的牌（从基础牌序中去掉）
+func (f *FairShoe) Shuffle(clientSeed string, excluded []Card) (*Deck, error) {
+	if clientSeed == "" {
+		return nil, errors.New("provably fair shoes need a client seed")
+	}
+
+	f.ClientSeed = clientSeed
+	f.Excluded = cardNames(excluded)
+	f.order = fairFinalOrder(f.serverSeed, clientSeed, f.Number, withoutCards(f.base, excluded))
+	return &Deck{Cards: slices.Clone(f.order), order: slices.Clone(f.order)}, nil
+}
+
+// Reveal 牌靴结束：公开服务器种子并记录已发出的牌
+func (f *FairShoe) Reveal(deck *Deck) {
+	f.ServerSeed = hex.EncodeToString(f.serverSeed)
+	dealt := max(len(f.order)-len(deck.Cards), 0)
+	f.Dealt = cardNames(f.order[:dealt])
+}
+
+// VerifyFairShoe 用公开的服务器种子重现牌靴：检查承诺与基础牌序一致，且已发出的牌与重现的牌序一致
+func VerifyFairShoe(record *FairShoe) ([]Card, error) {
+	serverSeed, err := hex.DecodeString(record.ServerSeed)
+	if err != nil || len(serverSeed) != serverSeedSize {
+		return nil, errors.New("server seed is missing or malformed")
+	}
+
+	var excluded []Card
+	for _, name := range record.Excluded {
+		card, err := ParseCard(name)
+		if err != nil {
+			return nil, err
+		}
+		excluded = append(excluded, card)
+	}
+
+	base := fairBaseOrder(serverSeed, record.cards())
+	if fairCommitment(serverSeed, base) != record.Commitment {
+		return nil, errors.New("commitment does not match the server seed")
+	}
+
+	order := fairFinalOrder(serverSeed, record.ClientSeed, record.Number, withoutCards(base, excluded))
+	if len(record.Dealt) > len(order) {
+		return nil, fmt.Errorf("%d cards dealt from a %d-card shoe", len(record.Dealt), len(order))
+	}
+	for i, name := range record.Dealt {
+		if order[i].String() != name {
+			return nil, fmt.Errorf("card %d was %s, but the shoe order has %s", i+1, name, order[i])
+		}
+	}
+	return order, nil
+}
+
+// cards 按出厂顺序排列的完整牌靴
+func (f *FairShoe) cards() []Card {
+	if f.SpanishDeck {
+		return newOrderedSpanishShoe(f.DeckCount).Cards
+	}
+	return newOrderedShoe(f.DeckCount).Cards
+}
+
+// withoutCards 按原顺序去掉excluded中的牌（同样的牌每张只去掉一次）
+func withoutCards(cards, excluded []Card) []Card {
+	deck := &Deck{Cards: slices.Clone(cards)}
+	for _, card := range excluded {
+		deck.Remove(card)
+	}
+	return deck.Cards
+}
+
+// fairBaseOrder 只由服务器种子决定的完整牌靴基础牌序
+func fairBaseOrder(serverSeed []byte, cards []Card) []Card {
+	return fairShuffle(sha256.Sum256(serverSeed), cards)
+}
+
+// fairCommitment 服务器种子与基础牌序的承诺
+func fairCommitment(serverSeed []byte, base []Card) string {
+	digest := sha256.Sum256([]byte(hex.EncodeToString(serverSeed) + ":" + strings.Join(cardNames(base), ",")))
+	return hex.EncodeToString(digest[:])
+}
+
+// fairFinalOrder 用HMAC-SHA256(服务器种子, 客户端种子:牌靴序号)再洗一次基础牌序
+func fairFinalOrder(serverSeed []byte, clientSeed string, number int, base []Card) []Card {
+	mac := hmac.New(sha256.New, serverSeed)
+	fmt.Fprintf(mac, "%s:%d", clientSeed, number)
+	var seed [32]byte
+	copy(seed[:], mac.Sum(nil))
+	return fairShuffle(seed, base)
+}
+
+// fairShuffle 以ChaCha8为随机源的Fisher–Yates洗牌；自行实现取数与交换，牌序不随Go版本变化
+func fairShuffle(seed [32]byte, cards []Card) []Card {
+	source := mathrand.NewChaCha8(seed)
+	shuffled := slices.Clone(cards)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := fairIntN(source, uint64(i+1))
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
+
+// fairIntN 取[0,n)的均匀整数，拒绝采样避免取模偏差
+func fairIntN(source *mathrand.ChaCha8, n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := source.Uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// cardNames 卡牌的文字表示
+func cardNames(cards []Card) []string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.String()
+	}
+	return names
+}
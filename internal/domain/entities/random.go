@@ -0,0 +1,78 @@
+package entities
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RandomSource 洗牌随机源
+type RandomSource int
+
+const (
+	// RandomPCG is a PCG generator seeded from the clock, or from a fixed seed for reproducible simulations
+	RandomPCG RandomSource = iota
+	// RandomCrypto reads every draw from crypto/rand, so shoe orders cannot be predicted from the start time
+	RandomCrypto
+)
+
+// RandomSources 所有洗牌随机源
+var RandomSources = []RandomSource{RandomPCG, RandomCrypto}
+
+func (s RandomSource) String() string {
+	switch s {
+	case RandomPCG:
+		return "pcg"
+	case RandomCrypto:
+		return "crypto"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRandomSource 解析洗牌随机源名称
+func ParseRandomSource(name string) (RandomSource, error) {
+	for _, source := range RandomSources {
+		if source.String() == name {
+			return source, nil
+		}
+	}
+	return RandomPCG, fmt.Errorf("unknown random source %q", name)
+}
+
+// MarshalText 按名称编码
+func (s RandomSource) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText 按名称解码
+func (s *RandomSource) UnmarshalText(text []byte) error {
+	value, err := ParseRandomSource(string(text))
+	*s = value
+	return err
+}
+
+// NewRand 按随机源创建随机数生成器；PCG的seed为0时使用当前时间，crypto忽略seed
+func (s RandomSource) NewRand(seed uint64) *rand.Rand {
+	if s == RandomCrypto {
+		return rand.New(cryptoSource{})
+	}
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return rand.New(rand.NewPCG(seed, seed>>32))
+}
+
+// cryptoSource 每次取数都读crypto/rand的随机源
+type cryptoSource struct{}
+
+// Uint64 读取8字节系统随机数；系统随机数不可用时无法安全洗牌，直接终止
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand unavailable: %v", err))
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,456 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// HoleCardRule 庄家底牌规则
+type HoleCardRule int
+
+const (
+	// HoleCardPeek deals the hole card up front and checks for blackjack before the player acts
+	HoleCardPeek HoleCardRule = iota
+	// HoleCardENHC deals no hole card until the player is done; a dealer blackjack takes all bets
+	HoleCardENHC
+	// HoleCardENHCOBO is ENHC where a dealer blackjack only takes the original bet
+	HoleCardENHCOBO
+)
+
+// HoleCardRules 所有庄家底牌规则
+var HoleCardRules = []HoleCardRule{HoleCardPeek, HoleCardENHC, HoleCardENHCOBO}
+
+func (r HoleCardRule) String() string {
+	switch r {
+	case HoleCardPeek:
+		return "peek"
+	case HoleCardENHC:
+		return "enhc"
+	case HoleCardENHCOBO:
+		return "enhc-obo"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseHoleCardRule 解析庄家底牌规则名称
+func ParseHoleCardRule(name string) (HoleCardRule, error) {
+	for _, rule := range HoleCardRules {
+		if rule.String() == name {
+			return rule, nil
+		}
+	}
+	return HoleCardPeek, fmt.Errorf("unknown hole card rule %q", name)
+}
+
+// MarshalText 按名称编码，规则与场景文件中使用名称而不是数字
+func (r HoleCardRule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText 按名称解码
+func (r *HoleCardRule) UnmarshalText(text []byte) error {
+	value, err := ParseHoleCardRule(string(text))
+	*r = value
+	return err
+}
+
+// DoubleRestriction 加倍点数限制
+type DoubleRestriction int
+
+const (
+	// DoubleAnyTotal allows doubling on any total
+	DoubleAnyTotal DoubleRestriction = iota
+	// DoubleNineToEleven allows doubling only on totals of 9, 10 and 11
+	DoubleNineToEleven
+	// DoubleTenToEleven allows doubling only on totals of 10 and 11
+	DoubleTenToEleven
+)
+
+// DoubleRestrictions 所有加倍点数限制
+var DoubleRestrictions = []DoubleRestriction{DoubleAnyTotal, DoubleNineToEleven, DoubleTenToEleven}
+
+func (r DoubleRestriction) String() string {
+	switch r {
+	case DoubleAnyTotal:
+		return "any"
+	case DoubleNineToEleven:
+		return "9-11"
+	case DoubleTenToEleven:
+		return "10-11"
+	default:
+		return "unknown"
+	}
+}
+
+// Allows 指定点数是否允许加倍
+func (r DoubleRestriction) Allows(total int) bool {
+	switch r {
+	case DoubleNineToEleven:
+		return total >= 9 && total <= 11
+	case DoubleTenToEleven:
+		return total >= 10 && total <= 11
+	default:
+		return total < 21
+	}
+}
+
+// ParseDoubleRestriction 解析加倍点数限制名称
+func ParseDoubleRestriction(name string) (DoubleRestriction, error) {
+	for _, restriction := range DoubleRestrictions {
+		if restriction.String() == name {
+			return restriction, nil
+		}
+	}
+	return DoubleAnyTotal, fmt.Errorf("unknown double restriction %q", name)
+}
+
+// MarshalText 按名称编码
+func (r DoubleRestriction) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText 按名称解码
+func (r *DoubleRestriction) UnmarshalText(text []byte) error {
+	value, err := ParseDoubleRestriction(string(text))
+	*r = value
+	return err
+}
+
+// GameVariant 游戏变体
+type GameVariant int
+
+const (
+	// VariantStandard is classic blackjack
+	VariantStandard GameVariant = iota
+	// VariantSpanish21 is Spanish 21 played with 48-card decks
+	VariantSpanish21
+	// VariantSwitch is Blackjack Switch: two hands whose second cards may be swapped
+	VariantSwitch
+	// VariantFreeBet is Free Bet Blackjack with free doubles and free splits
+	VariantFreeBet
+	// VariantDoubleExposure is Double Exposure with both dealer cards dealt face up
+	VariantDoubleExposure
+	// VariantPontoon is British Pontoon with both dealer cards dealt face down
+	VariantPontoon
+)
+
+// GameVariants 所有游戏变体
+var GameVariants = []GameVariant{VariantStandard, VariantSpanish21, VariantSwitch, VariantFreeBet, VariantDoubleExposure, VariantPontoon}
+
+func (v GameVariant) String() string {
+	switch v {
+	case VariantStandard:
+		return "standard"
+	case VariantSpanish21:
+		return "spanish21"
+	case VariantSwitch:
+		return "switch"
+	case VariantFreeBet:
+		return "free-bet"
+	case VariantDoubleExposure:
+		return "double-exposure"
+	case VariantPontoon:
+		return "pontoon"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseGameVariant 解析游戏变体名称
+func ParseGameVariant(name string) (GameVariant, error) {
+	for _, variant := range GameVariants {
+		if variant.String() == name {
+			return variant, nil
+		}
+	}
+	return VariantStandard, fmt.Errorf("unknown game variant %q", name)
+}
+
+// MarshalText 按名称编码
+func (v GameVariant) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText 按名称解码
+func (v *GameVariant) UnmarshalText(text []byte) error {
+	value, err := ParseGameVariant(string(text))
+	*v = value
+	return err
+}
+
+// VariantRules 游戏变体的标准规则
+func VariantRules(variant GameVariant) Rules {
+	switch variant {
+	case VariantSpanish21:
+		return Spanish21Rules()
+	case VariantSwitch:
+		return BlackjackSwitchRules()
+	case VariantFreeBet:
+		return FreeBetRules()
+	case VariantDoubleExposure:
+		return DoubleExposureRules()
+	case VariantPontoon:
+		return PontoonRules()
+	default:
+		return DefaultRules()
+	}
+}
+
+// Rules 牌桌规则
+type Rules struct {
+	Variant             GameVariant       `json:"variant"`               // 游戏变体
+	DeckCount           int               `json:"deck_count"`            // 牌副数
+	BlackjackPayout     float64           `json:"blackjack_payout"`      // Blackjack赔率（3:2为1.5）
+	HoleCard            HoleCardRule      `json:"hole_card"`             // 庄家底牌规则
+	DealerHitsSoft17    bool              `json:"dealer_hits_soft_17"`   // 庄家软17要牌（H17）
+	DoubleRestriction   DoubleRestriction `json:"double_restriction"`    // 加倍点数限制
+	DoubleAfterSplit    bool              `json:"double_after_split"`    // 分牌后可加倍（DAS）
+	DoubleAnyCards      bool              `json:"double_any_cards"`      // 任意张数时可加倍
+	DoubleForLess       bool              `json:"double_for_less"`       // 可用少于原注的金额加倍
+	ResplitAces         bool              `json:"resplit_aces"`          // 分A后补到A可再分牌（RSA）
+	HitSplitAces        bool              `json:"hit_split_aces"`        // 分A后可继续要牌与加倍（默认只补一张）
+	CharlieCards        int               `json:"charlie_cards"`         // 拿到该张数未爆牌自动获胜（0为不启用）
+	Player21AlwaysWins  bool              `json:"player_21_always_wins"` // 玩家21点必胜（Blackjack也胜过庄家Blackjack）
+	SpanishDeck         bool              `json:"spanish_deck"`          // 使用去掉10点数字牌的西班牙牌
+	BonusPayouts        bool              `json:"bonus_payouts"`         // 西班牙21点奖励赔付
+	LateSurrender       bool              `json:"late_surrender"`        // 庄家检查底牌后可投降，输一半注码
+	DoubleDownRescue    bool              `json:"double_down_rescue"`    // 加倍后可投降，只输原始注码
+	SwitchHands         bool              `json:"switch_hands"`          // 每局两手牌，可交换两手的第二张牌
+	Dealer22Push        bool              `json:"dealer_22_push"`        // 庄家22点爆牌时未爆牌的玩家平局
+	FreeDoubles         bool              `json:"free_doubles"`          // 硬9-11点免费加倍
+	FreeSplits          bool              `json:"free_splits"`           // 10点牌以外的对子免费分牌
+	DoubleExposure      bool              `json:"double_exposure"`       // 庄家两张牌都明牌，平局庄家赢（玩家Blackjack除外）
+	Pontoon             bool              `json:"pontoon"`               // 英式Pontoon：庄家两张暗牌，平局庄家赢，15点以上才能停牌，加倍改为买牌
+	ContinuousShuffle   bool              `json:"continuous_shuffle"`    // 连续洗牌机：每局结算后弃牌随机插回牌靴
+	ShuffleModel        ShuffleModel      `json:"shuffle_model"`         // 换牌靴时的洗牌方式
+	ShuffleImperfection float64           `json:"shuffle_imperfection"`  // 人工洗牌的不完美程度[0,1)
+	BurnCard            bool              `json:"burn_card"`             // 每次洗牌后烧掉第一张牌（牌面朝下）
+	ProvablyFair        bool              `json:"provably_fair"`         // 可验证公平洗牌：承诺-公开，不使用连续洗牌机与人工洗牌方式
+	RandomSource        RandomSource      `json:"random_source"`         // 洗牌随机源
+	ShuffleSeed         uint64            `json:"shuffle_seed"`          // PCG随机源的种子，0为当前时间；固定种子可重现模拟
+	MinBet              int               `json:"min_bet"`               // 牌桌最低下注
+	MaxBet              int               `json:"max_bet"`               // 牌桌最高下注（0为不限）
+	ChipDenomination    int               `json:"chip_denomination"`     // 最小筹码面额，下注须为其整数倍
+}
+
+// DefaultRules 默认规则：单副牌，Blackjack 3:2，美式偷看底牌，下注10-500，最小筹码5
+func DefaultRules() Rules {
+	return Rules{
+		DeckCount:        1,
+		BlackjackPayout:  1.5,
+		HoleCard:         HoleCardPeek,
+		MinBet:           10,
+		MaxBet:           500,
+		ChipDenomination: 5,
+	}
+}
+
+// NewShoe 按规则的随机源与种子创建牌靴
+func (r Rules) NewShoe() *Deck {
+	return r.newShoe(r.RandomSource.NewRand(r.ShuffleSeed))
+}
+
+// newShoe 用指定的随机数生成器洗出新牌靴，人工洗牌方式从新牌的出厂顺序开始洗
+func (r Rules) newShoe(rng *rand.Rand) *Deck {
+	var deck *Deck
+	if r.SpanishDeck {
+		deck = newOrderedSpanishShoe(r.DeckCount)
+	} else {
+		deck = newOrderedShoe(r.DeckCount)
+	}
+
+	if r.ShuffleModel != ShuffleRandom {
+		return r.shuffleShoe(deck.Cards, 0, rng)
+	}
+	deck.rng = rng
+	deck.Shuffle()
+	return deck
+}
+
+// Reshuffle 用上一个牌靴的牌和随机数生成器重新洗牌：已发的牌按发牌顺序叠成弃牌，未发的余牌放在最后
+// 理想洗牌与牌序已被打乱（如连续洗牌机回收弃牌）的牌靴直接换新牌靴
+func (r Rules) Reshuffle(previous *Deck) *Deck {
+	dealt := len(previous.order) - len(previous.Cards)
+	if r.ShuffleModel == ShuffleRandom || dealt < 0 || !slices.Equal(previous.order[dealt:], previous.Cards) {
+		return r.newShoe(previous.random())
+	}
+	cards := slices.Concat(previous.order[:dealt], previous.Cards)
+	return r.shuffleShoe(cards, len(previous.Cards), previous.random())
+}
+
+// shuffleShoe 按规则的洗牌方式洗牌，stub为排在最后的余牌张数
+func (r Rules) shuffleShoe(cards []Card, stub int, rng *rand.Rand) *Deck {
+	shuffled := NewShuffler(r.ShuffleModel, r.ShuffleImperfection, rng).Shuffle(cards, stub)
+	return &Deck{Cards: shuffled, order: slices.Clone(shuffled), rng: rng}
+}
+
+// Validate 检查规则的取值范围，以及是否同时启用了互相冲突的游戏变体规则
+func (r Rules) Validate() error {
+	switch {
+	case r.DeckCount < 1:
+		return fmt.Errorf("deck count must be at least 1, got %d", r.DeckCount)
+	case r.BlackjackPayout <= 0:
+		return fmt.Errorf("blackjack payout must be positive, got %g", r.BlackjackPayout)
+	case r.CharlieCards < 0 || (r.CharlieCards > 0 && r.CharlieCards < 3):
+		return fmt.Errorf("charlie cards must be 0 (off) or at least 3, got %d", r.CharlieCards)
+	case r.ChipDenomination <= 0:
+		return fmt.Errorf("chip denomination must be positive, got %d", r.ChipDenomination)
+	case r.MinBet <= 0:
+		return fmt.Errorf("minimum bet must be positive, got %d", r.MinBet)
+	case r.MaxBet < 0 || (r.MaxBet > 0 && r.MaxBet < r.MinBet):
+		return fmt.Errorf("maximum bet %d must be 0 (no limit) or at least the minimum bet %d", r.MaxBet, r.MinBet)
+	case r.ShuffleImperfection < 0 || r.ShuffleImperfection >= 1:
+		return fmt.Errorf("shuffle imperfection must be in [0, 1), got %g", r.ShuffleImperfection)
+	case r.ContinuousShuffle && r.ProvablyFair:
+		// 可验证公平的牌序必须完全由种子决定，不能回收弃牌
+		return errors.New("continuous shuffling cannot be combined with provably fair shoes")
+	}
+
+	var variants []string
+	if r.SpanishDeck {
+		variants = append(variants, "spanish deck")
+	}
+	if r.SwitchHands {
+		variants = append(variants, "switch")
+	}
+	if r.FreeDoubles || r.FreeSplits {
+		variants = append(variants, "free bet")
+	}
+	if r.DoubleExposure {
+		variants = append(variants, "double exposure")
+	}
+	if r.Pontoon {
+		variants = append(variants, "pontoon")
+	}
+	if len(variants) > 1 {
+		return fmt.Errorf("conflicting variant rules: %s", strings.Join(variants, ", "))
+	}
+	return nil
+}
+
+// MaxSplitHands 每手初始牌分牌后最多的手牌数
+const MaxSplitHands = 4
+
+// HandsPerRound 每局玩家的手牌数（换牌玩法为两手）
+func (r Rules) HandsPerRound() int {
+	if r.SwitchHands {
+		return 2
+	}
+	return 1
+}
+
+// CanSplit 手牌是否为可分牌的对子
+func (r Rules) CanSplit(hand *Hand) bool {
+	return len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank
+}
+
+// IsFreeDouble 手牌是否可以免费加倍（两张牌的硬9-11点）
+func (r Rules) IsFreeDouble(hand *Hand) bool {
+	if !r.FreeDoubles || len(hand.Cards) != 2 || hand.IsSoft() {
+		return false
+	}
+	value := hand.Value()
+	return value >= 9 && value <= 11
+}
+
+// IsFreeSplit 手牌是否可以免费分牌（10点牌以外的对子）
+func (r Rules) IsFreeSplit(hand *Hand) bool {
+	return r.FreeSplits && r.CanSplit(hand) && hand.Cards[0].BaseValue() != 10
+}
+
+// BlackjackBeatsDealerBlackjack 玩家Blackjack是否胜过庄家Blackjack（21点必胜或双明牌规则）
+func (r Rules) BlackjackBeatsDealerBlackjack() bool {
+	return r.Player21AlwaysWins || r.DoubleExposure
+}
+
+// DealerWinsTies 平局是否算庄家赢（双明牌与Pontoon）
+func (r Rules) DealerWinsTies() bool {
+	return r.DoubleExposure || r.Pontoon
+}
+
+// IsDealer22Push 庄家22点是否与玩家平局
+func (r Rules) IsDealer22Push(dealer *Hand) bool {
+	return r.Dealer22Push && dealer.Value() == 22
+}
+
+// DealerShouldHit 庄家是否继续要牌：17点以下要牌，H17规则下软17也要牌
+func (r Rules) DealerShouldHit(hand *Hand) bool {
+	value := hand.Value()
+	return value < 17 || (r.DealerHitsSoft17 && value == 17 && hand.IsSoft())
+}
+
+// IsCharlie 手牌是否达到查理张数且未爆牌
+func (r Rules) IsCharlie(hand *Hand) bool {
+	return r.CharlieCards > 0 && len(hand.Cards) >= r.CharlieCards && !hand.IsBust()
+}
+
+// IsAutoWin 玩家手牌是否已自动获胜（查理或21点必胜），Blackjack另行结算
+func (r Rules) IsAutoWin(hand *Hand) bool {
+	return r.IsCharlie(hand) || (r.Player21AlwaysWins && hand.Value() == 21 && !hand.IsBlackjack())
+}
+
+// CanDouble 手牌是否满足加倍的张数与点数限制，Pontoon按买牌规则判断
+func (r Rules) CanDouble(hand *Hand) bool {
+	if r.Pontoon {
+		return r.CanBuy(hand)
+	}
+	if len(hand.Cards) < 2 || (len(hand.Cards) > 2 && !r.DoubleAnyCards) {
+		return false
+	}
+	return r.DoubleRestriction.Allows(hand.Value())
+}
+
+// ValidateDouble 检查加倍追加的金额：不超过原注，只有允许少加倍时才能低于原注
+func (r Rules) ValidateDouble(amount, bet int) error {
+	switch {
+	case amount <= 0:
+		return errors.New("double amount must be positive")
+	case amount > bet:
+		return fmt.Errorf("double amount %d exceeds the original bet of %d", amount, bet)
+	case amount == bet:
+		return nil
+	case !r.DoubleForLess:
+		return fmt.Errorf("double for less is not allowed, the double must be %d", bet)
+	case r.ChipDenomination > 1 && amount%r.ChipDenomination != 0:
+		return fmt.Errorf("double amount %d is not a multiple of the %d chip", amount, r.ChipDenomination)
+	}
+	return nil
+}
+
+// ValidateBet 检查下注金额是否符合牌桌限额与筹码面额
+func (r Rules) ValidateBet(amount int) error {
+	switch {
+	case amount <= 0:
+		return errors.New("bet must be positive")
+	case amount < r.MinBet:
+		return fmt.Errorf("bet %d is below the table minimum of %d", amount, r.MinBet)
+	case r.MaxBet > 0 && amount > r.MaxBet:
+		return fmt.Errorf("bet %d exceeds the table maximum of %d", amount, r.MaxBet)
+	case r.ChipDenomination > 1 && amount%r.ChipDenomination != 0:
+		return fmt.Errorf("bet %d is not a multiple of the %d chip", amount, r.ChipDenomination)
+	}
+	return nil
+}
+
+// RoundBet 将金额向下取整到筹码面额，并限制在牌桌限额与可用筹码之内
+// 可用筹码不足最低下注时返回0
+func (r Rules) RoundBet(amount, chips int) int {
+	if r.MaxBet > 0 {
+		amount = min(amount, r.MaxBet)
+	}
+	amount = min(amount, chips)
+	if r.ChipDenomination > 1 {
+		amount -= amount % r.ChipDenomination
+	}
+	if amount < r.MinBet {
+		if chips < r.MinBet {
+			return 0
+		}
+		amount = r.MinBet
+	}
+	return amount
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Scenario 牌局场景：牌靴顶部按固定顺序叠放的牌，以及可选的规则与筹码，用于演示和回归测试特定手牌
+// 初始发牌顺序为玩家、庄家、玩家、庄家（无底牌规则下庄家只发一张），之后按要牌顺序继续
+type Scenario struct {
+	Name        string
+	Description string
+	Rules       Rules
+	Bankroll    int    // 玩家初始筹码，0为默认
+	Cards       []Card // 从牌靴顶部开始的发牌顺序
+}
+
+// scenarioFile 场景文件的JSON格式
+type scenarioFile struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Variant     *GameVariant    `json:"variant"`  // 先载入变体的标准规则
+	Rules       json.RawMessage `json:"rules"`    // 覆盖的规则字段，枚举使用名称
+	Bankroll    int             `json:"bankroll"` // 玩家初始筹码
+	Cards       []string        `json:"cards"`    // 如 "8S"、"10h"、"A♠"，省略花色时为红心
+}
+
+// ParseScenario 解析JSON场景文件，场景中的规则字段覆盖base规则
+func ParseScenario(data []byte, base Rules) (*Scenario, error) {
+	var file scenarioFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse scenario: %w", err)
+	}
+
+	scenario := &Scenario{
+		Name:        file.Name,
+		Description: file.Description,
+		Rules:       base,
+		Bankroll:    file.Bankroll,
+	}
+	if file.Variant != nil {
+		scenario.Rules = VariantRules(*file.Variant)
+	}
+	if len(file.Rules) > 0 {
+		if err := json.Unmarshal(file.Rules, &scenario.Rules); err != nil {
+			return nil, fmt.Errorf("parse scenario rules: %w", err)
+		}
+	}
+	for _, name := range file.Cards {
+		card, err := ParseCard(name)
+		if err != nil {
+			return nil, err
+		}
+		scenario.Cards = append(scenario.Cards, card)
+	}
+
+	if err := scenario.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("scenario rules: %w", err)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+// validate 检查场景能否从规则的牌靴中发出
+func (s *Scenario) validate() error {
+	switch {
+	case len(s.Cards) == 0:
+		return errors.New("scenario lists no cards")
+	case s.Bankroll < 0:
+		return errors.New("scenario bankroll must not be negative")
+	case s.Rules.ProvablyFair:
+		return errors.New("scenarios cannot use provably fair shoes")
+	}
+
+	shoe := s.Rules.NewShoe()
+	for _, card := range s.Cards {
+		if !shoe.Remove(card) {
+			return fmt.Errorf("scenario deals more %s than the shoe holds", card)
+		}
+	}
+	return nil
+}
+
+// stack 把场景的牌从第一个牌靴中取出，按顺序叠放在牌靴顶部
+func (s *Scenario) stack(game *Game) {
+	deck := game.Deck
+	for _, card := range s.Cards {
+		if deck.Remove(card) {
+			continue
+		}
+		// 洗牌后烧掉的正好是场景中的牌：改烧牌靴顶部的另一张
+		if i := slices.Index(game.Discards.Burned, card); i >= 0 && len(deck.Cards) > 0 {
+			game.Discards.Burned[i] = deck.Cards[0]
+			deck.Cards = deck.Cards[1:]
+		}
+	}
+	deck.Cards = slices.Concat(s.Cards, deck.Cards)
+}
+
+// WithScenario configures the game to use the scenario's rules and bankroll and deal its cards first
+func WithScenario(scenario *Scenario) GameOption {
+	return func(game *Game) {
+		game.Rules = scenario.Rules
+		if scenario.Bankroll > 0 {
+			game.Player.Chips = scenario.Bankroll
+		}
+		game.scenario = scenario
+	}
+}
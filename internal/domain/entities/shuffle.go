@@ -0,0 +1,215 @@
+package entities
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// ShuffleModel 洗牌方式
+type ShuffleModel int
+
+const (
+	// ShuffleRandom is a perfect Fisher–Yates shuffle of a fresh shoe
+	ShuffleRandom ShuffleModel = iota
+	// ShuffleRiffle is a cut followed by repeated Gilbert–Shannon–Reeds riffles
+	ShuffleRiffle
+	// ShuffleStrip is repeated strip cuts followed by a cut
+	ShuffleStrip
+	// ShuffleCasino plugs the stub into the discards, then riffles, strips and riffles grab by grab
+	ShuffleCasino
+)
+
+// ShuffleModels 所有洗牌方式
+var ShuffleModels = []ShuffleModel{ShuffleRandom, ShuffleRiffle, ShuffleStrip, ShuffleCasino}
+
+func (m ShuffleModel) String() string {
+	switch m {
+	case ShuffleRandom:
+		return "random"
+	case ShuffleRiffle:
+		return "riffle"
+	case ShuffleStrip:
+		return "strip"
+	case ShuffleCasino:
+		return "casino"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseShuffleModel 解析洗牌方式名称
+func ParseShuffleModel(name string) (ShuffleModel, error) {
+	for _, model := range ShuffleModels {
+		if model.String() == name {
+			return model, nil
+		}
+	}
+	return ShuffleRandom, fmt.Errorf("unknown shuffle model %q", name)
+}
+
+// MarshalText 按名称编码
+func (m ShuffleModel) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText 按名称解码
+func (m *ShuffleModel) UnmarshalText(text []byte) error {
+	value, err := ParseShuffleModel(string(text))
+	*m = value
+	return err
+}
+
+const (
+	// 鸽尾式洗牌方式的洗牌次数（手洗常见的次数）
+	riffleModelPasses = 3
+	// 切条洗牌方式的切条次数
+	stripModelPasses = 3
+	// 赌场流程每次从左右两堆各取的张数（约半副牌）
+	casinoGrabSize = 26
+	// 切条洗牌每条的平均张数占总数的比例
+	stripPacketFraction = 1.0 / 8
+)
+
+// Shuffler 洗牌器：按洗牌方式生成牌序的置换
+// 不完美程度在[0,1)之间：0为理想手法，越大鸽尾落牌越容易成团、切条的条越厚越整齐
+type Shuffler struct {
+	model        ShuffleModel
+	imperfection float64
+	rng          *rand.Rand
+}
+
+// NewShuffler 用指定的随机数生成器创建洗牌器
+func NewShuffler(model ShuffleModel, imperfection float64, rng *rand.Rand) *Shuffler {
+	return &Shuffler{
+		model:        model,
+		imperfection: min(max(imperfection, 0), 0.99),
+		rng:          rng,
+	}
+}
+
+// Permutation 洗牌n张牌，返回洗牌后每个位置上的牌在洗牌前的位置
+// 洗牌前的后stub张是上一个牌靴未发的余牌，赌场流程把它们插入弃牌中
+func (s *Shuffler) Permutation(n, stub int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	stub = min(max(stub, 0), n)
+
+	switch s.model {
+	case ShuffleRiffle:
+		items = s.cut(items)
+		for range riffleModelPasses {
+			items = s.riffle(items)
+		}
+	case ShuffleStrip:
+		for range stripModelPasses {
+			items = s.strip(items)
+		}
+		items = s.cut(items)
+	case ShuffleCasino:
+		items = s.casino(items, stub)
+	default:
+		s.rng.Shuffle(n, func(i, j int) {
+			items[i], items[j] = items[j], items[i]
+		})
+	}
+	return items
+}
+
+// Shuffle 按洗牌方式重排卡牌，返回新的切片
+func (s *Shuffler) Shuffle(cards []Card, stub int) []Card {
+	shuffled := make([]Card, len(cards))
+	for i, from := range s.Permutation(len(cards), stub) {
+		shuffled[i] = cards[from]
+	}
+	return shuffled
+}
+
+// riffle Gilbert–Shannon–Reeds鸽尾式洗牌：按二项分布把牌分成两叠，
+// 每次按两叠剩余张数的比例从其中一叠落下一张；不完美时以imperfection的概率沿用上一张的那叠，落牌成团
+func (s *Shuffler) riffle(items []int) []int {
+	cut := 0
+	for range items {
+		cut += s.rng.IntN(2)
+	}
+	left, right := items[:cut], items[cut:]
+
+	out := make([]int, 0, len(items))
+	fromLeft := false
+	for len(left) > 0 || len(right) > 0 {
+		switch {
+		case len(left) == 0:
+			fromLeft = false
+		case len(right) == 0:
+			fromLeft = true
+		case len(out) > 0 && s.rng.Float64() < s.imperfection:
+		default:
+			fromLeft = s.rng.IntN(len(left)+len(right)) < len(left)
+		}
+
+		if fromLeft {
+			out = append(out, left[0])
+			left = left[1:]
+		} else {
+			out = append(out, right[0])
+			right = right[1:]
+		}
+	}
+	return out
+}
+
+// strip 切条洗牌：从顶部依次抽出若干小叠放到新牌堆上，小叠的先后顺序颠倒、叠内顺序不变
+func (s *Shuffler) strip(items []int) []int {
+	mean := max(int(float64(len(items))*stripPacketFraction*(1+s.imperfection)), 1)
+	// 理想手法每条的张数在均值上下浮动一半，不完美时更整齐
+	spread := int(float64(mean) * (1 - s.imperfection) / 2)
+
+	out := make([]int, 0, len(items))
+	for len(items) > 0 {
+		size := mean
+		if spread > 0 {
+			size += s.rng.IntN(2*spread+1) - spread
+		}
+		size = min(max(size, 1), len(items))
+		out = slices.Insert(out, 0, items[:size]...)
+		items = items[size:]
+	}
+	return out
+}
+
+// cut 切牌：在中间一半的随机位置把上下两叠交换
+func (s *Shuffler) cut(items []int) []int {
+	if len(items) < 4 {
+		return items
+	}
+	at := len(items)/4 + s.rng.IntN(len(items)/2+1)
+	return append(slices.Clone(items[at:]), items[:at]...)
+}
+
+// plug 把最后stub张余牌整叠插入其余牌中间一半的随机位置
+func (s *Shuffler) plug(items []int, stub int) []int {
+	rest, stubCards := items[:len(items)-stub], items[len(items)-stub:]
+	if stub == 0 || len(rest) == 0 {
+		return items
+	}
+	at := len(rest)/4 + s.rng.IntN(len(rest)/2+1)
+	return slices.Concat(rest[:at], stubCards, rest[at:])
+}
+
+// casino 赌场多副牌流程：余牌插入弃牌，分成左右两堆，每次从两堆各取一把合在一起，
+// 鸽尾-切条-鸽尾后叠到新牌堆上，最后切牌
+func (s *Shuffler) casino(items []int, stub int) []int {
+	items = s.plug(items, stub)
+	left, right := items[:len(items)/2], items[len(items)/2:]
+
+	out := make([]int, 0, len(items))
+	for len(left) > 0 || len(right) > 0 {
+		a, b := min(casinoGrabSize, len(left)), min(casinoGrabSize, len(right))
+		grab := slices.Concat(left[:a], right[:b])
+		left, right = left[a:], right[b:]
+		out = append(out, s.riffle(s.strip(s.riffle(grab)))...)
+	}
+	return s.cut(out)
+}
@@ -0,0 +1,250 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SideBetType 边注类型
+type SideBetType int
+
+const (
+	// SideBetPerfectPairs pays when the player's first two cards are a pair
+	SideBetPerfectPairs SideBetType = iota
+	// SideBetTwentyOnePlusThree pays on a poker hand made of the player's two cards and the dealer upcard
+	SideBetTwentyOnePlusThree
+	// SideBetLuckyLadies pays when the player's first two cards total 20
+	SideBetLuckyLadies
+)
+
+// SideBetTypes 所有边注类型
+var SideBetTypes = []SideBetType{SideBetPerfectPairs, SideBetTwentyOnePlusThree, SideBetLuckyLadies}
+
+func (t SideBetType) String() string {
+	switch t {
+	case SideBetPerfectPairs:
+		return "perfect-pairs"
+	case SideBetTwentyOnePlusThree:
+		return "21+3"
+	case SideBetLuckyLadies:
+		return "lucky-ladies"
+	default:
+		return "unknown"
+	}
+}
+
+// SideBetHand 边注牌型
+type SideBetHand int
+
+const (
+	// SideBetNoHand represents a losing side bet
+	SideBetNoHand SideBetHand = iota
+	// MixedPair is a pair of different colors
+	MixedPair
+	// ColoredPair is a pair of the same color but different suits
+	ColoredPair
+	// PerfectPair is a pair of the same suit
+	PerfectPair
+	// Flush is three cards of the same suit
+	Flush
+	// Straight is three consecutive ranks
+	Straight
+	// ThreeOfAKind is three cards of the same rank
+	ThreeOfAKind
+	// StraightFlush is a straight of the same suit
+	StraightFlush
+	// SuitedTrips is three identical cards
+	SuitedTrips
+	// AnyTwenty is any two cards totalling 20
+	AnyTwenty
+	// SuitedTwenty is a suited 20
+	SuitedTwenty
+	// MatchedTwenty is a 20 of the same rank and suit
+	MatchedTwenty
+	// QueenOfHeartsPair is a pair of queens of hearts
+	QueenOfHeartsPair
+	// QueenOfHeartsWithBlackjack is a pair of queens of hearts against a dealer blackjack
+	QueenOfHeartsWithBlackjack
+)
+
+func (h SideBetHand) String() string {
+	switch h {
+	case SideBetNoHand:
+		return "none"
+	case MixedPair:
+		return "mixed_pair"
+	case ColoredPair:
+		return "colored_pair"
+	case PerfectPair:
+		return "perfect_pair"
+	case Flush:
+		return "flush"
+	case Straight:
+		return "straight"
+	case ThreeOfAKind:
+		return "three_of_a_kind"
+	case StraightFlush:
+		return "straight_flush"
+	case SuitedTrips:
+		return "suited_trips"
+	case AnyTwenty:
+		return "any_twenty"
+	case SuitedTwenty:
+		return "suited_twenty"
+	case MatchedTwenty:
+		return "matched_twenty"
+	case QueenOfHeartsPair:
+		return "queen_of_hearts_pair"
+	case QueenOfHeartsWithBlackjack:
+		return "queen_of_hearts_with_blackjack"
+	default:
+		return "unknown"
+	}
+}
+
+// SideBetPayout 边注牌型的赔率（x:1）
+type SideBetPayout struct {
+	Hand   SideBetHand
+	Payout int
+}
+
+// SideBetPayTables 各边注的赔率表，按赔率从低到高排列
+var SideBetPayTables = map[SideBetType][]SideBetPayout{
+	SideBetPerfectPairs: {
+		{MixedPair, 6},
+		{ColoredPair, 12},
+		{PerfectPair, 25},
+	},
+	SideBetTwentyOnePlusThree: {
+		{Flush, 5},
+		{Straight, 10},
+		{ThreeOfAKind, 30},
+		{StraightFlush, 40},
+		{SuitedTrips, 100},
+	},
+	SideBetLuckyLadies: {
+		{AnyTwenty, 4},
+		{SuitedTwenty, 9},
+		{MatchedTwenty, 19},
+		{QueenOfHeartsPair, 125},
+		{QueenOfHeartsWithBlackjack, 1000},
+	},
+}
+
+// SideBetPayoutOf 边注牌型的赔率，未中奖返回0
+func SideBetPayoutOf(betType SideBetType, hand SideBetHand) int {
+	for _, line := range SideBetPayTables[betType] {
+		if line.Hand == hand {
+			return line.Payout
+		}
+	}
+	return 0
+}
+
+// SideBetResult 边注结算结果
+type SideBetResult struct {
+	Type     SideBetType
+	Amount   int
+	Hand     SideBetHand
+	Payout   int // 赔率（x:1），未中奖为0
+	NetValue int // 净输赢
+}
+
+// EvaluateSideBet 根据玩家前两张牌与庄家已发的牌判断边注牌型
+// 幸运女士的庄家Blackjack奖项需要发牌后已知庄家底牌
+func EvaluateSideBet(betType SideBetType, playerCards, dealerCards []Card) SideBetHand {
+	if len(playerCards) < 2 {
+		return SideBetNoHand
+	}
+	first, second := playerCards[0], playerCards[1]
+
+	switch betType {
+	case SideBetPerfectPairs:
+		return evaluatePerfectPairs(first, second)
+	case SideBetTwentyOnePlusThree:
+		if len(dealerCards) == 0 {
+			return SideBetNoHand
+		}
+		return evaluateTwentyOnePlusThree(first, second, dealerCards[0])
+	case SideBetLuckyLadies:
+		dealerBlackjack := len(dealerCards) == 2 && dealerCards[0].BaseValue()+dealerCards[1].BaseValue() == 21
+		return evaluateLuckyLadies(first, second, dealerBlackjack)
+	default:
+		return SideBetNoHand
+	}
+}
+
+// evaluatePerfectPairs 对子边注：同花色、同颜色、异颜色
+func evaluatePerfectPairs(first, second Card) SideBetHand {
+	switch {
+	case first.Rank != second.Rank:
+		return SideBetNoHand
+	case first.Suit == second.Suit:
+		return PerfectPair
+	case first.Suit.IsRed() == second.Suit.IsRed():
+		return ColoredPair
+	default:
+		return MixedPair
+	}
+}
+
+// evaluateTwentyOnePlusThree 21+3边注：三张牌组成的扑克牌型，A可作最大或最小
+func evaluateTwentyOnePlusThree(first, second, upCard Card) SideBetHand {
+	flush := first.Suit == second.Suit && second.Suit == upCard.Suit
+
+	ranks := []int{int(first.Rank), int(second.Rank), int(upCard.Rank)}
+	slices.Sort(ranks)
+	trips := ranks[0] == ranks[2]
+	straight := (ranks[0]+1 == ranks[1] && ranks[1]+1 == ranks[2]) ||
+		(ranks[0] == int(Ace) && ranks[1] == int(Queen) && ranks[2] == int(King))
+
+	switch {
+	case trips && flush:
+		return SuitedTrips
+	case straight && flush:
+		return StraightFlush
+	case trips:
+		return ThreeOfAKind
+	case straight:
+		return Straight
+	case flush:
+		return Flush
+	default:
+		return SideBetNoHand
+	}
+}
+
+// evaluateLuckyLadies 幸运女士边注：前两张牌合计20点
+func evaluateLuckyLadies(first, second Card, dealerBlackjack bool) SideBetHand {
+	if first.BaseValue()+second.BaseValue() != 20 {
+		return SideBetNoHand
+	}
+
+	queenOfHearts := Card{Suit: Hearts, Rank: Queen}
+	switch {
+	case first == queenOfHearts && second == queenOfHearts && dealerBlackjack:
+		return QueenOfHeartsWithBlackjack
+	case first == queenOfHearts && second == queenOfHearts:
+		return QueenOfHeartsPair
+	case first == second:
+		return MatchedTwenty
+	case first.Suit == second.Suit:
+		return SuitedTwenty
+	default:
+		return AnyTwenty
+	}
+}
+
+// ValidateSideBet 检查边注金额：须为正数、符合筹码面额且不超过牌桌最高下注
+func (r Rules) ValidateSideBet(amount int) error {
+	switch {
+	case amount <= 0:
+		return errors.New("side bet must be positive")
+	case r.MaxBet > 0 && amount > r.MaxBet:
+		return fmt.Errorf("side bet %d exceeds the table maximum of %d", amount, r.MaxBet)
+	case r.ChipDenomination > 1 && amount%r.ChipDenomination != 0:
+		return fmt.Errorf("side bet %d is not a multiple of the %d chip", amount, r.ChipDenomination)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package entities
+
+import "slices"
+
+// spanishDeckSize 西班牙牌每副张数（去掉4张10点数字牌）
+const spanishDeckSize = 48
+
+// NewSpanishShoe 创建由西班牙牌组成的牌靴，每副去掉所有10点数字牌（J、Q、K保留）
+func NewSpanishShoe(deckCount int) *Deck {
+	deck := newOrderedSpanishShoe(deckCount)
+	deck.Shuffle()
+	return deck
+}
+
+// newOrderedSpanishShoe 创建按出厂顺序排列的西班牙牌靴
+func newOrderedSpanishShoe(deckCount int) *Deck {
+	deckCount = max(deckCount, 1)
+	deck := &Deck{
+		Cards: make([]Card, 0, spanishDeckSize*deckCount),
+	}
+
+	for range deckCount {
+		for suit := Hearts; suit <= Spades; suit++ {
+			for rank := Ace; rank <= King; rank++ {
+				if rank == Ten {
+					continue
+				}
+				deck.Cards = append(deck.Cards, Card{Suit: suit, Rank: rank})
+			}
+		}
+	}
+	return deck
+}
+
+// Spanish21Rules 西班牙21点的常见规则：6副西班牙牌，H17，任意张数可加倍，
+// 分牌后可加倍，可再分A且分A后可要牌与加倍，玩家21点必胜，奖励赔付，后投降与加倍救援
+func Spanish21Rules() Rules {
+	rules := DefaultRules()
+	rules.Variant = VariantSpanish21
+	rules.DeckCount = 6
+	rules.SpanishDeck = true
+	rules.DealerHitsSoft17 = true
+	rules.DoubleAnyCards = true
+	rules.DoubleAfterSplit = true
+	rules.ResplitAces = true
+	rules.HitSplitAces = true
+	rules.Player21AlwaysWins = true
+	rules.BonusPayouts = true
+	rules.LateSurrender = true
+	rules.DoubleDownRescue = true
+	return rules
+}
+
+// TwentyOneBonus 西班牙21点奖励赔率（以注码为单位，无奖励时为1）
+// 5张21点3:2，6张2:1，7张及以上3:1；6-7-8与7-7-7杂色3:2，同花2:1，黑桃3:1；加倍后不发奖励
+func (r Rules) TwentyOneBonus(hand *Hand, doubled bool) float64 {
+	if !r.BonusPayouts || doubled || hand.Value() != 21 {
+		return 1
+	}
+
+	switch count := len(hand.Cards); {
+	case count >= 7:
+		return 3
+	case count == 6:
+		return 2
+	case count == 5:
+		return 1.5
+	case count == 3:
+		return threeCardBonus(hand.Cards)
+	default:
+		return 1
+	}
+}
+
+// threeCardBonus 6-7-8与7-7-7的奖励赔率
+func threeCardBonus(cards []Card) float64 {
+	ranks := []Rank{cards[0].Rank, cards[1].Rank, cards[2].Rank}
+	slices.Sort(ranks)
+	if !slices.Equal(ranks, []Rank{Six, Seven, Eight}) && !slices.Equal(ranks, []Rank{Seven, Seven, Seven}) {
+		return 1
+	}
+
+	suit := cards[0].Suit
+	if cards[1].Suit != suit || cards[2].Suit != suit {
+		return 1.5
+	}
+	if suit == Spades {
+		return 3
+	}
+	return 2
+}
@@ -20,6 +20,22 @@ const (
 	DealerWin
 	// Push represents the result when it's a tie
 	Push
+	// PlayerCharlie represents the result when player reaches the Charlie card count without busting
+	PlayerCharlie
+	// PlayerTwentyOne represents the result when player's 21 wins automatically
+	PlayerTwentyOne
+	// PlayerSurrender represents the result when player surrenders half the bet
+	PlayerSurrender
+	// PlayerBonus represents a winning 21 paid at a Spanish 21 bonus rate
+	PlayerBonus
+	// Dealer22Push represents a push against a dealer 22 in Blackjack Switch and Free Bet
+	Dealer22Push
+	// PlayerPontoon represents a winning pontoon (ace and ten) in Pontoon
+	PlayerPontoon
+	// DealerPontoon represents a dealer pontoon, which beats every player hand in Pontoon
+	DealerPontoon
+	// PlayerFiveCardTrick represents a winning five-card trick in Pontoon
+	PlayerFiveCardTrick
 )
 
 // GameResult 游戏结果结构
@@ -27,6 +43,7 @@ type GameResult struct {
 	ResultType ResultType
 	BetAmount  int
 	IsDoubled  bool
+	Hands      []*GameResult // 多手牌时各手的结果
 }
 
 // PlayerAction 玩家行动类型
@@ -43,6 +60,12 @@ const (
 	ActionDoubleDown
 	// ActionQuit represents the quit action
 	ActionQuit
+	// ActionSplit represents the split action
+	ActionSplit
+	// ActionSurrender represents late surrender or a double-down rescue
+	ActionSurrender
+	// ActionSwitch swaps the second cards of the two hands in Blackjack Switch
+	ActionSwitch
 )
 
 // ActionResult 行动结果
@@ -54,17 +77,32 @@ type ActionResult struct {
 
 // 玩家输入常量
 const (
-	InputHit        = "h"
-	InputHitFull    = "hit"
-	InputStand      = "s"
-	InputStandFull  = "stand"
-	InputDouble     = "d"
-	InputDoubleFull = "double"
-	InputDoubleDown = "doubledown"
-	InputQuit       = "q"
-	InputQuitFull   = "quit"
-	InputYes        = "y"
-	InputYesFull    = "yes"
-	InputNo         = "n"
-	InputNoFull     = "no"
+	InputHit           = "h"
+	InputHitFull       = "hit"
+	InputStand         = "s"
+	InputStandFull     = "stand"
+	InputTwist         = "t"
+	InputTwistFull     = "twist"
+	InputStickFull     = "stick"
+	InputBuy           = "b"
+	InputBuyFull       = "buy"
+	InputDouble        = "d"
+	InputDoubleFull    = "double"
+	InputDoubleDown    = "doubledown"
+	InputSplit         = "p"
+	InputSplitFull     = "split"
+	InputSurrender     = "u"
+	InputSurrenderFull = "surrender"
+	InputSwitch        = "w"
+	InputSwitchFull    = "switch"
+	InputQuit          = "q"
+	InputQuitFull      = "quit"
+	InputYes           = "y"
+	InputYesFull       = "yes"
+	InputNo            = "n"
+	InputNoFull        = "no"
+	InputKellyBet      = "k"
+	InputRepeatBet     = "r"
+	InputDoubleBet     = "2x"
+	InputShoe          = "c"
 )
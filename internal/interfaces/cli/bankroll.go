@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runBankrollCommand 资金风险分析子命令
+func runBankrollCommand(args []string, out io.Writer) error {
+	params := services.DefaultBankrollParams(entities.DefaultRules())
+	edgePercent := params.Edge * 100
+	policy := params.Policy.String()
+	var seed uint64
+
+	fs := flag.NewFlagSet("bankroll", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Float64Var(&params.Bankroll, "bankroll", params.Bankroll, "初始资金")
+	fs.Float64Var(&edgePercent, "edge", edgePercent, "玩家优势(%)，负数为庄家优势")
+	fs.Float64Var(&params.Variance, "variance", params.Variance, "每单位注码的方差")
+	fs.StringVar(&policy, "policy", policy, "下注策略: flat(平注) 或 kelly(按资金比例)")
+	fs.Float64Var(&params.BetAmount, "bet", params.BetAmount, "平注金额")
+	fs.Float64Var(&params.KellyMultiplier, "kelly", params.KellyMultiplier, "比例下注时相对完整凯利的倍数")
+	fs.Float64Var(&params.MinBet, "min-bet", params.MinBet, "牌桌最低下注，资金低于此值视为破产")
+	fs.Float64Var(&params.RoundsPerHour, "hands-per-hour", params.RoundsPerHour, "每小时局数")
+	fs.IntVar(&params.Rounds, "rounds", params.Rounds, "蒙特卡洛模拟的局数(0为不模拟)")
+	fs.IntVar(&params.Trials, "trials", params.Trials, "蒙特卡洛模拟次数")
+	fs.Uint64Var(&seed, "seed", 0, "随机种子(0为使用当前时间)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if params.Policy, err = services.ParseBetPolicy(policy); err != nil {
+		return err
+	}
+	params.Edge = edgePercent / 100
+
+	analysis, err := services.NewBankrollService(seed).Analyze(params)
+	if err != nil {
+		return err
+	}
+
+	writeBankrollAnalysis(out, analysis)
+	return nil
+}
+
+// writeBankrollAnalysis 输出资金风险分析结果
+func writeBankrollAnalysis(out io.Writer, analysis *dtos.BankrollAnalysisDTO) {
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintln(out, "📈 资金风险分析")
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "   初始资金: %.0f   下注策略: %s   初始注码: %.2f\n",
+		analysis.Bankroll, analysis.Policy, analysis.InitialBet)
+	fmt.Fprintf(out, "   玩家优势: %+.2f%%   方差: %.2f\n", analysis.Edge*100, analysis.Variance)
+	fmt.Fprintf(out, "💀 破产风险: %.2f%%\n", analysis.RiskOfRuin*100)
+	if analysis.N0 > 0 {
+		fmt.Fprintf(out, "🎯 N0: %.0f 局 (约 %.1f 小时)\n", analysis.N0, analysis.N0/max(analysis.RoundsPerHour, 1))
+	} else {
+		fmt.Fprintln(out, "🎯 N0: 无正期望，长期必然亏损")
+	}
+	fmt.Fprintf(out, "⏱️  每小时期望: %+.2f (标准差 %.2f，%.0f 局/小时)\n",
+		analysis.HourlyEV, analysis.HourlyStdDev, analysis.RoundsPerHour)
+
+	if sim := analysis.Simulation; sim != nil {
+		fmt.Fprintf(out, "\n🎲 %d 局后资金分布 (%d 次模拟):\n", sim.Rounds, sim.Trials)
+		fmt.Fprintf(out, "   平均 %.0f   中位数 %.0f\n", sim.Mean, sim.Median)
+		fmt.Fprintf(out, "   5%%: %.0f   25%%: %.0f   75%%: %.0f   95%%: %.0f\n", sim.P5, sim.P25, sim.P75, sim.P95)
+		fmt.Fprintf(out, "   破产比例 %.1f%%   盈利比例 %.1f%%\n", sim.RuinProbability*100, sim.ProfitProbability*100)
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Command 非交互式子命令
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(args []string, out io.Writer) error
+}
+
+// Commands 所有子命令
+var Commands = []Command{
+	{Name: "bankroll", Summary: "资金风险分析(破产风险、N0、每小时期望、资金分布)", Run: runBankrollCommand},
+	{Name: "house-edge", Summary: "按规则计算赌场优势及各项规则的影响", Run: runHouseEdgeCommand},
+	{Name: "counting-sim", Summary: "模拟算牌加注的优势，比较切牌牌靴与连续洗牌机", Run: runCountingSimulationCommand},
+	{Name: "shuffle-test", Summary: "统计各洗牌方式洗牌后残留的牌序相关性", Run: runShuffleAnalysisCommand},
+	{Name: "strategy-compare", Summary: "比较组成相关最优策略与基本策略的决策差异及期望值", Run: runStrategyComparisonCommand},
+	{Name: "strategy-chart", Summary: "按规则生成基本策略表(默认按无限副牌)", Run: runStrategyChartCommand},
+	{Name: "verify", Summary: "按牌靴历史文件验证可验证公平牌靴的承诺与牌序", Run: runVerifyCommand},
+}
+
+// IsCommand 判断参数是否为子命令名称
+func IsCommand(name string) bool {
+	return slices.ContainsFunc(Commands, func(command Command) bool {
+		return command.Name == name
+	})
+}
+
+// RunCommand 运行指定子命令
+func RunCommand(name string, args []string, out io.Writer) error {
+	for _, command := range Commands {
+		if command.Name == name {
+			return command.Run(args, out)
+		}
+	}
+
+	names := make([]string, 0, len(Commands))
+	for _, command := range Commands {
+		names = append(names, command.Name)
+	}
+	return fmt.Errorf("unknown command %q (available: %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 算牌练习默认配置
+const (
+	defaultCountingSystem   = "Hi-Lo"
+	defaultCountingDecks    = 6
+	defaultCountingInterval = 1000 // 毫秒
+	defaultCardsPerQuestion = 10
+)
+
+// runCountingDrill 算牌练习：按设定速度发牌，定期询问流水数与真数
+func (h *GameHandler) runCountingDrill() {
+	drill, err := h.setupCountingDrill()
+	if err != nil {
+		h.display.ShowError(fmt.Sprintf("操作失败: %v", err))
+		return
+	}
+	h.display.ShowCountingDrillStart(drill)
+	interval := time.Duration(drill.DealIntervalMs) * time.Millisecond
+
+	defer func() {
+		h.gameService.FinishCountingDrill()
+		h.display.ShowCountingHistory(h.gameService.GetCountingHistory())
+		h.getInput("按回车键返回菜单...")
+	}()
+
+	for {
+		time.Sleep(interval)
+		card, err := h.gameService.DealCountingCard()
+		if err != nil {
+			h.display.ShowError(fmt.Sprintf("操作失败: %v", err))
+			return
+		}
+		h.display.ShowCountingCard(card)
+
+		if !card.QuestionDue {
+			continue
+		}
+		if !h.askCount(drill.System.Balanced) || card.Finished {
+			return
+		}
+	}
+}
+
+// setupCountingDrill 读取算牌练习配置，直接回车使用默认值
+func (h *GameHandler) setupCountingDrill() (*dtos.CountingDrillDTO, error) {
+	systems := h.gameService.GetCountingSystems()
+	names := make([]string, 0, len(systems))
+	for _, system := range systems {
+		names = append(names, system.Name)
+	}
+
+	system := h.getInput(fmt.Sprintf("算牌系统 (%s) [%s]: ", strings.Join(names, "/"), defaultCountingSystem))
+	if system == "" {
+		system = defaultCountingSystem
+	}
+	decks := h.readIntWithDefault("牌副数", defaultCountingDecks)
+	interval := h.readIntWithDefault("发牌间隔(毫秒)", defaultCountingInterval)
+	cardsPerQuestion := h.readIntWithDefault("每多少张提问一次", defaultCardsPerQuestion)
+
+	return h.gameService.StartCountingDrill(system, decks, time.Duration(interval)*time.Millisecond, cardsPerQuestion)
+}
+
+// askCount 询问流水数（平衡系统还询问真数），返回是否继续练习
+func (h *GameHandler) askCount(balanced bool) bool {
+	var runningCount int
+	for {
+		input := h.getInput("流水数 (q退出): ")
+		if strings.ToLower(input) == entities.InputQuit {
+			return false
+		}
+		value, err := strconv.Atoi(input)
+		if err != nil {
+			h.display.ShowError("请输入整数")
+			continue
+		}
+		runningCount = value
+		break
+	}
+
+	trueCount := 0.0
+	for balanced {
+		input := h.getInput("真数 (q退出): ")
+		if strings.ToLower(input) == entities.InputQuit {
+			return false
+		}
+		value, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			h.display.ShowError("请输入数字")
+			continue
+		}
+		trueCount = value
+		break
+	}
+
+	answer, err := h.gameService.AnswerCountingQuestion(runningCount, trueCount)
+	if err != nil {
+		h.display.ShowError(fmt.Sprintf("操作失败: %v", err))
+		return false
+	}
+	h.display.ShowCountingAnswer(answer)
+
+	return strings.ToLower(h.getInput("按回车键继续 (q退出): ")) != entities.InputQuit
+}
+
+// readIntWithDefault 读取正整数，输入为空或无效时使用默认值
+func (h *GameHandler) readIntWithDefault(label string, defaultValue int) int {
+	input := h.getInput(fmt.Sprintf("%s [%d]: ", label, defaultValue))
+	value, err := strconv.Atoi(input)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
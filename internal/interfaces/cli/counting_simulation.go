@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runCountingSimulationCommand 算牌优势模拟子命令：同一规则下分别用切牌牌靴与连续洗牌机模拟
+func runCountingSimulationCommand(args []string, out io.Writer) error {
+	rules := entities.DefaultRules()
+	params := services.DefaultCountingSimulationParams()
+	system := params.System.Name
+
+	fs := flag.NewFlagSet("counting-sim", flag.ContinueOnError)
+	fs.SetOutput(out)
+	RegisterRuleFlags(fs, &rules)
+	fs.StringVar(&system, "system", system, "算牌系统: Hi-Lo / KO / Hi-Opt I / Hi-Opt II / Omega II / Zen")
+	fs.IntVar(&params.Rounds, "rounds", params.Rounds, "每种洗牌方式模拟的局数")
+	fs.IntVar(&params.Spread, "spread", params.Spread, "最大下注单位(真数每高1点多下1单位)")
+	if err := ParseRuleFlags(fs, &rules, args); err != nil {
+		return err
+	}
+
+	var err error
+	if params.System, err = services.FindCountingSystem(system); err != nil {
+		return err
+	}
+
+	for _, continuous := range []bool{false, true} {
+		rules.ContinuousShuffle = continuous
+		simulation, err := services.NewCountingSimulator(rules).Simulate(params)
+		if err != nil {
+			return err
+		}
+		writeCountingSimulation(out, simulation)
+	}
+	return nil
+}
+
+// writeCountingSimulation 输出算牌优势模拟结果
+func writeCountingSimulation(out io.Writer, simulation *dtos.CountingSimulationDTO) {
+	shuffle := "切牌牌靴"
+	if simulation.ContinuousShuffle {
+		shuffle = "连续洗牌机"
+	}
+
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "🧮 算牌优势模拟 (%s，%s，%d 局)\n", shuffle, simulation.System, simulation.Rounds)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "   平注期望: %+.3f%%\n", simulation.FlatEV*100)
+	fmt.Fprintf(out, "   1-%d 加注期望: %+.3f%% (平均下注 %.2f 单位)\n", simulation.Spread, simulation.SpreadEV*100, simulation.AverageBet)
+	fmt.Fprintln(out, "   下注前真数    局数      期望")
+	for _, result := range simulation.TrueCounts {
+		fmt.Fprintf(out, "   %+5d    %9d   %+.2f%%\n", result.TrueCount, result.Rounds, result.EV*100)
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+}
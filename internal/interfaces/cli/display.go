@@ -2,7 +2,10 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,12 +13,28 @@ import (
 	"github.com/luffy050596/go-blackjack/internal/domain/entities"
 )
 
-// DisplayService 显示服务
-type DisplayService struct{}
+// DisplayService 显示服务（逐行滚动输出的经典界面）
+type DisplayService struct {
+	scanner *bufio.Scanner
+}
 
 // NewDisplayService 创建显示服务
 func NewDisplayService() *DisplayService {
-	return &DisplayService{}
+	return &DisplayService{
+		scanner: bufio.NewScanner(os.Stdin),
+	}
+}
+
+// ReadInput 获取用户输入
+func (d *DisplayService) ReadInput(prompt string) string {
+	fmt.Print(prompt)
+	d.scanner.Scan()
+	return strings.TrimSpace(d.scanner.Text())
+}
+
+// ReadAction 读取玩家行动
+func (d *DisplayService) ReadAction(options ...PlayerPromptOption) string {
+	return d.ReadInput(d.buildPlayerPrompt(options...))
 }
 
 // ShowWelcome 显示欢迎信息
@@ -32,6 +51,9 @@ func (d *DisplayService) ShowMenu() {
 	fmt.Print(MenuOptionStart + ". 开始游戏\n")
 	fmt.Print(MenuOptionRules + ". 游戏规则\n")
 	fmt.Print(MenuOptionExit + ". 退出游戏\n")
+	fmt.Print(MenuOptionTraining + ". 训练模式(基本策略评分)\n")
+	fmt.Print(MenuOptionDrill + ". 策略专项练习\n")
+	fmt.Print(MenuOptionCounting + ". 算牌练习\n")
 	fmt.Println()
 }
 
@@ -55,15 +77,17 @@ func (d *DisplayService) ShowRoundStart(round, chips int) {
 // ShowBettingSection 显示下注区域
 func (d *DisplayService) ShowBettingSection(chips int) {
 	fmt.Printf("💰 当前筹码: %d\n", chips)
-	fmt.Println("请选择下注金额:")
 }
 
 // ShowBetOptions 显示下注选项
-func (d *DisplayService) ShowBetOptions(options []int) {
-	for i, amount := range options {
-		fmt.Printf("%d. %d 筹码\n", i+1, amount)
+func (d *DisplayService) ShowBetOptions(options *dtos.BetOptionsDTO) {
+	fmt.Printf("🎰 牌桌限额: %s (筹码面额 %d)\n", formatTableLimits(options), options.ChipDenomination)
+	if len(options.Amounts) > 0 {
+		fmt.Printf("   常用金额: %s\n", joinInts(options.Amounts, " / "))
+	}
+	if options.LastBet > 0 {
+		fmt.Printf("   上次下注: %d (r 重复, 2x 翻倍)\n", options.LastBet)
 	}
-	fmt.Printf("%d. 退出游戏\n", len(options)+1)
 	fmt.Println()
 }
 
@@ -73,6 +97,63 @@ func (d *DisplayService) ShowBetSuccess(amount int) {
 	time.Sleep(500 * time.Millisecond)
 }
 
+// ShowSideBetOptions 显示边注赔率表与按剩余牌堆计算的期望值
+func (d *DisplayService) ShowSideBetOptions(evs []*dtos.SideBetEVDTO) {
+	fmt.Println("🎲 边注 (发牌后立即结算):")
+	for _, ev := range evs {
+		fmt.Printf("   %s  期望值 %+.2f%%\n", sideBetNames[ev.Type], ev.EV*100)
+		for _, outcome := range ev.Outcomes {
+			fmt.Printf("      %s %4d:1  概率 %.3f%%\n", padRight(sideBetHandNames[outcome.Hand], 16), outcome.Payout, outcome.Probability*100)
+		}
+	}
+	fmt.Println()
+}
+
+// ShowSideBetResults 显示边注结算结果
+func (d *DisplayService) ShowSideBetResults(results []*dtos.SideBetResultDTO) {
+	fmt.Println("\n🎲 边注结算:")
+	for _, result := range results {
+		fmt.Printf("   %s: %s\n", sideBetNames[result.Type], formatSideBetResult(result))
+	}
+}
+
+// ShowShoeComposition 显示还没看到的牌按点数的组成与移除效应
+func (d *DisplayService) ShowShoeComposition(composition *dtos.ShoeCompositionDTO) {
+	fmt.Println("👟 牌靴组成:")
+	fmt.Printf("   余牌 %d/%d 张 (%.1f 副)  渗透率 %.1f%%  弃牌 %d  烧牌 %d\n",
+		composition.CardsRemaining, composition.ShoeSize, composition.DecksRemaining,
+		composition.Penetration*100, composition.Discarded, composition.Burned)
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println("点数   余牌  完整牌靴   占比   完整占比")
+	for _, rank := range composition.Ranks {
+		fmt.Printf("%-4s %6d %9d %6.1f%% %8.1f%%\n",
+			rank.Rank, rank.Remaining, rank.Fresh, rank.Percent*100, rank.FreshPercent*100)
+	}
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Printf("按余牌计算的玩家期望值: %+.3f%%\n", composition.PlayerEdge*100)
+	fmt.Println("移除一张牌对玩家期望值的影响:")
+	for _, effect := range composition.EffectsOfRemoval {
+		fmt.Printf("   %-3s %+.4f%%\n", effect.Rank, effect.Effect*100)
+	}
+	fmt.Println()
+}
+
+// ShowFairCommitment 显示为下一个牌靴公布的承诺，玩家随后设置客户端种子
+func (d *DisplayService) ShowFairCommitment(fair *dtos.FairShoeDTO) {
+	fmt.Printf("🔒 可验证公平牌靴 #%d 的承诺\n", fair.Number)
+	fmt.Printf("   承诺: %s\n", fair.Commitment)
+	fmt.Println("   这个牌靴开始前可设置或更换客户端种子")
+	fmt.Println()
+}
+
+// ShowFairReveal 显示牌靴结束后公开的服务器种子
+func (d *DisplayService) ShowFairReveal(fair *dtos.FairShoeDTO) {
+	fmt.Printf("🔓 牌靴 #%d 结束 (已发 %d 张)\n", fair.Number, len(fair.Dealt))
+	fmt.Printf("   服务器种子: %s\n", fair.ServerSeed)
+	fmt.Println("   可用 verify 命令按牌靴历史文件验证")
+	fmt.Println()
+}
+
 // ShowPlayerTurnStart 显示玩家回合开始
 func (d *DisplayService) ShowPlayerTurnStart() {
 	fmt.Println("🎮 === 玩家回合开始 ===")
@@ -86,12 +167,49 @@ func (d *DisplayService) ShowDealerTurnStart() {
 
 // PlayerPromptOptions contains options for player prompt configuration
 type PlayerPromptOptions struct {
-	doubleDown bool
+	noHit         bool
+	noStand       bool
+	pontoon       bool
+	doubleDown    bool
+	doubleForLess bool
+	freeDouble    bool
+	split         bool
+	freeSplit     bool
+	surrender     bool
+	switchCards   bool
 }
 
 // PlayerPromptOption is a function type for configuring player prompt options
 type PlayerPromptOption func(options *PlayerPromptOptions)
 
+// WithHit configures whether the hit option is available (it is by default)
+func WithHit(hit bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.noHit = !hit
+	}
+}
+
+// WithStand configures whether the stand option is available (it is by default)
+func WithStand(stand bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.noStand = !stand
+	}
+}
+
+// WithPontoon configures whether the prompt uses the Pontoon twist/stick/buy wording
+func WithPontoon(pontoon bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.pontoon = pontoon
+	}
+}
+
+// WithSurrender configures whether surrender option is available
+func WithSurrender(surrender bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.surrender = surrender
+	}
+}
+
 // WithDoubleDown configures whether double down option is available
 func WithDoubleDown(doubleDown bool) PlayerPromptOption {
 	return func(options *PlayerPromptOptions) {
@@ -99,6 +217,41 @@ func WithDoubleDown(doubleDown bool) PlayerPromptOption {
 	}
 }
 
+// WithDoubleForLess configures whether the double may be less than the original bet
+func WithDoubleForLess(doubleForLess bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.doubleForLess = doubleForLess
+	}
+}
+
+// WithFreeDouble configures whether the double is free
+func WithFreeDouble(free bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.freeDouble = free
+	}
+}
+
+// WithFreeSplit configures whether the split is free
+func WithFreeSplit(free bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.freeSplit = free
+	}
+}
+
+// WithSwitch configures whether the switch option is available
+func WithSwitch(switchCards bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.switchCards = switchCards
+	}
+}
+
+// WithSplit configures whether split option is available
+func WithSplit(split bool) PlayerPromptOption {
+	return func(options *PlayerPromptOptions) {
+		options.split = split
+	}
+}
+
 // buildPlayerPrompt 构建玩家输入提示
 func (d *DisplayService) buildPlayerPrompt(options ...PlayerPromptOption) string {
 	opts := PlayerPromptOptions{}
@@ -107,36 +260,95 @@ func (d *DisplayService) buildPlayerPrompt(options ...PlayerPromptOption) string
 		option(&opts)
 	}
 
-	prompt := "请选择: (h)要牌 (s)停牌"
+	prompt := "请选择:"
+	if opts.pontoon {
+		for _, key := range pontoonPromptKeys(opts) {
+			prompt += fmt.Sprintf(" (%s)%s", key[0], key[1])
+		}
+		return prompt + " (q)退出: "
+	}
+	if !opts.noHit {
+		prompt += " (h)要牌"
+	}
+	prompt += " (s)停牌"
 	if opts.doubleDown {
 		prompt += " (d)加倍"
+		switch {
+		case opts.freeDouble:
+			prompt += "(免费)"
+		case opts.doubleForLess:
+			prompt += "(可少加)"
+		}
+	}
+	if opts.split {
+		prompt += " (p)分牌"
+		if opts.freeSplit {
+			prompt += "(免费)"
+		}
+	}
+	if opts.surrender {
+		prompt += " (u)投降"
+	}
+	if opts.switchCards {
+		prompt += " (w)换牌"
 	}
 	prompt += " (q)退出: "
 	return prompt
 }
 
+// pontoonPromptKeys 按Pontoon术语列出可用的行动按键及说明：twist要牌、stick停牌、buy买牌
+func pontoonPromptKeys(opts PlayerPromptOptions) [][2]string {
+	var keys [][2]string
+	if !opts.noHit {
+		keys = append(keys, [2]string{entities.InputTwist, "要牌twist"})
+	}
+	if !opts.noStand {
+		keys = append(keys, [2]string{entities.InputStand, "停牌stick"})
+	}
+	if opts.doubleDown {
+		keys = append(keys, [2]string{entities.InputBuy, "买牌buy"})
+	}
+	if opts.split {
+		keys = append(keys, [2]string{entities.InputSplit, "分牌"})
+	}
+	return keys
+}
+
 // ShowGameState 显示游戏状态
-func (d *DisplayService) ShowGameState(gameState *dtos.GameStateDTO, hideFirstDealerCard bool) {
+func (d *DisplayService) ShowGameState(gameState *dtos.GameStateDTO, hideHoleCard bool) {
 	fmt.Print("\n👨 庄家手牌")
 
-	if hideFirstDealerCard && len(gameState.DealerHand.Cards) > 1 {
-		fmt.Println(" (第一张牌隐藏):")
-		d.showHand(gameState.DealerHand, true)
-	} else {
+	visibility := dealerVisibility(hideHoleCard, gameState.DealerFaceDown)
+	switch {
+	case visibility == dealtCardsHidden:
+		fmt.Println(" (两张暗牌):")
+	case visibility == holeCardHidden && len(gameState.DealerHand.Cards) > 1:
+		fmt.Println(" (底牌隐藏):")
+	default:
 		fmt.Printf(" (点数: %d):\n", gameState.DealerHand.Value)
-		d.showHand(gameState.DealerHand, false)
 	}
+	d.showHand(gameState.DealerHand, visibility)
 
-	fmt.Printf("\n👨 玩家手牌 (点数: %d):\n", gameState.PlayerHand.Value)
-	d.showHand(gameState.PlayerHand, false)
+	if len(gameState.PlayerHands) == 0 {
+		fmt.Printf("\n👨 玩家手牌 (点数: %d):\n", gameState.PlayerHand.Value)
+		d.showHand(gameState.PlayerHand, cardsVisible)
+	}
+	for i, hand := range gameState.PlayerHands {
+		fmt.Printf("\n👨 第%d手 (点数: %d, 下注: %d%s)", i+1, hand.Hand.Value, hand.Bet, formatFreeBet(hand.FreeBet))
+		if i == gameState.ActiveHand && gameState.State == entities.StatePlayerTurn {
+			fmt.Print(" 👈")
+		}
+		fmt.Println(":")
+		d.showHand(hand.Hand, cardsVisible)
+	}
 
 	fmt.Println()
 }
 
-// showHand 显示手牌
-func (d *DisplayService) showHand(hand *dtos.HandDTO, hideFirst bool) {
+// showHand 显示手牌，背面朝上的牌显示为牌背
+func (d *DisplayService) showHand(hand *dtos.HandDTO, visibility cardVisibility) {
 	for i, card := range hand.Cards {
-		if hideFirst && i == 0 {
+		if visibility.hides(i) {
 			fmt.Print("🂠 ")
 		} else {
 			fmt.Printf("%s%s ", d.getSuitSymbol(card.Suit), card.Rank)
@@ -145,16 +357,24 @@ func (d *DisplayService) showHand(hand *dtos.HandDTO, hideFirst bool) {
 	fmt.Println()
 }
 
+// formatFreeBet 免费注码说明
+func formatFreeBet(freeBet int) string {
+	if freeBet == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", 其中免费 %d", freeBet)
+}
+
 // getSuitSymbol 获取花色符号
 func (d *DisplayService) getSuitSymbol(suit string) string {
 	switch suit {
-	case "Hearts":
+	case entities.Hearts.String():
 		return "♥️"
-	case "Diamonds":
+	case entities.Diamonds.String():
 		return "♦️"
-	case "Clubs":
+	case entities.Clubs.String():
 		return "♣️"
-	case "Spades":
+	case entities.Spades.String():
 		return "♠️"
 	default:
 		return "🃏"
@@ -166,6 +386,11 @@ func (d *DisplayService) ShowBlackjack() {
 	fmt.Println("🎉 21点! 🎉")
 }
 
+// ShowDealerPeekBlackjack 显示庄家偷看底牌发现Blackjack
+func (d *DisplayService) ShowDealerPeekBlackjack() {
+	fmt.Println("👀 庄家检查底牌: Blackjack! 本局直接结算")
+}
+
 // ShowPlayerBust 显示玩家爆牌
 func (d *DisplayService) ShowPlayerBust() {
 	fmt.Println("💥 爆牌了! 💥")
@@ -192,6 +417,12 @@ func (d *DisplayService) ShowActionResult(result *dtos.ActionResultDTO) {
 			fmt.Printf("🃏 获得一张牌: %s%s\n",
 				d.getSuitSymbol(result.Card.Suit), result.Card.Rank)
 		}
+	case entities.ActionSurrender:
+		fmt.Println("🏳️ 投降")
+	case entities.ActionSplit:
+		fmt.Println("✂️ 分牌")
+	case entities.ActionSwitch:
+		fmt.Println("🔀 交换两手牌的第二张牌")
 	}
 
 	time.Sleep(500 * time.Millisecond)
@@ -207,11 +438,126 @@ func (d *DisplayService) ShowGameResult(result *dtos.GameResultDTO) {
 	if result.IsDoubled {
 		fmt.Print(" (已加倍)")
 	}
-	fmt.Printf("\n当前筹码: %d\n", result.PlayerChips)
+	fmt.Println()
+	for i, hand := range result.Hands {
+		fmt.Printf("  第%d手: %s (下注 %d", i+1, GetResultMessage(hand.Type), hand.BetAmount)
+		if hand.IsDoubled {
+			fmt.Print(", 已加倍")
+		}
+		fmt.Println(")")
+	}
+	fmt.Printf("当前筹码: %d\n", result.PlayerChips)
 	fmt.Println(strings.Repeat("=", 40))
 	fmt.Println()
 }
 
+// ShowDecisionFeedback 显示基本策略评分
+func (d *DisplayService) ShowDecisionFeedback(feedback *dtos.DecisionFeedbackDTO) {
+	if feedback == nil {
+		return
+	}
+
+	if feedback.IsCorrect {
+		fmt.Printf("🎓 正确! %s %s 符合基本策略 (EV %+.3f)\n",
+			getCategoryName(feedback.Category), getPlayerActionName(feedback.ChosenAction), feedback.ChosenEV)
+		return
+	}
+
+	fmt.Printf("🎓 错误: 基本策略为 %s (EV %+.3f)，你选择了 %s (EV %+.3f)\n",
+		getPlayerActionName(feedback.BestAction), feedback.BestEV,
+		getPlayerActionName(feedback.ChosenAction), feedback.ChosenEV)
+	fmt.Printf("   💸 期望损失: %.1f%% 注码\n", feedback.EVCost*100)
+}
+
+// ShowTrainingStats 显示训练统计
+func (d *DisplayService) ShowTrainingStats(stats *dtos.TrainingStatsDTO) {
+	if stats == nil {
+		return
+	}
+
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println("🎓 训练统计")
+	fmt.Println(strings.Repeat("─", 40))
+	for _, category := range stats.Categories {
+		if category.Decisions == 0 {
+			fmt.Printf("   %s: 暂无记录\n", getCategoryName(category.Category))
+			continue
+		}
+		fmt.Printf("   %s: %d/%d 正确 (%.1f%%)，平均损失 %.2f%% 注码\n",
+			getCategoryName(category.Category), category.Correct, category.Decisions,
+			category.Accuracy*100, category.AverageEVCost*100)
+	}
+	if stats.TotalDecisions > 0 {
+		fmt.Printf("📊 总正确率: %.1f%%，累计期望损失 %.2f 注码\n", stats.Accuracy*100, stats.TotalEVCost)
+	}
+	fmt.Printf("🎯 最需加强: %s\n", getCategoryName(stats.WeakestCategory))
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println()
+}
+
+// ShowStrategyDrill 显示策略练习题目
+func (d *DisplayService) ShowStrategyDrill(drill *dtos.StrategyDrillDTO) {
+	fmt.Printf("\n🎓 专项练习 [%s]\n", getCategoryName(drill.Category))
+	fmt.Printf("👨 庄家明牌: %s%s\n", d.getSuitSymbol(drill.DealerUpCard.Suit), drill.DealerUpCard.Rank)
+	fmt.Printf("👨 玩家手牌 (点数: %d): ", drill.PlayerHand.Value)
+	d.showHand(drill.PlayerHand, cardsVisible)
+}
+
+// ShowCountingDrillStart 显示算牌练习开始
+func (d *DisplayService) ShowCountingDrillStart(drill *dtos.CountingDrillDTO) {
+	d.clearScreen()
+	fmt.Printf("🧮 算牌练习: %s (%d副牌)\n", drill.System.Name, drill.DeckCount)
+	fmt.Printf("   计数值(A,2-10): %v\n", drill.System.Tags)
+	fmt.Printf("   发牌间隔 %dms，每 %d 张牌提问一次，共发 %d 张\n",
+		drill.DealIntervalMs, drill.CardsPerQuestion, drill.CardsToDeal)
+	if !drill.System.Balanced {
+		fmt.Println("   不平衡系统只考流水数")
+	}
+	fmt.Println()
+}
+
+// ShowCountingCard 显示算牌练习发出的牌（原地覆盖上一张）
+func (d *DisplayService) ShowCountingCard(card *dtos.CountingCardDTO) {
+	fmt.Printf("\r   🃏 %s%-2s   [%d 张]   ", d.getSuitSymbol(card.Card.Suit), card.Card.Rank, card.CardsDealt)
+	if card.QuestionDue {
+		fmt.Println()
+	}
+}
+
+// ShowCountingAnswer 显示算牌回答结果
+func (d *DisplayService) ShowCountingAnswer(answer *dtos.CountingAnswerDTO) {
+	if answer.RunningCorrect {
+		fmt.Printf("✅ 流水数正确: %d\n", answer.RunningCount)
+	} else {
+		fmt.Printf("❌ 流水数应为 %d，你回答了 %d\n", answer.RunningCount, answer.AnsweredRunning)
+	}
+	if answer.TrueCountAsked {
+		if answer.TrueCorrect {
+			fmt.Printf("✅ 真数正确: %.1f\n", answer.TrueCount)
+		} else {
+			fmt.Printf("❌ 真数应为 %.1f，你回答了 %.1f\n", answer.TrueCount, answer.AnsweredTrue)
+		}
+	}
+	fmt.Printf("⏱️  用时 %.1f 秒\n\n", answer.ResponseSeconds)
+}
+
+// ShowCountingHistory 显示算牌练习历史
+func (d *DisplayService) ShowCountingHistory(history []*dtos.CountingSessionDTO) {
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println("🧮 算牌练习记录")
+	fmt.Println(strings.Repeat("─", 40))
+	if len(history) == 0 {
+		fmt.Println("   暂无记录")
+	}
+	for _, session := range history {
+		fmt.Printf("   %s %s %d副: %.0f张/分钟，正确 %d/%d (%.1f%%)，平均用时 %.1f 秒\n",
+			session.StartedAt.Format("15:04"), session.System, session.DeckCount, session.CardsPerMinute,
+			session.CorrectAnswers, session.Questions, session.Accuracy*100, session.AverageResponseSeconds)
+	}
+	fmt.Println(strings.Repeat("─", 40))
+	fmt.Println()
+}
+
 // ShowGameOver 显示游戏结束
 func (d *DisplayService) ShowGameOver() {
 	fmt.Println("💸 筹码用完了！游戏结束！")
@@ -255,6 +601,23 @@ func (d *DisplayService) ShowProbabilities(probabilities *dtos.ProbabilityResult
 		d.showActionAnalysis(probabilities.ActionAnalysis)
 	}
 
+	if advice := probabilities.SwitchAdvice; advice != nil {
+		fmt.Println()
+		fmt.Printf("🔀 换牌期望: 保持 %+.3f / 换牌 %+.3f", advice.KeepEV, advice.SwitchEV)
+		if advice.ShouldSwitch {
+			fmt.Println("  👈 建议换牌")
+		} else {
+			fmt.Println("  👈 建议保持")
+		}
+	}
+
+	if reference := probabilities.InfiniteDeck; reference != nil {
+		fmt.Println()
+		fmt.Printf("♾️  无限副牌参考: 期望 %+.3f，推荐%s\n",
+			reference.ExpectedValue, getActionNameByKey(reference.RecommendedAction))
+		fmt.Printf("   牌堆组成影响: %+.3f (当前牌堆期望 %+.3f)\n", reference.CompositionEffect, reference.ShoeExpectedValue)
+	}
+
 	fmt.Println(strings.Repeat("─", 40))
 	fmt.Println()
 }
@@ -288,6 +651,10 @@ func (d *DisplayService) showActionAnalysis(analysis *dtos.ActionAnalysisDTO) {
 		}
 	}
 
+	if name, ok := strategySourceNames[analysis.StrategySource]; ok {
+		fmt.Printf("   推荐来源: %s\n", name)
+	}
+
 	// 显示最优期望值
 	if analysis.ExpectedValue > 0 {
 		fmt.Printf("\n🏆 最优策略期望胜率: %.1f%%\n", analysis.ExpectedValue*100)
@@ -335,6 +702,63 @@ func (d *DisplayService) showKellyRecommendation(kelly *dtos.KellyRecommendation
 	}
 }
 
+// formatTableLimits 格式化牌桌限额
+func formatTableLimits(options *dtos.BetOptionsDTO) string {
+	if options.MaxBet > 0 {
+		return fmt.Sprintf("%d - %d", options.MinBet, options.MaxBet)
+	}
+	return fmt.Sprintf("%d 起", options.MinBet)
+}
+
+// joinInts 用分隔符连接整数
+func joinInts(values []int, sep string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, strconv.Itoa(value))
+	}
+	return strings.Join(parts, sep)
+}
+
+// strategySourceNames 非模拟胜率的推荐来源名称
+var strategySourceNames = map[string]string{
+	"basic":       "基本策略(总点数)",
+	"composition": "组成相关最优策略",
+}
+
+// getPlayerActionName 获取玩家操作名称
+func getPlayerActionName(action entities.PlayerAction) string {
+	switch action {
+	case entities.ActionHit:
+		return "要牌"
+	case entities.ActionStand:
+		return "停牌"
+	case entities.ActionDoubleDown:
+		return "加倍"
+	case entities.ActionSplit:
+		return "分牌"
+	case entities.ActionSurrender:
+		return "投降"
+	case entities.ActionSwitch:
+		return "换牌"
+	default:
+		return "未知操作"
+	}
+}
+
+// getCategoryName 获取手牌类别名称
+func getCategoryName(category string) string {
+	switch category {
+	case "hard":
+		return "硬牌"
+	case "soft":
+		return "软牌"
+	case "pair":
+		return "对子"
+	default:
+		return category
+	}
+}
+
 // getActionKey 将操作名称转换为操作键
 func getActionKey(actionName string) string {
 	switch actionName {
@@ -346,16 +770,62 @@ func getActionKey(actionName string) string {
 		return "double"
 	case "分牌":
 		return "split"
+	case "投降":
+		return "surrender"
+	case "换牌":
+		return "switch"
 	default:
 		return ""
 	}
 }
 
+// getActionNameByKey 由操作键获取操作名称
+func getActionNameByKey(key string) string {
+	for _, name := range []string{"停牌", "要牌", "加倍", "分牌", "投降", "换牌"} {
+		if getActionKey(name) == key {
+			return name
+		}
+	}
+	return key
+}
+
 // clearScreen 清屏
 func (d *DisplayService) clearScreen() {
 	fmt.Print("\033[2J\033[H")
 }
 
+// sideBetNames 边注中文名称
+var sideBetNames = map[entities.SideBetType]string{
+	entities.SideBetPerfectPairs:       "完美对子",
+	entities.SideBetTwentyOnePlusThree: "21+3",
+	entities.SideBetLuckyLadies:        "幸运女士",
+}
+
+// sideBetHandNames 边注牌型中文名称
+var sideBetHandNames = map[string]string{
+	entities.MixedPair.String():                  "混色对子",
+	entities.ColoredPair.String():                "同色对子",
+	entities.PerfectPair.String():                "完美对子",
+	entities.Flush.String():                      "同花",
+	entities.Straight.String():                   "顺子",
+	entities.ThreeOfAKind.String():               "三条",
+	entities.StraightFlush.String():              "同花顺",
+	entities.SuitedTrips.String():                "同花三条",
+	entities.AnyTwenty.String():                  "任意20点",
+	entities.SuitedTwenty.String():               "同花20点",
+	entities.MatchedTwenty.String():              "完全相同20点",
+	entities.QueenOfHeartsPair.String():          "红心Q对",
+	entities.QueenOfHeartsWithBlackjack.String(): "红心Q对+庄家BJ",
+}
+
+// formatSideBetResult 格式化单个边注的结算结果
+func formatSideBetResult(result *dtos.SideBetResultDTO) string {
+	if result.Payout == 0 {
+		return fmt.Sprintf("未中奖，输 %d", result.Amount)
+	}
+	return fmt.Sprintf("%s %d:1，赢 %d", sideBetHandNames[result.Hand], result.Payout, result.NetValue)
+}
+
 // GetResultMessage 获取结果消息
 func GetResultMessage(resultType entities.ResultType) string {
 	switch resultType {
@@ -375,6 +845,22 @@ func GetResultMessage(resultType entities.ResultType) string {
 		return "庄家获胜！"
 	case entities.Push:
 		return "平局！"
+	case entities.PlayerCharlie:
+		return "玩家查理未爆牌，自动获胜！"
+	case entities.PlayerTwentyOne:
+		return "玩家21点必胜！"
+	case entities.PlayerSurrender:
+		return "玩家投降，退回部分注码"
+	case entities.Dealer22Push:
+		return "庄家22点，平局"
+	case entities.PlayerBonus:
+		return "玩家21点奖励牌型，按奖励赔率获胜！"
+	case entities.PlayerPontoon:
+		return "玩家Pontoon，赔率2:1！"
+	case entities.DealerPontoon:
+		return "庄家Pontoon，通吃！"
+	case entities.PlayerFiveCardTrick:
+		return "玩家五张牌型(Five Card Trick)，赔率2:1！"
 	default:
 		return "未知结果"
 	}
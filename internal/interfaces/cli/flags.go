@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// RegisterRuleFlags 注册牌桌规则相关的命令行参数，须用ParseRuleFlags解析
+func RegisterRuleFlags(fs *flag.FlagSet, rules *entities.Rules) {
+	fs.Func("variant", "游戏变体: standard(经典) / spanish21(西班牙21点) / switch(换牌21点) / free-bet(免费下注21点) / double-exposure(双明牌21点) / pontoon(英式Pontoon)，载入该变体的标准规则，其他规则参数在其基础上调整 (默认 "+
+		rules.Variant.String()+")", func(value string) error {
+		variant, err := entities.ParseGameVariant(value)
+		rules.Variant = variant
+		return err
+	})
+	fs.IntVar(&rules.DeckCount, "decks", rules.DeckCount, "牌副数")
+	fs.Float64Var(&rules.BlackjackPayout, "bj-payout", rules.BlackjackPayout, "Blackjack赔率(3:2为1.5，6:5为1.2)")
+	fs.Func("hole-card", "庄家底牌规则: peek(美式偷看) / enhc(欧式无底牌) / enhc-obo(欧式，只输原始注码) (默认 "+
+		rules.HoleCard.String()+")", func(value string) error {
+		holeCard, err := entities.ParseHoleCardRule(value)
+		rules.HoleCard = holeCard
+		return err
+	})
+	fs.BoolVar(&rules.DealerHitsSoft17, "h17", rules.DealerHitsSoft17, "庄家软17要牌(默认软17停牌)")
+	fs.Func("double", "加倍点数限制: any(任意点数) / 9-11 / 10-11 (默认 "+rules.DoubleRestriction.String()+")",
+		func(value string) error {
+			restriction, err := entities.ParseDoubleRestriction(value)
+			rules.DoubleRestriction = restriction
+			return err
+		})
+	fs.BoolVar(&rules.DoubleAfterSplit, "das", rules.DoubleAfterSplit, "分牌后可加倍")
+	fs.BoolVar(&rules.DoubleAnyCards, "double-any-cards", rules.DoubleAnyCards, "任意张数时可加倍(默认只有前两张牌)")
+	fs.BoolVar(&rules.ResplitAces, "rsa", rules.ResplitAces, "分A后补到A可再分牌")
+	fs.BoolVar(&rules.HitSplitAces, "hit-split-aces", rules.HitSplitAces, "分A后可继续要牌与加倍(默认只补一张)")
+	fs.BoolVar(&rules.DoubleForLess, "double-for-less", rules.DoubleForLess, "加倍时可追加少于原注的金额")
+	fs.IntVar(&rules.CharlieCards, "charlie", rules.CharlieCards, "拿到该张数未爆牌自动获胜，如5为五张查理(0为不启用)")
+	fs.BoolVar(&rules.Player21AlwaysWins, "player-21-wins", rules.Player21AlwaysWins, "玩家21点自动获胜，Blackjack也胜过庄家Blackjack")
+	fs.BoolVar(&rules.SpanishDeck, "spanish-deck", rules.SpanishDeck, "使用去掉10点数字牌的西班牙牌(每副48张)")
+	fs.BoolVar(&rules.BonusPayouts, "bonus-payouts", rules.BonusPayouts, "西班牙21点奖励赔付(5张及以上21点、6-7-8、7-7-7)")
+	fs.BoolVar(&rules.LateSurrender, "late-surrender", rules.LateSurrender, "前两张牌时可投降，输一半注码")
+	fs.BoolVar(&rules.DoubleDownRescue, "rescue", rules.DoubleDownRescue, "加倍后可投降，只输原始注码(加倍救援)")
+	fs.BoolVar(&rules.SwitchHands, "switch", rules.SwitchHands, "每局两手牌，可交换两手的第二张牌(换牌后A+10只算21点)")
+	fs.BoolVar(&rules.Dealer22Push, "dealer-22-push", rules.Dealer22Push, "庄家22点时未爆牌的玩家平局(Blackjack除外)")
+	fs.BoolVar(&rules.FreeDoubles, "free-doubles", rules.FreeDoubles, "两张牌硬9-11点免费加倍")
+	fs.BoolVar(&rules.FreeSplits, "free-splits", rules.FreeSplits, "10点牌以外的对子免费分牌")
+	fs.BoolVar(&rules.DoubleExposure, "double-exposure", rules.DoubleExposure, "庄家两张牌都明牌，平局庄家赢(玩家Blackjack除外)")
+	fs.BoolVar(&rules.Pontoon, "pontoon", rules.Pontoon, "Pontoon规则: 庄家两张暗牌，平局庄家赢，15点起才能停牌，五张牌型赔2:1")
+	fs.BoolVar(&rules.ContinuousShuffle, "csm", rules.ContinuousShuffle, "连续洗牌机: 每局结算后弃牌随机插回牌靴，算牌无效")
+	fs.Func("shuffle", "换牌靴时的洗牌方式: random(理想洗牌) / riffle(鸽尾) / strip(切条) / casino(赌场流程) (默认 "+
+		rules.ShuffleModel.String()+")", func(value string) error {
+		model, err := entities.ParseShuffleModel(value)
+		rules.ShuffleModel = model
+		return err
+	})
+	fs.Float64Var(&rules.ShuffleImperfection, "shuffle-imperfection", rules.ShuffleImperfection,
+		"人工洗牌的不完美程度(0-1): 越大鸽尾落牌越成团、切条越整齐")
+	fs.Func("rng", "洗牌随机源: pcg(可用种子重现) / crypto(系统安全随机数，无法按开局时间预测) (默认 "+
+		rules.RandomSource.String()+")", func(value string) error {
+		source, err := entities.ParseRandomSource(value)
+		rules.RandomSource = source
+		return err
+	})
+	fs.Uint64Var(&rules.ShuffleSeed, "shuffle-seed", rules.ShuffleSeed, "pcg随机源的种子(0为当前时间)，固定种子可重现牌靴与模拟")
+	fs.BoolVar(&rules.BurnCard, "burn", rules.BurnCard, "每次洗牌后烧掉第一张牌(牌面朝下，玩家看不到)")
+	fs.BoolVar(&rules.ProvablyFair, "provably-fair", rules.ProvablyFair,
+		"可验证公平洗牌: 下注前公布牌靴承诺，牌序由服务器种子与客户端种子决定，牌靴结束时公开服务器种子")
+	fs.IntVar(&rules.MinBet, "min-bet", rules.MinBet, "牌桌最低下注")
+	fs.IntVar(&rules.MaxBet, "max-bet", rules.MaxBet, "牌桌最高下注(0为不限)")
+	fs.IntVar(&rules.ChipDenomination, "chip", rules.ChipDenomination, "最小筹码面额，下注须为其整数倍")
+}
+
+// ParseRuleFlags 解析命令行参数并校验规则：无论-variant出现在什么位置，都先载入变体的标准规则，
+// 其他规则参数再覆盖在变体规则之上
+func ParseRuleFlags(fs *flag.FlagSet, rules *entities.Rules, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	variantSet := false
+	fs.Visit(func(f *flag.Flag) {
+		variantSet = variantSet || f.Name == "variant"
+	})
+	if variantSet {
+		// 载入变体规则后重新解析，其他规则参数覆盖变体规则
+		*rules = entities.VariantRules(rules.Variant)
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+	}
+	return rules.Validate()
+}
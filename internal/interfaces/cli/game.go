@@ -1,13 +1,12 @@
 package cli
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
 	"github.com/luffy050596/go-blackjack/internal/application/services"
 	"github.com/luffy050596/go-blackjack/internal/domain/entities"
 )
@@ -15,17 +14,83 @@ import (
 // GameHandler 游戏命令行处理器
 type GameHandler struct {
 	gameService *services.GameApplicationService
-	scanner     *bufio.Scanner
-	display     *DisplayService
+	display     Renderer
+	sideBets    bool // 下注阶段是否询问边注
+
+	strategySource    services.StrategySource // 推荐操作的来源
+	infiniteReference bool                    // 概率分析是否附带无限副牌参考
+
+	gameOptions    []entities.GameOption
+	fairHistory    string // 公开的可验证公平牌靴追加写入的历史文件
+	fairRevealed   int    // 已显示公开的牌靴数
+	fairCommitment string // 最近显示的下一个牌靴的承诺
+}
+
+// GameHandlerOption is a function type for configuring the game handler
+type GameHandlerOption func(handler *GameHandler)
+
+// WithRenderer configures the renderer used to draw the game
+func WithRenderer(renderer Renderer) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.display = renderer
+	}
+}
+
+// WithRules configures the table rules used by the game service
+func WithRules(rules entities.Rules) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.gameOptions = append(handler.gameOptions, entities.WithRules(rules))
+	}
+}
+
+// WithScenario configures the game to deal a scenario's stacked cards with its rules and bankroll
+func WithScenario(scenario *entities.Scenario) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.gameOptions = append(handler.gameOptions, entities.WithScenario(scenario))
+	}
+}
+
+// WithFairHistory configures the file that revealed provably fair shoes are appended to
+func WithFairHistory(path string) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.fairHistory = path
+	}
+}
+
+// WithStrategySource configures where the recommended action in the probability analysis comes from
+func WithStrategySource(source services.StrategySource) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.strategySource = source
+	}
+}
+
+// WithInfiniteDeckReference configures whether the probability analysis compares the current shoe with an infinite deck
+func WithInfiniteDeckReference(enabled bool) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.infiniteReference = enabled
+	}
+}
+
+// WithSideBets configures whether side bets are offered in the betting phase
+func WithSideBets(enabled bool) GameHandlerOption {
+	return func(handler *GameHandler) {
+		handler.sideBets = enabled
+	}
 }
 
 // NewGameHandler 创建游戏处理器
-func NewGameHandler() *GameHandler {
+func NewGameHandler(options ...GameHandlerOption) *GameHandler {
 	handler := &GameHandler{
-		gameService: services.NewGameApplicationService("玩家"),
-		scanner:     bufio.NewScanner(os.Stdin),
-		display:     NewDisplayService(),
+		display: NewDisplayService(),
+	}
+
+	for _, option := range options {
+		option(handler)
 	}
+	handler.gameService = services.NewGameApplicationService("玩家", handler.gameOptions...)
+	handler.gameService.SetStrategySource(handler.strategySource)
+	handler.gameService.SetInfiniteDeckReference(handler.infiniteReference)
+
 	return handler
 }
 
@@ -46,7 +111,18 @@ func (h *GameHandler) Run() {
 				h.display.ShowError(fmt.Sprintf("游戏错误: %v", err))
 			}
 		case MenuOptionRules:
-			h.display.ShowRules()
+			h.display.ShowRules(h.gameService.GetRules())
+		case MenuOptionTraining:
+			if err := h.playTrainingGame(); err != nil {
+				if errors.Is(err, ErrorQuit) {
+					return
+				}
+				h.display.ShowError(fmt.Sprintf("游戏错误: %v", err))
+			}
+		case MenuOptionDrill:
+			h.runStrategyDrill()
+		case MenuOptionCounting:
+			h.runCountingDrill()
 		case MenuOptionExit:
 			h.display.ShowGoodbye()
 			return
@@ -58,6 +134,13 @@ func (h *GameHandler) Run() {
 
 // playGame 游戏主循环
 func (h *GameHandler) playGame() error {
+	// 离开牌桌时公开当前可验证公平牌靴
+	defer func() {
+		if h.gameService.RevealFairShoe() != nil {
+			h.showFairReveals()
+		}
+	}()
+
 	for !h.gameService.IsGameOver() {
 		if err := h.playRound(); err != nil {
 			h.display.ShowError(fmt.Sprintf("游戏错误: %v", err))
@@ -78,6 +161,11 @@ func (h *GameHandler) playGame() error {
 
 // playRound 单轮游戏
 func (h *GameHandler) playRound() error {
+	// 可验证公平模式下新牌靴开始前先公布承诺，由玩家设置客户端种子
+	if err := h.showFairShoes(); err != nil {
+		return err
+	}
+
 	// 开始新一轮
 	if err := h.gameService.StartNewRound(); err != nil {
 		return err
@@ -85,6 +173,9 @@ func (h *GameHandler) playRound() error {
 
 	gameState := h.gameService.GetGameState()
 	h.display.ShowRoundStart(gameState.RoundNumber, gameState.PlayerChips)
+	if err := h.showFairShoes(); err != nil {
+		return err
+	}
 
 	// 下注阶段
 	if !h.handleBetting() {
@@ -96,8 +187,15 @@ func (h *GameHandler) playRound() error {
 		return err
 	}
 
-	// 玩家回合
-	if err := h.handlePlayerTurn(); err != nil {
+	// 边注在发牌后立即结算
+	if results := h.gameService.GetSideBetResults(); len(results) > 0 {
+		h.display.ShowSideBetResults(results)
+	}
+
+	// 庄家偷看到Blackjack时跳过玩家回合，否则进入玩家回合
+	if h.gameService.DealerPeekedBlackjack() {
+		h.display.ShowDealerPeekBlackjack()
+	} else if err := h.handlePlayerTurn(); err != nil {
 		return err
 	}
 
@@ -131,29 +229,60 @@ func (h *GameHandler) handleBetting() bool {
 	h.display.ShowKellyBettingRecommendation(kellyRecommendation)
 
 	for {
-		input := h.getInput("请选择下注金额 (输入选项编号或 'q' 退出): ")
+		input := h.getInput("请输入下注金额 (k 凯利建议, r 重复, 2x 翻倍, c 牌靴组成, q 退出): ")
 
-		if strings.ToLower(input) == entities.InputQuit {
+		switch strings.ToLower(input) {
+		case entities.InputQuit:
 			return false
+		case entities.InputShoe:
+			h.display.ShowShoeComposition(h.gameService.GetShoeComposition())
+			continue
 		}
 
-		choice, err := strconv.Atoi(input)
-		if err != nil || choice < 1 || choice > len(betOptions) {
-			h.display.ShowError("请输入有效的选项编号")
+		betAmount, err := ParseBetInput(input, betOptions.LastBet, kellyRecommendation.RecommendedBetAmount)
+		if err != nil {
+			h.display.ShowError(fmt.Sprintf("下注失败: %v", err))
 			continue
 		}
 
-		betAmount := betOptions[choice-1]
 		if err := h.gameService.PlaceBet(betAmount); err != nil {
 			h.display.ShowError(fmt.Sprintf("下注失败: %v", err))
 			continue
 		}
 
 		h.display.ShowBetSuccess(betAmount)
+		if h.sideBets {
+			h.handleSideBets()
+		}
 		return true
 	}
 }
 
+// handleSideBets 依次询问各边注金额，直接回车跳过
+func (h *GameHandler) handleSideBets() {
+	evs := h.gameService.GetSideBetEVs()
+	h.display.ShowSideBetOptions(evs)
+
+	for _, ev := range evs {
+		for {
+			input := h.getInput(fmt.Sprintf("%s 边注金额 (回车跳过): ", sideBetNames[ev.Type]))
+			if input == "" {
+				break
+			}
+
+			amount, err := strconv.Atoi(input)
+			if err == nil {
+				err = h.gameService.PlaceSideBet(ev.Type, amount)
+			}
+			if err != nil {
+				h.display.ShowError(fmt.Sprintf("边注失败: %v", err))
+				continue
+			}
+			break
+		}
+	}
+}
+
 // ErrorQuit 退出游戏的标记
 var ErrorQuit = errors.New("quit")
 
@@ -169,28 +298,39 @@ func (h *GameHandler) handlePlayerTurn() error {
 	}()
 
 	for {
+		// 双明牌规则下庄家两张牌都不隐藏
+		rules := h.gameService.GetRules()
 		gameState := h.gameService.GetGameState()
-		h.display.ShowGameState(gameState, true)
+		h.display.ShowGameState(gameState, !rules.DoubleExposure)
+
+		// 所有手牌都已无需行动（如Blackjack）时直接进入庄家回合
+		if gameState.State != entities.StatePlayerTurn {
+			switch {
+			case gameState.PlayerHand.Value == 21 && len(gameState.PlayerHand.Cards) == 2:
+				h.display.ShowBlackjack()
+			case gameState.PlayerHand.Value > 21:
+				h.display.ShowPlayerBust()
+			}
+			break
+		}
 
 		// 显示获胜概率
 		probabilities := h.gameService.CalculateWinProbabilities()
 		h.display.ShowProbabilities(probabilities)
 
-		// 检查21点
-		if gameState.PlayerHand.Value == 21 && len(gameState.PlayerHand.Cards) == 2 {
-			h.display.ShowBlackjack()
-			break
-		}
-
-		// 检查爆牌
-		if gameState.PlayerHand.Value > 21 {
-			h.display.ShowPlayerBust()
-			break
-		}
-
 		// 获取玩家输入
-		prompt := h.display.buildPlayerPrompt(WithDoubleDown(h.gameService.CanPlayerDoubleDown()))
-		input := h.getInput(prompt)
+		input := h.display.ReadAction(
+			WithHit(h.gameService.CanPlayerHit()),
+			WithStand(h.gameService.CanPlayerStand()),
+			WithPontoon(rules.Pontoon),
+			WithDoubleDown(h.gameService.CanPlayerDoubleDown()),
+			WithDoubleForLess(rules.DoubleForLess),
+			WithFreeDouble(h.gameService.IsFreeDouble()),
+			WithSplit(h.gameService.CanPlayerSplit()),
+			WithFreeSplit(h.gameService.IsFreeSplit()),
+			WithSurrender(h.gameService.CanPlayerSurrender()),
+			WithSwitch(h.gameService.CanPlayerSwitch()),
+		)
 
 		// 处理玩家行动
 		action := ParsePlayerInput(input)
@@ -199,13 +339,48 @@ func (h *GameHandler) handlePlayerTurn() error {
 			continue
 		}
 
-		result, err := h.gameService.ProcessPlayerAction(action)
+		if action == entities.ActionDoubleDown && !h.gameService.CanPlayerDoubleDown() {
+			h.display.ShowError("当前手牌不能加倍")
+			continue
+		}
+
+		if action == entities.ActionHit && !h.gameService.CanPlayerHit() {
+			h.display.ShowError("加倍后不能再要牌")
+			continue
+		}
+
+		if action == entities.ActionStand && !h.gameService.CanPlayerStand() {
+			h.display.ShowError(fmt.Sprintf("不到%d点不能停牌", entities.PontoonMinStick))
+			continue
+		}
+
+		if action == entities.ActionSurrender && !h.gameService.CanPlayerSurrender() {
+			h.display.ShowError("当前不能投降")
+			continue
+		}
+
+		if action == entities.ActionSplit && !h.gameService.CanPlayerSplit() {
+			h.display.ShowError("当前手牌不能分牌")
+			continue
+		}
+
+		if action == entities.ActionSwitch && !h.gameService.CanPlayerSwitch() {
+			h.display.ShowError("只能在第一手行动之前换牌")
+			continue
+		}
+
+		result, err := h.processAction(action, rules)
 		if err != nil {
+			if action == entities.ActionDoubleDown {
+				h.display.ShowError(err.Error())
+				continue
+			}
 			return err
 		}
 
 		// 显示行动结果
 		h.display.ShowActionResult(result)
+		h.display.ShowDecisionFeedback(result.Feedback)
 
 		if result.Action == entities.ActionQuit {
 			shouldStartDealerTurn = false
@@ -220,6 +395,16 @@ func (h *GameHandler) handlePlayerTurn() error {
 	return nil
 }
 
+// processAction 执行玩家行动，允许少加倍时先询问追加金额
+func (h *GameHandler) processAction(action entities.PlayerAction, rules entities.Rules) (*dtos.ActionResultDTO, error) {
+	if action != entities.ActionDoubleDown || !rules.DoubleForLess || h.gameService.IsFreeDouble() {
+		return h.gameService.ProcessPlayerAction(action)
+	}
+
+	amount := h.readIntWithDefault("加倍追加金额", h.gameService.MaxDoubleAmount())
+	return h.gameService.ProcessDoubleDown(amount)
+}
+
 // handleDealerTurn 处理庄家回合
 func (h *GameHandler) handleDealerTurn() error {
 	h.display.ShowDealerTurnStart()
@@ -236,9 +421,7 @@ func (h *GameHandler) handleDealerTurn() error {
 
 // getInput 获取用户输入
 func (h *GameHandler) getInput(prompt string) string {
-	fmt.Print(prompt)
-	h.scanner.Scan()
-	return strings.TrimSpace(h.scanner.Text())
+	return h.display.ReadInput(prompt)
 }
 
 // askPlayAgain 询问是否继续游戏
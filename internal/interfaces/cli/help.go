@@ -1,57 +1,221 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
 
 // ShowRules 显示游戏规则
-func (d *DisplayService) ShowRules() {
-	fmt.Println("=== 二十一点游戏规则 ===")
-	fmt.Println()
-	fmt.Println("🎯 游戏目标:")
-	fmt.Println("   让手中牌的点数尽可能接近21点，但不能超过21点")
-	fmt.Println("   点数比庄家更接近21点就获胜")
-	fmt.Println()
-	fmt.Println("🃏 牌面点数:")
-	fmt.Println("   • 数字牌(2-10): 按牌面数字计算")
-	fmt.Println("   • 花牌(J,Q,K): 每张都是10点")
-	fmt.Println("   • A: 可以是1点或11点(自动选择最优)")
-	fmt.Println()
-	fmt.Println("💰 下注系统:")
-	fmt.Println("   • 初始筹码: 1000")
-	fmt.Println("   • 下注选项: 10, 25, 50, 100, 200 筹码")
-	fmt.Println("   • 筹码不足时可选择全押")
-	fmt.Println("   • 普通获胜: 1:1 赔率")
-	fmt.Println("   • Blackjack获胜: 3:2 赔率(非加倍)")
-	fmt.Println("   • 平局: 返还下注金额")
-	fmt.Println("   • 筹码用完可选择重新开始")
-	fmt.Println()
-	fmt.Println("🎮 游戏流程:")
-	fmt.Println("   1. 选择下注金额(从预设选项中选择)")
-	fmt.Println("   2. 玩家和庄家各发2张牌")
-	fmt.Println("   3. 玩家选择要牌(h)、停牌(s)或加倍(d)")
-	fmt.Println("   4. 庄家小于17点必须要牌，17点以上必须停牌")
-	fmt.Println("   5. 比较点数决定胜负并结算筹码")
-	fmt.Println()
-	fmt.Println("🎮 操作命令:")
-	fmt.Println("   • h/hit: 要牌")
-	fmt.Println("   • s/stand: 停牌")
-	fmt.Println("   • d/double/doubledown: 加倍(仅前两张牌时可用)")
-	fmt.Println("   • q/quit: 退出游戏")
+func (d *DisplayService) ShowRules(rules entities.Rules) {
+	for _, line := range rulesText(rules) {
+		fmt.Println(line)
+	}
 	fmt.Println()
-	fmt.Println("⚡ 加倍功能:")
-	fmt.Println("   • 只能在拿到前两张牌时使用")
-	fmt.Println("   • 下注金额翻倍，需要足够筹码")
-	fmt.Println("   • 加倍后只能再拿一张牌，然后必须停牌")
-	fmt.Println("   • 加倍后的Blackjack按1:1赔率计算")
-	fmt.Println()
-	fmt.Println("🏆 特殊情况:")
-	fmt.Println("   • Blackjack: 前两张牌就是21点(A+10点牌)")
-	fmt.Println("   • 爆牌: 点数超过21点立即失败")
-	fmt.Println("   • 平局: 双方点数相同")
-	fmt.Println()
-	fmt.Print("按回车键继续...")
 
 	// 等待用户输入
-	_, _ = fmt.Scanln()
+	d.ReadInput("按回车键继续...")
 
 	d.clearScreen()
 }
+
+// rulesText 按当前牌桌规则生成游戏规则文本（供各渲染器共用）
+func rulesText(rules entities.Rules) []string {
+	betLimits := fmt.Sprintf("%d 起", rules.MinBet)
+	if rules.MaxBet > 0 {
+		betLimits = fmt.Sprintf("%d - %d", rules.MinBet, rules.MaxBet)
+	}
+
+	dealerRule := "   4. 庄家小于17点必须要牌，17点以上必须停牌"
+	if rules.DealerHitsSoft17 {
+		dealerRule = "   4. 庄家小于17点或软17必须要牌，硬17及以上停牌"
+	}
+
+	holeCardRule := "   • 庄家明牌为A或10点时先检查底牌，Blackjack则直接结算"
+	switch rules.HoleCard {
+	case entities.HoleCardENHC:
+		holeCardRule = "   • 庄家玩家行动后才发底牌，庄家Blackjack赢走全部注码"
+	case entities.HoleCardENHCOBO:
+		holeCardRule = "   • 庄家玩家行动后才发底牌，庄家Blackjack只赢原始注码"
+	}
+
+	shuffleRule := fmt.Sprintf("   • 洗牌: 牌靴每副剩余不足%d张时换新牌靴", entities.CutCardReserve)
+	if rules.ShuffleModel != entities.ShuffleRandom {
+		shuffleRule += fmt.Sprintf("，按 %s 方式洗牌(不完美程度 %.2f)", rules.ShuffleModel, rules.ShuffleImperfection)
+	}
+	if rules.BurnCard {
+		shuffleRule += "，洗牌后烧掉第一张牌"
+	}
+	if rules.ContinuousShuffle {
+		shuffleRule = "   • 连续洗牌机: 每局结算后弃牌随机插回牌靴"
+	}
+	if rules.RandomSource == entities.RandomCrypto {
+		shuffleRule += "，随机数取自系统安全随机源"
+	}
+	if rules.ProvablyFair {
+		shuffleRule = fmt.Sprintf("   • 可验证公平: 牌靴每副剩余不足%d张时换新牌靴，下注前公布承诺，牌靴结束时公开服务器种子",
+			entities.CutCardReserve)
+		if rules.BurnCard {
+			shuffleRule += "，洗牌后烧掉第一张牌"
+		}
+	}
+
+	doubleCards := "   • 只能在拿到前两张牌时使用"
+	if rules.DoubleAnyCards {
+		doubleCards = "   • 任意张数时都可使用"
+	}
+	doubleTotals := "   • 任意点数都可加倍"
+	if rules.DoubleRestriction != entities.DoubleAnyTotal {
+		doubleTotals = fmt.Sprintf("   • 只有 %s 点可以加倍", rules.DoubleRestriction)
+	}
+	doubleAmount := "   • 下注金额翻倍，需要足够筹码"
+	if rules.DoubleForLess {
+		doubleAmount = "   • 追加金额不超过原注，可以少于原注(少加倍)"
+	}
+
+	splitRule := "   • p/split: 分牌(两张同点数的牌，最多分成4手，分A只补一张且不能再分)"
+	switch {
+	case rules.ResplitAces && rules.HitSplitAces:
+		splitRule = "   • p/split: 分牌(两张同点数的牌，最多分成4手，可再分A，分A后可要牌与加倍)"
+	case rules.ResplitAces:
+		splitRule = "   • p/split: 分牌(两张同点数的牌，最多分成4手，分A只补一张，补到A可再分)"
+	case rules.HitSplitAces:
+		splitRule = "   • p/split: 分牌(两张同点数的牌，最多分成4手，A不能再分，分A后可要牌与加倍)"
+	}
+
+	var autoWinRules []string
+	if rules.CharlieCards > 0 {
+		autoWinRules = append(autoWinRules, fmt.Sprintf("   • %d张查理: 拿到%d张牌未爆牌自动获胜", rules.CharlieCards, rules.CharlieCards))
+	}
+	if rules.Player21AlwaysWins {
+		autoWinRules = append(autoWinRules, "   • 21点必胜: 21点自动获胜，Blackjack也胜过庄家Blackjack")
+	}
+	if rules.SpanishDeck {
+		autoWinRules = append(autoWinRules, "   • 西班牙牌: 每副去掉4张10点数字牌(48张)，J、Q、K保留")
+	}
+
+	var surrenderRules []string
+	if rules.LateSurrender {
+		surrenderRules = append(surrenderRules, "   • 后投降: 前两张牌时可投降，输一半注码")
+	}
+	if rules.DoubleDownRescue {
+		surrenderRules = append(surrenderRules, "   • 加倍救援: 加倍后未爆牌可投降，只输原始注码")
+	}
+	if len(surrenderRules) > 0 {
+		surrenderRules = append([]string{"", "🏳️ 投降:"}, surrenderRules...)
+	}
+
+	var bonusRules []string
+	if rules.BonusPayouts {
+		bonusRules = []string{
+			"",
+			"🎁 21点奖励(加倍后不适用):",
+			"   • 5张21点 3:2，6张 2:1，7张及以上 3:1",
+			"   • 6-7-8或7-7-7: 杂色 3:2，同花 2:1，黑桃 3:1",
+		}
+	}
+
+	var variantRules []string
+	if rules.SwitchHands {
+		variantRules = append(variantRules,
+			"   • 换牌: 每局下两手相同注码，第一手行动前可交换两手的第二张牌",
+			"   • 换牌后的A+10只算21点，不算Blackjack")
+	}
+	if rules.FreeDoubles {
+		variantRules = append(variantRules, "   • 免费加倍: 两张牌硬9-11点加倍时追加注码由庄家出资")
+	}
+	if rules.FreeSplits {
+		variantRules = append(variantRules, "   • 免费分牌: 10点牌以外的对子分牌时新一手的注码由庄家出资")
+	}
+	if rules.FreeDoubles || rules.FreeSplits {
+		variantRules = append(variantRules, "   • 免费注码赢了照常赔付，输了不收回")
+	}
+	if rules.DoubleExposure {
+		variantRules = append(variantRules,
+			"   • 双明牌: 庄家两张牌都明牌发出",
+			"   • 平局庄家赢，只有玩家Blackjack胜过庄家Blackjack")
+	}
+	if rules.Pontoon {
+		variantRules = append(variantRules,
+			"   • Pontoon: 庄家两张牌都是暗牌，庄家Pontoon(A+10)通吃",
+			"   • t/twist要牌，s/stick停牌(须达到15点)，b/buy买牌(加注不超过原注后要牌，可继续要牌)",
+			"   • 玩家Pontoon与五张牌型(Five Card Trick)赔2:1，平局庄家赢")
+	}
+	if rules.Dealer22Push {
+		variantRules = append(variantRules, "   • 庄家22点: 未爆牌的玩家平局，Blackjack仍然获胜")
+	}
+	if len(variantRules) > 0 {
+		variantRules = append([]string{"", "🔀 变体规则:"}, variantRules...)
+	}
+
+	title := "=== 二十一点游戏规则 ==="
+	switch rules.Variant {
+	case entities.VariantSpanish21:
+		title = "=== 西班牙21点游戏规则 ==="
+	case entities.VariantSwitch:
+		title = "=== 换牌21点游戏规则 ==="
+	case entities.VariantFreeBet:
+		title = "=== 免费下注21点游戏规则 ==="
+	case entities.VariantDoubleExposure:
+		title = "=== 双明牌21点游戏规则 ==="
+	case entities.VariantPontoon:
+		title = "=== Pontoon游戏规则 ==="
+	}
+
+	lines := []string{
+		title,
+		"",
+		"🎯 游戏目标:",
+		"   让手中牌的点数尽可能接近21点，但不能超过21点",
+		"   点数比庄家更接近21点就获胜",
+		"",
+		"🃏 牌面点数:",
+		"   • 数字牌(2-10): 按牌面数字计算",
+		"   • 花牌(J,Q,K): 每张都是10点",
+		"   • A: 可以是1点或11点(自动选择最优)",
+		"",
+		"💰 下注系统:",
+		"   • 初始筹码: 1000",
+		fmt.Sprintf("   • 牌桌限额: %s，须为 %d 的整数倍", betLimits, rules.ChipDenomination),
+		"   • k: 凯利建议，r: 重复上次下注，2x: 上次下注翻倍，c: 查看牌靴组成",
+		"   • 普通获胜: 1:1 赔率",
+		fmt.Sprintf("   • Blackjack获胜: %g:1 赔率(非加倍)", rules.BlackjackPayout),
+		"   • 平局: 返还下注金额",
+		"   • 筹码低于最低下注时游戏结束",
+		"",
+		"🎮 游戏流程:",
+		fmt.Sprintf("   1. 输入下注金额(%d 副牌)", rules.DeckCount),
+		"   2. 玩家和庄家各发2张牌",
+		"   3. 玩家选择要牌(h)、停牌(s)或加倍(d)",
+		dealerRule,
+		"   5. 比较点数决定胜负并结算筹码",
+		"",
+		"🎮 操作命令:",
+		"   • h/hit: 要牌",
+		"   • s/stand: 停牌",
+		"   • d/double/doubledown: 加倍",
+		splitRule,
+		"   • u/surrender: 投降(规则允许时)",
+		"   • w/switch: 换牌(换牌玩法)",
+		"   • t/twist、stick、b/buy: 要牌、停牌、买牌(Pontoon)",
+		"   • q/quit: 退出游戏",
+		"",
+		"⚡ 加倍功能:",
+		doubleCards,
+		doubleTotals,
+		doubleAmount,
+		"   • 加倍后只能再拿一张牌，然后必须停牌",
+		"   • 加倍后的Blackjack按1:1赔率计算",
+		"",
+		"🏆 特殊情况:",
+		"   • Blackjack: 前两张牌就是21点(A+10点牌)",
+		holeCardRule,
+		shuffleRule,
+		"   • 爆牌: 点数超过21点立即失败",
+		"   • 平局: 双方点数相同",
+	}
+	lines = append(lines, autoWinRules...)
+	lines = append(lines, surrenderRules...)
+	lines = append(lines, variantRules...)
+	return append(lines, bonusRules...)
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runHouseEdgeCommand 赌场优势计算子命令
+func runHouseEdgeCommand(args []string, out io.Writer) error {
+	rules := entities.DefaultRules()
+
+	fs := flag.NewFlagSet("house-edge", flag.ContinueOnError)
+	fs.SetOutput(out)
+	RegisterRuleFlags(fs, &rules)
+	if err := ParseRuleFlags(fs, &rules, args); err != nil {
+		return err
+	}
+
+	writeHouseEdge(out, services.NewHouseEdgeService().Calculate(rules))
+	return nil
+}
+
+// writeHouseEdge 输出赌场优势计算结果
+func writeHouseEdge(out io.Writer, result *dtos.HouseEdgeDTO) {
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintln(out, "🏦 赌场优势计算 (首手，最优基本策略)")
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "   参考规则期望: %+.3f%%\n", result.ReferenceEV*100)
+	for _, contribution := range result.Breakdown {
+		fmt.Fprintf(out, "   %s %+.3f%%\n", padRight(contribution.Description, 22), contribution.EVChange*100)
+	}
+	fmt.Fprintf(out, "📊 玩家期望: %+.3f%%\n", result.PlayerEV*100)
+	fmt.Fprintf(out, "🏦 赌场优势: %.3f%%\n", result.HouseEdge*100)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+}
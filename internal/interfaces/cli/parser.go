@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/luffy050596/go-blackjack/internal/domain/entities"
@@ -9,15 +12,47 @@ import (
 // ParsePlayerInput 解析玩家输入
 func ParsePlayerInput(input string) entities.PlayerAction {
 	switch strings.ToLower(strings.TrimSpace(input)) {
-	case entities.InputHit, entities.InputHitFull:
+	case entities.InputHit, entities.InputHitFull, entities.InputTwist, entities.InputTwistFull:
 		return entities.ActionHit
-	case entities.InputStand, entities.InputStandFull:
+	case entities.InputStand, entities.InputStandFull, entities.InputStickFull:
 		return entities.ActionStand
-	case entities.InputDouble, entities.InputDoubleFull, entities.InputDoubleDown:
+	case entities.InputDouble, entities.InputDoubleFull, entities.InputDoubleDown, entities.InputBuy, entities.InputBuyFull:
 		return entities.ActionDoubleDown
+	case entities.InputSplit, entities.InputSplitFull:
+		return entities.ActionSplit
+	case entities.InputSurrender, entities.InputSurrenderFull:
+		return entities.ActionSurrender
+	case entities.InputSwitch, entities.InputSwitchFull:
+		return entities.ActionSwitch
 	case entities.InputQuit, entities.InputQuitFull:
 		return entities.ActionQuit
 	default:
 		return entities.ActionInvalid
 	}
 }
+
+// ParseBetInput 解析下注输入：金额、k（凯利建议）、r（重复上次下注）、2x（上次下注翻倍）
+func ParseBetInput(input string, lastBet, kellyBet int) (int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch input {
+	case entities.InputKellyBet:
+		if kellyBet <= 0 {
+			return 0, errors.New("no Kelly recommendation available")
+		}
+		return kellyBet, nil
+	case entities.InputRepeatBet, entities.InputDoubleBet:
+		if lastBet <= 0 {
+			return 0, errors.New("no previous bet to repeat")
+		}
+		if input == entities.InputDoubleBet {
+			return 2 * lastBet, nil
+		}
+		return lastBet, nil
+	}
+
+	amount, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bet amount %q", input)
+	}
+	return amount, nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+)
+
+// showFairShoes 显示已结束牌靴公开的服务器种子并写入历史文件；为下一个牌靴公布新承诺时，
+// 由玩家设置或更换客户端种子，承诺公布在客户端种子确定之前
+func (h *GameHandler) showFairShoes() error {
+	h.showFairReveals()
+
+	commitment := h.gameService.GetFairCommitment()
+	if commitment == nil || commitment.Commitment == h.fairCommitment {
+		return nil
+	}
+	h.fairCommitment = commitment.Commitment
+	h.display.ShowFairCommitment(commitment)
+	return h.askClientSeed(commitment.ClientSeed)
+}
+
+// showFairReveals 显示已结束牌靴公开的服务器种子并写入历史文件
+func (h *GameHandler) showFairReveals() {
+	history := h.gameService.GetFairHistory()
+	for _, fair := range history[min(h.fairRevealed, len(history)):] {
+		h.display.ShowFairReveal(fair)
+		if err := appendFairHistory(h.fairHistory, fair); err != nil {
+			h.display.ShowError(fmt.Sprintf("写入牌靴历史失败: %v", err))
+		}
+	}
+	h.fairRevealed = len(history)
+}
+
+// askClientSeed 看到承诺后输入客户端种子，已有种子时直接回车保留
+func (h *GameHandler) askClientSeed(current string) error {
+	prompt := "请输入客户端种子: "
+	if current != "" {
+		prompt = fmt.Sprintf("更换客户端种子(回车保留 %s): ", current)
+	}
+	seed := h.getInput(prompt)
+	if seed == "" {
+		if current == "" {
+			return errors.New("a client seed is required for provably fair shoes")
+		}
+		return nil
+	}
+	return h.gameService.SetClientSeed(seed)
+}
+
+// appendFairHistory 把公开的牌靴作为一行JSON追加到历史文件，未指定文件时不写
+func appendFairHistory(path string, fair *dtos.FairShoeDTO) error {
+	if path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(fair)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runVerifyCommand 验证子命令：按历史文件逐个重现可验证公平牌靴
+func runVerifyCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(out)
+	path := fs.String("history", "", "牌靴历史文件(游戏时 -fair-history 写入的JSON行)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" && fs.NArg() > 0 {
+		*path = fs.Arg(0)
+	}
+	if *path == "" {
+		return errors.New("history file is required")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "🔍 验证牌靴历史 %s\n", *path)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+
+	total, failed := 0, 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		total++
+
+		var record dtos.FairShoeDTO
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			failed++
+			fmt.Fprintf(out, "   ❌ 第 %d 行: %v\n", total, err)
+			continue
+		}
+		cards, err := services.VerifyFairShoe(&record)
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "   ❌ 牌靴 #%d: %v\n", record.Number, err)
+			continue
+		}
+		fmt.Fprintf(out, "   ✅ 牌靴 #%d: 承诺一致，已发 %d 张与重现的 %d 张牌序一致\n", record.Number, len(record.Dealt), len(cards))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d shoes failed verification", failed, total)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 渲染模式常量
+const (
+	RendererClassic = "classic"
+	RendererTUI     = "tui"
+)
+
+// Renderer 渲染器接口，负责游戏画面输出与玩家输入读取
+type Renderer interface {
+	ShowWelcome()
+	ShowMenu()
+	ShowRules(rules entities.Rules)
+	ShowGoodbye()
+	ShowError(message string)
+	ShowRoundStart(round, chips int)
+	ShowBettingSection(chips int)
+	ShowBetOptions(options *dtos.BetOptionsDTO)
+	ShowBetSuccess(amount int)
+	ShowKellyBettingRecommendation(kelly *dtos.KellyRecommendationDTO)
+	ShowSideBetOptions(evs []*dtos.SideBetEVDTO)
+	ShowSideBetResults(results []*dtos.SideBetResultDTO)
+	ShowShoeComposition(composition *dtos.ShoeCompositionDTO)
+	ShowFairCommitment(fair *dtos.FairShoeDTO)
+	ShowFairReveal(fair *dtos.FairShoeDTO)
+	ShowPlayerTurnStart()
+	ShowDealerTurnStart()
+	ShowGameState(gameState *dtos.GameStateDTO, hideHoleCard bool)
+	ShowProbabilities(probabilities *dtos.ProbabilityResultDTO)
+	ShowBlackjack()
+	ShowDealerPeekBlackjack()
+	ShowPlayerBust()
+	ShowActionResult(result *dtos.ActionResultDTO)
+	ShowGameResult(result *dtos.GameResultDTO)
+	ShowGameOver()
+	ShowDecisionFeedback(feedback *dtos.DecisionFeedbackDTO)
+	ShowTrainingStats(stats *dtos.TrainingStatsDTO)
+	ShowStrategyDrill(drill *dtos.StrategyDrillDTO)
+	ShowCountingDrillStart(drill *dtos.CountingDrillDTO)
+	ShowCountingCard(card *dtos.CountingCardDTO)
+	ShowCountingAnswer(answer *dtos.CountingAnswerDTO)
+	ShowCountingHistory(history []*dtos.CountingSessionDTO)
+
+	// ReadInput 显示提示并读取一行输入
+	ReadInput(prompt string) string
+	// ReadAction 显示玩家可用操作并读取玩家选择
+	ReadAction(options ...PlayerPromptOption) string
+}
+
+// NewRenderer 根据模式名称创建渲染器
+func NewRenderer(mode string) (Renderer, error) {
+	switch mode {
+	case RendererClassic, "":
+		return NewDisplayService(), nil
+	case RendererTUI:
+		return NewTUIRenderer(), nil
+	default:
+		return nil, fmt.Errorf("unknown renderer %q", mode)
+	}
+}
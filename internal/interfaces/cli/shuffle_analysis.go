@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runShuffleAnalysisCommand 洗牌相关性子命令：按规则的牌靴大小比较各洗牌方式残留的牌序
+func runShuffleAnalysisCommand(args []string, out io.Writer) error {
+	rules := entities.DefaultRules()
+	params := services.DefaultShuffleAnalysisParams()
+
+	fs := flag.NewFlagSet("shuffle-test", flag.ContinueOnError)
+	fs.SetOutput(out)
+	RegisterRuleFlags(fs, &rules)
+	fs.IntVar(&params.Trials, "trials", params.Trials, "每种洗牌方式重复洗牌的次数")
+	fs.Uint64Var(&params.Seed, "seed", params.Seed, "随机种子(0为当前时间)")
+	if err := ParseRuleFlags(fs, &rules, args); err != nil {
+		return err
+	}
+
+	params.Cards = len(rules.NewShoe().Cards)
+	params.Stub = entities.CutCardReserve * rules.DeckCount
+	params.Imperfection = rules.ShuffleImperfection
+
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "🔀 洗牌残留相关性 (%d 张，余牌 %d 张，不完美程度 %.2f，%d 次)\n",
+		params.Cards, params.Stub, params.Imperfection, params.Trials)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintln(out, "   方式       秩相关   相邻保留   上升序列")
+	var expected *dtos.ShuffleAnalysisDTO
+	for _, model := range entities.ShuffleModels {
+		params.Model = model
+		analysis, err := services.AnalyzeShuffle(params)
+		if err != nil {
+			return err
+		}
+		writeShuffleAnalysis(out, analysis)
+		expected = analysis
+	}
+	fmt.Fprintf(out, "   理想值     %+.3f   %6.2f%%   %8.1f\n",
+		0.0, expected.ExpectedAdjacentPairs*100, expected.ExpectedRisingSequences)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	return nil
+}
+
+// writeShuffleAnalysis 输出单个洗牌方式的统计结果
+func writeShuffleAnalysis(out io.Writer, analysis *dtos.ShuffleAnalysisDTO) {
+	fmt.Fprintf(out, "   %-8s   %+.3f   %6.2f%%   %8.1f\n",
+		analysis.Model, analysis.RankCorrelation, analysis.AdjacentPairs*100, analysis.RisingSequences)
+}
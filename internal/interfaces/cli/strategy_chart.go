@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runStrategyChartCommand 策略表子命令：按规则生成基本策略表，默认按无限副牌计算
+func runStrategyChartCommand(args []string, out io.Writer) error {
+	rules := entities.DefaultRules()
+
+	fs := flag.NewFlagSet("strategy-chart", flag.ContinueOnError)
+	fs.SetOutput(out)
+	RegisterRuleFlags(fs, &rules)
+	finite := fs.Bool("finite", false, "按规则的副数计算(默认按无限副牌，结果与移除的牌无关)")
+	if err := ParseRuleFlags(fs, &rules, args); err != nil {
+		return err
+	}
+
+	var options []services.EVOption
+	if !*finite {
+		options = append(options, services.WithEVInfiniteDeck())
+	}
+	chart, err := services.GenerateStrategyChart(rules, options...)
+	if err != nil {
+		return err
+	}
+	writeStrategyChart(out, chart, *finite)
+	return nil
+}
+
+// writeStrategyChart 输出基本策略表
+func writeStrategyChart(out io.Writer, chart *dtos.StrategyChartDTO, finite bool) {
+	title := "无限副牌"
+	if finite {
+		title = "完整牌靴"
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "📋 基本策略表 (%s)\n", title)
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+
+	sections := []struct {
+		name string
+		rows []*dtos.StrategyChartRowDTO
+	}{
+		{"硬牌", chart.Hard},
+		{"软牌", chart.Soft},
+		{"对子", chart.Pairs},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(out, "   %s  ", padRight(section.name, 6))
+		for _, up := range chart.DealerUpCards {
+			fmt.Fprintf(out, " %s", padRight(up, 2))
+		}
+		fmt.Fprintln(out)
+		for _, row := range section.rows {
+			fmt.Fprintf(out, "   %s  ", padRight(row.Hand, 6))
+			for _, action := range row.Actions {
+				fmt.Fprintf(out, " %s", padRight(chartActionCode(action), 2))
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	fmt.Fprintln(out, "   H 要牌  S 停牌  D 加倍  P 分牌  R 投降")
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+}
+
+// chartActionCode 策略表中操作的缩写
+func chartActionCode(action entities.PlayerAction) string {
+	switch action {
+	case entities.ActionHit:
+		return "H"
+	case entities.ActionStand:
+		return "S"
+	case entities.ActionDoubleDown:
+		return "D"
+	case entities.ActionSplit:
+		return "P"
+	case entities.ActionSurrender:
+		return "R"
+	default:
+		return "?"
+	}
+}
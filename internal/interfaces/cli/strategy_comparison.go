@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/application/services"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// runStrategyComparisonCommand 策略比较子命令：完整牌靴首手的组成相关最优策略与总点数基本策略的差异
+func runStrategyComparisonCommand(args []string, out io.Writer) error {
+	rules := entities.DefaultRules()
+
+	fs := flag.NewFlagSet("strategy-compare", flag.ContinueOnError)
+	fs.SetOutput(out)
+	RegisterRuleFlags(fs, &rules)
+	top := fs.Int("top", 20, "列出的决策不同的局面数(按对总期望值的贡献排序)")
+	if err := ParseRuleFlags(fs, &rules, args); err != nil {
+		return err
+	}
+
+	comparison, err := services.CompareStrategies(rules)
+	if err != nil {
+		return err
+	}
+	writeStrategyComparison(out, comparison, *top)
+	return nil
+}
+
+// writeStrategyComparison 输出策略比较结果
+func writeStrategyComparison(out io.Writer, comparison *dtos.StrategyComparisonDTO, top int) {
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintln(out, "🧮 组成相关策略 vs 基本策略 (完整牌靴首手)")
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+	fmt.Fprintf(out, "   比较局面: %d，决策不同: %d\n", comparison.Situations, len(comparison.Differences))
+	fmt.Fprintf(out, "   决策不同的频率: %.3f%% 的手牌\n", comparison.DifferenceRate*100)
+	fmt.Fprintf(out, "   每手期望值提升: %+.4f%%\n", comparison.EVGain*100)
+	if len(comparison.Differences) > 0 {
+		fmt.Fprintln(out, "   手牌    庄家  基本策略  组成策略   频率      提升")
+	}
+	for i, difference := range comparison.Differences {
+		if i >= top {
+			break
+		}
+		fmt.Fprintf(out, "   %s %s  %s  %s  %7.4f%%  %+.3f%%\n",
+			padRight(strings.Join(difference.PlayerCards, "-"), 6), padRight(difference.DealerUpCard, 4),
+			padRight(getPlayerActionName(difference.BasicAction), 8), padRight(getPlayerActionName(difference.CompositionAction), 8),
+			difference.Probability*100, difference.Gain*100)
+	}
+	fmt.Fprintln(out, strings.Repeat("─", 40))
+}
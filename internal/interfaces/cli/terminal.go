@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+)
+
+// terminal 终端输入封装，支持整行读取与单键读取
+type terminal struct {
+	reader *bufio.Reader
+}
+
+// newTerminal 创建终端输入封装
+func newTerminal() *terminal {
+	return &terminal{
+		reader: bufio.NewReader(os.Stdin),
+	}
+}
+
+// readLine 读取一行输入
+func (t *terminal) readLine() string {
+	line, _ := t.reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// readKey 读取单个按键，终端不支持时退化为整行读取
+func (t *terminal) readKey() string {
+	if err := setCbreak(true); err != nil {
+		return t.readLine()
+	}
+	defer func() { _ = setCbreak(false) }()
+
+	for {
+		r, _, err := t.reader.ReadRune()
+		if err != nil {
+			return ""
+		}
+		if unicode.IsSpace(r) {
+			continue
+		}
+		return string(unicode.ToLower(r))
+	}
+}
+
+// setCbreak 切换终端的无缓冲无回显模式
+func setCbreak(enabled bool) error {
+	args := []string{"icanon", "echo"}
+	if enabled {
+		args = []string{"-icanon", "-echo", "min", "1"}
+	}
+
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
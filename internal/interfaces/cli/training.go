@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// playTrainingGame 训练模式：正常游戏，每次行动后与基本策略比较
+func (h *GameHandler) playTrainingGame() error {
+	h.gameService.SetTrainingMode(true)
+	defer func() {
+		h.gameService.SetTrainingMode(false)
+		h.display.ShowTrainingStats(h.gameService.GetTrainingStats())
+	}()
+
+	return h.playGame()
+}
+
+// runStrategyDrill 策略专项练习，优先出错误率高的手牌类别
+func (h *GameHandler) runStrategyDrill() {
+	for {
+		drill := h.gameService.NextStrategyDrill()
+		h.display.ShowStrategyDrill(drill)
+
+		for {
+			input := h.display.ReadAction(
+				WithDoubleDown(drill.CanDouble),
+				WithSplit(drill.CanSplit),
+				WithSurrender(drill.CanSurrender),
+			)
+			action := ParsePlayerInput(input)
+
+			if action == entities.ActionQuit {
+				h.display.ShowTrainingStats(h.gameService.GetTrainingStats())
+				return
+			}
+			if action == entities.ActionInvalid {
+				h.display.ShowError("无效的输入，请重试")
+				continue
+			}
+
+			feedback, err := h.gameService.AnswerStrategyDrill(action)
+			if err != nil {
+				h.display.ShowError(fmt.Sprintf("操作失败: %v", err))
+				continue
+			}
+			h.display.ShowDecisionFeedback(feedback)
+			break
+		}
+	}
+}
@@ -0,0 +1,712 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// 全屏界面布局尺寸
+const (
+	tuiTableWidth   = 46 // 牌桌区域宽度
+	tuiPanelWidth   = 31 // 概率面板宽度
+	tuiFullWidth    = tuiTableWidth + tuiPanelWidth + 1
+	tuiMessageLines = 4 // 消息区域行数
+)
+
+// TUIRenderer 全屏终端界面渲染器
+type TUIRenderer struct {
+	out  io.Writer
+	term *terminal
+
+	atTable bool // 是否处于牌桌画面（否则为菜单画面）
+	round   int
+	chips   int
+	bet     int
+
+	dealerHand  *dtos.HandDTO
+	playerHand  *dtos.HandDTO
+	playerHands []*dtos.PlayerHandDTO // 多手牌时的所有手牌
+	activeHand  int
+	dealerCards cardVisibility
+	shownDealer int // 已发出动画的庄家牌数
+	shownPlayer int // 已发出动画的玩家牌数
+
+	panelTitle string
+	panel      []string
+	messages   []string
+
+	countingTitle []string // 算牌练习标题行
+
+	dealDelay time.Duration
+}
+
+// NewTUIRenderer 创建全屏终端界面渲染器
+func NewTUIRenderer() *TUIRenderer {
+	return &TUIRenderer{
+		out:       os.Stdout,
+		term:      newTerminal(),
+		dealDelay: 250 * time.Millisecond,
+	}
+}
+
+// ShowWelcome 显示欢迎画面
+func (t *TUIRenderer) ShowWelcome() {
+	t.messages = nil
+}
+
+// ShowMenu 显示主菜单
+func (t *TUIRenderer) ShowMenu() {
+	if t.atTable {
+		t.atTable = false
+		t.messages = nil
+	}
+	lines := []string{
+		"",
+		ansiBold + "♠ ♥  二十一点  ♣ ♦" + ansiReset,
+		"",
+		MenuOptionStart + ". 开始游戏",
+		MenuOptionRules + ". 游戏规则",
+		MenuOptionExit + ". 退出游戏",
+		MenuOptionTraining + ". 训练模式",
+		MenuOptionDrill + ". 策略专项练习",
+		MenuOptionCounting + ". 算牌练习",
+		"",
+	}
+	t.renderSplash(lines)
+}
+
+// ShowRules 显示游戏规则
+func (t *TUIRenderer) ShowRules(rules entities.Rules) {
+	var b strings.Builder
+	b.WriteString(ansiClear)
+	for _, line := range rulesText(rules) {
+		b.WriteString(stripEmoji(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n按任意键返回...")
+	t.flush(b.String())
+	t.term.readKey()
+}
+
+// ShowGoodbye 显示再见信息
+func (t *TUIRenderer) ShowGoodbye() {
+	t.flush(ansiClear + ansiShowCursor + "感谢游戏！再见！\n")
+}
+
+// ShowError 显示错误信息
+func (t *TUIRenderer) ShowError(message string) {
+	t.addMessage(ansiRed + "✗ " + message + ansiReset)
+}
+
+// ShowRoundStart 显示回合开始，重置牌桌
+func (t *TUIRenderer) ShowRoundStart(round, chips int) {
+	t.atTable = true
+	t.round = round
+	t.chips = chips
+	t.bet = 0
+	t.dealerHand = nil
+	t.playerHand = nil
+	t.playerHands = nil
+	t.activeHand = 0
+	t.shownDealer = 0
+	t.shownPlayer = 0
+	t.dealerCards = holeCardHidden
+	t.panelTitle = ""
+	t.panel = nil
+	t.messages = nil
+	t.addMessage(fmt.Sprintf("第 %d 轮开始", round))
+}
+
+// ShowBettingSection 显示下注区域
+func (t *TUIRenderer) ShowBettingSection(chips int) {
+	t.chips = chips
+	t.panelTitle = "下注"
+	t.panel = nil
+}
+
+// ShowBetOptions 显示下注选项
+func (t *TUIRenderer) ShowBetOptions(options *dtos.BetOptionsDTO) {
+	t.panel = append(t.panel, "限额: "+formatTableLimits(options), fmt.Sprintf("筹码面额: %d", options.ChipDenomination))
+	if len(options.Amounts) > 0 {
+		t.panel = append(t.panel, "常用: "+joinInts(options.Amounts, " "))
+	}
+	if options.LastBet > 0 {
+		t.panel = append(t.panel, fmt.Sprintf("上次: %d  [R]重复 [2X]翻倍", options.LastBet))
+	}
+	t.panel = append(t.panel, "[K]凯利建议  [C]牌靴组成  [Q]退出", "")
+}
+
+// ShowKellyBettingRecommendation 显示凯利公式下注建议
+func (t *TUIRenderer) ShowKellyBettingRecommendation(kelly *dtos.KellyRecommendationDTO) {
+	if kelly == nil {
+		return
+	}
+	t.panel = append(t.panel,
+		fmt.Sprintf("建议下注: %d (%.1f%%)", kelly.RecommendedBetAmount, kelly.RecommendedBetFraction*100),
+		"风险状况: "+colorRisk(kelly.RiskLevel),
+	)
+}
+
+// ShowSideBetOptions 显示边注期望值
+func (t *TUIRenderer) ShowSideBetOptions(evs []*dtos.SideBetEVDTO) {
+	t.panel = append(t.panel, "边注期望值:")
+	for _, ev := range evs {
+		t.panel = append(t.panel, fmt.Sprintf("  %s %+.2f%%", sideBetNames[ev.Type], ev.EV*100))
+	}
+	t.panel = append(t.panel, "")
+}
+
+// ShowSideBetResults 显示边注结算结果
+func (t *TUIRenderer) ShowSideBetResults(results []*dtos.SideBetResultDTO) {
+	for _, result := range results {
+		message := sideBetNames[result.Type] + ": " + formatSideBetResult(result)
+		if result.Payout > 0 {
+			message = ansiGreen + message + ansiReset
+		}
+		t.addMessage(message)
+	}
+}
+
+// ShowShoeComposition 在侧边面板显示牌靴组成与移除效应
+func (t *TUIRenderer) ShowShoeComposition(composition *dtos.ShoeCompositionDTO) {
+	t.panelTitle = "牌靴组成"
+	t.panel = []string{
+		fmt.Sprintf("余牌: %d/%d (%.1f副)", composition.CardsRemaining, composition.ShoeSize, composition.DecksRemaining),
+		fmt.Sprintf("渗透率: %.1f%%", composition.Penetration*100),
+		fmt.Sprintf("期望值: %+.3f%%", composition.PlayerEdge*100),
+		"点数 余牌  占比/完整",
+	}
+	for _, rank := range composition.Ranks {
+		t.panel = append(t.panel, fmt.Sprintf("%-3s %4d %5.1f/%.1f%%", rank.Rank, rank.Remaining, rank.Percent*100, rank.FreshPercent*100))
+	}
+	t.panel = append(t.panel, "移除效应:")
+	for _, effect := range composition.EffectsOfRemoval {
+		t.panel = append(t.panel, fmt.Sprintf("  %-3s %+.4f%%", effect.Rank, effect.Effect*100))
+	}
+}
+
+// ShowFairCommitment 显示为下一个牌靴公布的承诺，玩家随后设置客户端种子
+func (t *TUIRenderer) ShowFairCommitment(fair *dtos.FairShoeDTO) {
+	t.addMessage(fmt.Sprintf("牌靴 #%d 承诺: %s…", fair.Number, fair.Commitment[:16]))
+}
+
+// ShowFairReveal 显示牌靴结束后公开的服务器种子
+func (t *TUIRenderer) ShowFairReveal(fair *dtos.FairShoeDTO) {
+	t.addMessage(fmt.Sprintf("牌靴 #%d 服务器种子: %s…", fair.Number, fair.ServerSeed[:16]))
+}
+
+// ShowBetSuccess 显示下注成功
+func (t *TUIRenderer) ShowBetSuccess(amount int) {
+	t.bet = amount
+	t.chips -= amount
+	t.addMessage(fmt.Sprintf("下注 %d 筹码", amount))
+}
+
+// ShowPlayerTurnStart 显示玩家回合开始
+func (t *TUIRenderer) ShowPlayerTurnStart() {
+	t.panelTitle = "概率分析"
+	t.panel = nil
+}
+
+// ShowDealerTurnStart 显示庄家回合开始
+func (t *TUIRenderer) ShowDealerTurnStart() {
+	t.addMessage("庄家回合")
+	t.render("")
+	time.Sleep(2 * t.dealDelay)
+}
+
+// ShowGameState 显示游戏状态，新发出的牌逐张动画显示
+func (t *TUIRenderer) ShowGameState(gameState *dtos.GameStateDTO, hideHoleCard bool) {
+	t.round = gameState.RoundNumber
+	t.chips = gameState.PlayerChips
+	t.bet = gameState.PlayerBet
+	t.dealerHand = gameState.DealerHand
+	t.playerHand = gameState.PlayerHand
+	t.playerHands = gameState.PlayerHands
+	if gameState.ActiveHand != t.activeHand {
+		// 切换到下一手牌时重新播放发牌动画
+		t.activeHand = gameState.ActiveHand
+		t.shownPlayer = 0
+	}
+
+	// 翻开庄家底牌
+	visibility := dealerVisibility(hideHoleCard, gameState.DealerFaceDown)
+	if t.dealerCards != cardsVisible && visibility == cardsVisible {
+		t.dealerCards = cardsVisible
+		t.render("")
+		time.Sleep(t.dealDelay)
+	}
+	t.dealerCards = visibility
+
+	// 按发牌顺序（玩家、庄家交替）逐张显示
+	for t.shownPlayer < len(t.playerHand.Cards) || t.shownDealer < len(t.dealerHand.Cards) {
+		if t.shownPlayer <= t.shownDealer && t.shownPlayer < len(t.playerHand.Cards) ||
+			t.shownDealer >= len(t.dealerHand.Cards) {
+			t.shownPlayer++
+		} else {
+			t.shownDealer++
+		}
+		t.render("")
+		time.Sleep(t.dealDelay)
+	}
+
+	t.render("")
+}
+
+// ShowProbabilities 显示获胜概率
+func (t *TUIRenderer) ShowProbabilities(probabilities *dtos.ProbabilityResultDTO) {
+	if probabilities == nil {
+		return
+	}
+
+	t.panelTitle = "概率分析"
+	t.panel = []string{
+		fmt.Sprintf("玩家获胜   %s", colorPercent(probabilities.PlayerWinProbability, ansiGreen)),
+		fmt.Sprintf("庄家获胜   %s", colorPercent(probabilities.DealerWinProbability, ansiRed)),
+		fmt.Sprintf("平局       %s", colorPercent(probabilities.PushProbability, ansiYellow)),
+		"",
+		fmt.Sprintf("玩家爆牌   %5.1f%%", probabilities.PlayerBustProbability*100),
+		fmt.Sprintf("庄家爆牌   %5.1f%%", probabilities.DealerBustProbability*100),
+		fmt.Sprintf("玩家21点   %5.1f%%", probabilities.Player21Probability*100),
+	}
+
+	if advice := probabilities.SwitchAdvice; advice != nil {
+		t.panel = append(t.panel, "",
+			fmt.Sprintf("保持期望   %+.3f", advice.KeepEV),
+			fmt.Sprintf("换牌期望   %+.3f", advice.SwitchEV))
+		if advice.ShouldSwitch {
+			t.panel[len(t.panel)-1] = ansiGreen + t.panel[len(t.panel)-1] + " ★" + ansiReset
+		} else {
+			t.panel[len(t.panel)-2] = ansiGreen + t.panel[len(t.panel)-2] + " ★" + ansiReset
+		}
+	}
+
+	if reference := probabilities.InfiniteDeck; reference != nil {
+		t.panel = append(t.panel, "",
+			fmt.Sprintf("无限副牌   %+.3f %s", reference.ExpectedValue, getActionNameByKey(reference.RecommendedAction)),
+			fmt.Sprintf("组成影响   %+.3f", reference.CompositionEffect))
+	}
+
+	analysis := probabilities.ActionAnalysis
+	if analysis == nil {
+		return
+	}
+
+	t.panel = append(t.panel, "", "操作胜率:")
+	actions := []struct {
+		key     string
+		name    string
+		winRate float64
+		canUse  bool
+	}{
+		{DisplayActionStand, "停牌", analysis.StandWinRate, analysis.CanStand},
+		{DisplayActionHit, "要牌", analysis.HitWinRate, analysis.CanHit},
+		{DisplayActionDouble, "加倍", analysis.DoubleWinRate, analysis.CanDouble},
+		{"split", "分牌", analysis.SplitWinRate, analysis.CanSplit},
+	}
+	for _, action := range actions {
+		if !action.canUse {
+			continue
+		}
+		line := fmt.Sprintf("  %s     %5.1f%%", action.name, action.winRate*100)
+		if analysis.RecommendedAction == action.key {
+			line = ansiGreen + line + " ★" + ansiReset
+		}
+		t.panel = append(t.panel, line)
+	}
+
+	if name, ok := strategySourceNames[analysis.StrategySource]; ok {
+		t.panel = append(t.panel, "  ★ "+name)
+	}
+
+	if kelly := analysis.KellyRecommendation; kelly != nil && kelly.ShouldDouble {
+		t.panel = append(t.panel, "", fmt.Sprintf("凯利: 推荐加倍 ROI %.1f%%", kelly.DoubleExpectedROI*100))
+	}
+}
+
+// ShowBlackjack 显示21点
+func (t *TUIRenderer) ShowBlackjack() {
+	t.addMessage(ansiGreen + "21点!" + ansiReset)
+	t.render("")
+}
+
+// ShowDealerPeekBlackjack 显示庄家偷看底牌发现Blackjack
+func (t *TUIRenderer) ShowDealerPeekBlackjack() {
+	t.addMessage(ansiRed + "庄家检查底牌: Blackjack!" + ansiReset)
+}
+
+// ShowPlayerBust 显示玩家爆牌
+func (t *TUIRenderer) ShowPlayerBust() {
+	t.addMessage(ansiRed + "爆牌了!" + ansiReset)
+	t.render("")
+}
+
+// ShowActionResult 显示行动结果
+func (t *TUIRenderer) ShowActionResult(result *dtos.ActionResultDTO) {
+	if !result.Success {
+		t.ShowError(result.Message)
+		return
+	}
+
+	switch result.Action {
+	case entities.ActionHit:
+		if result.Card != nil {
+			t.addMessage("获得一张牌: " + result.Card.Rank + result.Card.Suit)
+		}
+	case entities.ActionStand:
+		t.addMessage("停牌")
+	case entities.ActionDoubleDown:
+		message := "加倍下注!"
+		if result.Card != nil {
+			message += " 获得一张牌: " + result.Card.Rank + result.Card.Suit
+		}
+		t.addMessage(message)
+	case entities.ActionSurrender:
+		t.addMessage("投降")
+	case entities.ActionSplit:
+		t.addMessage("分牌")
+	case entities.ActionSwitch:
+		t.addMessage("交换两手牌的第二张牌")
+	}
+}
+
+// ShowGameResult 显示游戏结果
+func (t *TUIRenderer) ShowGameResult(result *dtos.GameResultDTO) {
+	t.chips = result.PlayerChips
+	t.bet = 0
+	t.panelTitle = "本轮结果"
+	t.panel = []string{
+		ansiBold + GetResultMessage(result.Type) + ansiReset,
+		"",
+		fmt.Sprintf("本轮下注: %d", result.BetAmount),
+	}
+	if result.IsDoubled {
+		t.panel = append(t.panel, "(已加倍)")
+	}
+	for i, hand := range result.Hands {
+		t.panel = append(t.panel, fmt.Sprintf("第%d手: %s", i+1, GetResultMessage(hand.Type)))
+	}
+	t.panel = append(t.panel, fmt.Sprintf("当前筹码: %d", result.PlayerChips))
+	t.render("")
+}
+
+// ShowGameOver 显示游戏结束
+func (t *TUIRenderer) ShowGameOver() {
+	t.addMessage(ansiRed + "筹码用完了！游戏结束！" + ansiReset)
+	t.render("")
+}
+
+// ShowDecisionFeedback 显示基本策略评分
+func (t *TUIRenderer) ShowDecisionFeedback(feedback *dtos.DecisionFeedbackDTO) {
+	if feedback == nil {
+		return
+	}
+
+	if feedback.IsCorrect {
+		t.addMessage(ansiGreen + fmt.Sprintf("正确! %s符合基本策略 (EV %+.3f)",
+			getPlayerActionName(feedback.ChosenAction), feedback.ChosenEV) + ansiReset)
+		return
+	}
+
+	t.addMessage(ansiRed + fmt.Sprintf("错误: 应%s (EV %+.3f)，你选择%s (EV %+.3f)，损失 %.1f%% 注码",
+		getPlayerActionName(feedback.BestAction), feedback.BestEV,
+		getPlayerActionName(feedback.ChosenAction), feedback.ChosenEV, feedback.EVCost*100) + ansiReset)
+}
+
+// ShowTrainingStats 显示训练统计
+func (t *TUIRenderer) ShowTrainingStats(stats *dtos.TrainingStatsDTO) {
+	if stats == nil {
+		return
+	}
+
+	t.panelTitle = "训练统计"
+	t.panel = nil
+	for _, category := range stats.Categories {
+		line := getCategoryName(category.Category) + ": 暂无记录"
+		if category.Decisions > 0 {
+			line = fmt.Sprintf("%s: %d/%d (%.0f%%)", getCategoryName(category.Category),
+				category.Correct, category.Decisions, category.Accuracy*100)
+		}
+		t.panel = append(t.panel, line)
+	}
+	if stats.TotalDecisions > 0 {
+		t.panel = append(t.panel, "", fmt.Sprintf("总正确率: %.1f%%", stats.Accuracy*100),
+			fmt.Sprintf("累计损失: %.2f 注码", stats.TotalEVCost))
+	}
+	t.panel = append(t.panel, "最需加强: "+getCategoryName(stats.WeakestCategory))
+
+	if t.atTable {
+		t.render("")
+	}
+}
+
+// ShowStrategyDrill 显示策略练习题目
+func (t *TUIRenderer) ShowStrategyDrill(drill *dtos.StrategyDrillDTO) {
+	t.atTable = true
+	t.dealerHand = &dtos.HandDTO{Cards: []*dtos.CardDTO{drill.DealerUpCard}, Value: drill.DealerUpCard.Value}
+	t.playerHand = drill.PlayerHand
+	t.shownDealer = len(t.dealerHand.Cards)
+	t.shownPlayer = len(t.playerHand.Cards)
+	t.dealerCards = cardsVisible
+	t.panelTitle = "专项练习 [" + getCategoryName(drill.Category) + "]"
+	t.render("")
+}
+
+// ShowCountingDrillStart 显示算牌练习开始
+func (t *TUIRenderer) ShowCountingDrillStart(drill *dtos.CountingDrillDTO) {
+	t.atTable = false
+	t.messages = nil
+	t.countingTitle = []string{
+		"",
+		ansiBold + fmt.Sprintf("算牌练习 · %s · %d副牌", drill.System.Name, drill.DeckCount) + ansiReset,
+		fmt.Sprintf("计数值(A,2-10): %v", drill.System.Tags),
+		fmt.Sprintf("%dms/张 · 每%d张提问 · 共%d张", drill.DealIntervalMs, drill.CardsPerQuestion, drill.CardsToDeal),
+		"",
+	}
+	t.renderSplash(append(t.countingTitle, cardArt(nil, true)...))
+}
+
+// ShowCountingCard 显示算牌练习发出的牌
+func (t *TUIRenderer) ShowCountingCard(card *dtos.CountingCardDTO) {
+	lines := append([]string{}, t.countingTitle...)
+	lines = append(lines, cardArt(card.Card, false)...)
+	lines = append(lines, "", fmt.Sprintf("已发 %d 张 · 剩余 %d 张", card.CardsDealt, card.CardsRemaining), "")
+	t.renderSplash(lines)
+}
+
+// ShowCountingAnswer 显示算牌回答结果
+func (t *TUIRenderer) ShowCountingAnswer(answer *dtos.CountingAnswerDTO) {
+	if answer.RunningCorrect {
+		t.addMessage(ansiGreen + fmt.Sprintf("✓ 流水数正确: %d", answer.RunningCount) + ansiReset)
+	} else {
+		t.addMessage(ansiRed + fmt.Sprintf("✗ 流水数应为 %d，你回答了 %d", answer.RunningCount, answer.AnsweredRunning) + ansiReset)
+	}
+	if answer.TrueCountAsked {
+		if answer.TrueCorrect {
+			t.addMessage(ansiGreen + fmt.Sprintf("✓ 真数正确: %.1f", answer.TrueCount) + ansiReset)
+		} else {
+			t.addMessage(ansiRed + fmt.Sprintf("✗ 真数应为 %.1f，你回答了 %.1f", answer.TrueCount, answer.AnsweredTrue) + ansiReset)
+		}
+	}
+	t.addMessage(fmt.Sprintf("用时 %.1f 秒", answer.ResponseSeconds))
+	t.renderSplash(t.countingTitle)
+}
+
+// ShowCountingHistory 显示算牌练习历史
+func (t *TUIRenderer) ShowCountingHistory(history []*dtos.CountingSessionDTO) {
+	lines := []string{"", ansiBold + "算牌练习记录" + ansiReset, ""}
+	if len(history) == 0 {
+		lines = append(lines, "暂无记录")
+	}
+	for _, session := range history {
+		lines = append(lines, fmt.Sprintf("%s %-9s %d副  %3.0f张/分  %d/%d (%.0f%%)  %.1fs",
+			session.StartedAt.Format("15:04"), session.System, session.DeckCount, session.CardsPerMinute,
+			session.CorrectAnswers, session.Questions, session.Accuracy*100, session.AverageResponseSeconds))
+	}
+	t.renderSplash(append(lines, ""))
+}
+
+// ReadInput 显示提示并读取一行输入
+func (t *TUIRenderer) ReadInput(prompt string) string {
+	if !t.atTable {
+		t.flush(ansiShowCursor + prompt)
+	} else {
+		t.render(prompt)
+		t.flush(ansiShowCursor)
+	}
+	return t.term.readLine()
+}
+
+// ReadAction 显示按键提示并读取单个按键
+func (t *TUIRenderer) ReadAction(options ...PlayerPromptOption) string {
+	opts := PlayerPromptOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.pontoon {
+		var keys []string
+		for _, key := range pontoonPromptKeys(opts) {
+			keys = append(keys, fmt.Sprintf("[%s]%s", strings.ToUpper(key[0]), key[1]))
+		}
+		t.render(ansiBold + strings.Join(append(keys, "[Q]退出"), "  ") + ansiReset)
+		return t.term.readKey()
+	}
+
+	keys := "[S]停牌"
+	if !opts.noHit {
+		keys = "[H]要牌  " + keys
+	}
+	if opts.doubleDown {
+		keys += "  [D]加倍"
+		switch {
+		case opts.freeDouble:
+			keys += "(免费)"
+		case opts.doubleForLess:
+			keys += "(可少加)"
+		}
+	}
+	if opts.split {
+		keys += "  [P]分牌"
+		if opts.freeSplit {
+			keys += "(免费)"
+		}
+	}
+	if opts.surrender {
+		keys += "  [U]投降"
+	}
+	if opts.switchCards {
+		keys += "  [W]换牌"
+	}
+	keys += "  [Q]退出"
+
+	t.render(ansiBold + keys + ansiReset)
+	return t.term.readKey()
+}
+
+// addMessage 追加一条消息，只保留最近几条
+func (t *TUIRenderer) addMessage(message string) {
+	t.messages = append(t.messages, message)
+	if len(t.messages) > tuiMessageLines {
+		t.messages = t.messages[len(t.messages)-tuiMessageLines:]
+	}
+}
+
+// renderSplash 渲染居中的标题画面
+func (t *TUIRenderer) renderSplash(lines []string) {
+	var b strings.Builder
+	b.WriteString(ansiClear + ansiHideCursor)
+	b.WriteString(horizontalRule("╔", "═", "╗", tuiFullWidth) + "\n")
+	for _, line := range lines {
+		pad := (tuiFullWidth - displayWidth(line)) / 2
+		b.WriteString(boxLine("║", strings.Repeat(" ", pad)+line, "║", tuiFullWidth) + "\n")
+	}
+	b.WriteString(horizontalRule("╚", "═", "╝", tuiFullWidth) + "\n")
+	for _, message := range t.messages {
+		b.WriteString(message + "\n")
+	}
+	t.messages = nil
+	t.flush(b.String())
+}
+
+// render 重绘整个牌桌画面
+func (t *TUIRenderer) render(footer string) {
+	var b strings.Builder
+	b.WriteString(ansiClear + ansiHideCursor)
+
+	header := fmt.Sprintf(" 二十一点 · 第 %d 轮", t.round)
+	status := fmt.Sprintf("筹码: %d   下注: %d ", t.chips, t.bet)
+	gap := tuiFullWidth - displayWidth(header) - displayWidth(status)
+	b.WriteString(horizontalRule("╔", "═", "╗", tuiFullWidth) + "\n")
+	b.WriteString("║" + header + strings.Repeat(" ", max(gap, 1)) + status + "║\n")
+	b.WriteString("╠" + strings.Repeat("═", tuiTableWidth) + "╦" + strings.Repeat("═", tuiPanelWidth) + "╣\n")
+
+	table := t.tableLines()
+	panel := append([]string{ansiBold + " " + t.panelTitle + ansiReset}, t.panelLines()...)
+	rows := max(len(table), len(panel))
+	for i := range rows {
+		left, right := "", ""
+		if i < len(table) {
+			left = table[i]
+		}
+		if i < len(panel) {
+			right = panel[i]
+		}
+		b.WriteString(boxLine("║", left, "║", tuiTableWidth))
+		b.WriteString(boxLine("", right, "║", tuiPanelWidth) + "\n")
+	}
+
+	b.WriteString("╠" + strings.Repeat("═", tuiTableWidth) + "╩" + strings.Repeat("═", tuiPanelWidth) + "╣\n")
+	for i := range tuiMessageLines {
+		message := ""
+		if i < len(t.messages) {
+			message = " " + t.messages[i]
+		}
+		b.WriteString(boxLine("║", message, "║", tuiFullWidth) + "\n")
+	}
+	b.WriteString(horizontalRule("╚", "═", "╝", tuiFullWidth) + "\n")
+	b.WriteString(" " + footer)
+
+	t.flush(b.String())
+}
+
+// tableLines 生成牌桌区域（庄家与玩家手牌）
+func (t *TUIRenderer) tableLines() []string {
+	artWidth := tuiTableWidth - 2
+	lines := []string{" " + t.handTitle("庄家", t.dealerHand, t.shownDealer, t.dealerCards != cardsVisible)}
+	for _, line := range handArt(t.dealerHand, t.shownDealer, t.dealerCards, artWidth) {
+		lines = append(lines, " "+line)
+	}
+
+	name := "玩家"
+	if len(t.playerHands) > 0 {
+		name = fmt.Sprintf("玩家·第%d手", t.activeHand+1)
+	}
+	lines = append(lines, "", " "+t.handTitle(name, t.playerHand, t.shownPlayer, false))
+	for _, line := range handArt(t.playerHand, t.shownPlayer, cardsVisible, artWidth) {
+		lines = append(lines, " "+line)
+	}
+
+	// 其他手牌以文字形式列出
+	for i, hand := range t.playerHands {
+		if i == t.activeHand {
+			continue
+		}
+		cards := make([]string, 0, len(hand.Hand.Cards))
+		for _, card := range hand.Hand.Cards {
+			cards = append(cards, card.Rank+card.Suit)
+		}
+		lines = append(lines, fmt.Sprintf(" 第%d手 %s (%d) 下注 %d", i+1, strings.Join(cards, " "), hand.Hand.Value, hand.Bet))
+	}
+
+	return lines
+}
+
+// handTitle 生成手牌标题
+func (t *TUIRenderer) handTitle(name string, hand *dtos.HandDTO, shown int, hidden bool) string {
+	switch {
+	case hand == nil || shown == 0:
+		return name
+	case hidden || shown < len(hand.Cards):
+		return name + " (点数: ?)"
+	default:
+		return fmt.Sprintf("%s (点数: %d)", name, hand.Value)
+	}
+}
+
+// panelLines 生成右侧面板内容
+func (t *TUIRenderer) panelLines() []string {
+	lines := make([]string, 0, len(t.panel))
+	for _, line := range t.panel {
+		lines = append(lines, " "+line)
+	}
+	return lines
+}
+
+// flush 输出到终端
+func (t *TUIRenderer) flush(s string) {
+	_, _ = io.WriteString(t.out, s)
+}
+
+// colorPercent 带颜色的百分比
+func colorPercent(value float64, color string) string {
+	return fmt.Sprintf("%s%5.1f%%%s", color, value*100, ansiReset)
+}
+
+// colorRisk 带颜色的风险等级
+func colorRisk(level string) string {
+	switch level {
+	case "Low":
+		return ansiGreen + level + ansiReset
+	case "Medium":
+		return ansiYellow + level + ansiReset
+	default:
+		return ansiRed + level + ansiReset
+	}
+}
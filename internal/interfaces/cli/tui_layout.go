@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/luffy050596/go-blackjack/internal/application/dtos"
+	"github.com/luffy050596/go-blackjack/internal/domain/entities"
+)
+
+// ANSI 控制序列
+const (
+	ansiReset      = "\033[0m"
+	ansiRed        = "\033[31m"
+	ansiGreen      = "\033[32m"
+	ansiYellow     = "\033[33m"
+	ansiBold       = "\033[1m"
+	ansiClear      = "\033[2J\033[H"
+	ansiHideCursor = "\033[?25l"
+	ansiShowCursor = "\033[?25h"
+)
+
+// 牌面图案尺寸
+const (
+	cardArtWidth  = 7
+	cardArtHeight = 5
+	cardArtGap    = 1
+	cardArtPeek   = 4 // 重叠显示时每张牌露出的宽度
+)
+
+// cardArt 生成单张牌的图案
+func cardArt(card *dtos.CardDTO, hidden bool) []string {
+	if hidden {
+		return []string{
+			"┌─────┐",
+			"│░░░░░│",
+			"│░░░░░│",
+			"│░░░░░│",
+			"└─────┘",
+		}
+	}
+
+	rank := card.Rank
+	color := ""
+	if card.Suit == entities.Hearts.String() || card.Suit == entities.Diamonds.String() {
+		color = ansiRed
+	}
+
+	paint := func(s string) string {
+		if color == "" {
+			return s
+		}
+		return color + s + ansiReset
+	}
+
+	return []string{
+		"┌─────┐",
+		"│" + paint(padRight(rank, 5)) + "│",
+		"│  " + paint(card.Suit) + "  │",
+		"│" + paint(padLeft(rank, 5)) + "│",
+		"└─────┘",
+	}
+}
+
+// handArt 生成整手牌的图案，超出宽度时重叠显示
+func handArt(hand *dtos.HandDTO, shown int, visibility cardVisibility, maxWidth int) []string {
+	lines := make([]string, cardArtHeight)
+	if hand == nil || shown == 0 {
+		return lines
+	}
+
+	cards := hand.Cards[:min(shown, len(hand.Cards))]
+	step := cardArtWidth + cardArtGap
+	if len(cards)*step-cardArtGap > maxWidth {
+		step = cardArtPeek
+	}
+
+	for i, card := range cards {
+		art := cardArt(card, visibility.hides(i))
+		last := i == len(cards)-1
+		for row := range lines {
+			switch {
+			case last:
+				lines[row] += art[row]
+			case step < cardArtWidth:
+				lines[row] += truncateWidth(art[row], step)
+			default:
+				lines[row] += art[row] + strings.Repeat(" ", cardArtGap)
+			}
+		}
+	}
+
+	return lines
+}
+
+// displayWidth 计算字符串在终端中的显示宽度（忽略ANSI序列，宽字符计为2）
+func displayWidth(s string) int {
+	width := 0
+	inEscape := false
+
+	for _, r := range s {
+		switch {
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		case r == '\033':
+			inEscape = true
+		case isWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+
+	return width
+}
+
+// isWideRune 判断是否为全角字符
+func isWideRune(r rune) bool {
+	return r >= 0x1100 && (r <= 0x115f ||
+		(r >= 0x2e80 && r <= 0xa4cf) ||
+		(r >= 0xac00 && r <= 0xd7a3) ||
+		(r >= 0xf900 && r <= 0xfaff) ||
+		(r >= 0xfe30 && r <= 0xfe4f) ||
+		(r >= 0xff00 && r <= 0xff60) ||
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x1f300 && r <= 0x1faff))
+}
+
+// truncateWidth 按显示宽度截断字符串（保留ANSI序列）
+func truncateWidth(s string, width int) string {
+	var b strings.Builder
+	current := 0
+	inEscape := false
+
+	for _, r := range s {
+		switch {
+		case inEscape:
+			b.WriteRune(r)
+			if r == 'm' {
+				inEscape = false
+			}
+			continue
+		case r == '\033':
+			inEscape = true
+			b.WriteRune(r)
+			continue
+		}
+
+		w := 1
+		if isWideRune(r) {
+			w = 2
+		}
+		if current+w > width {
+			break
+		}
+		b.WriteRune(r)
+		current += w
+	}
+
+	if strings.Contains(b.String(), "\033[") {
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}
+
+// padRight 右侧补空格至指定显示宽度
+func padRight(s string, width int) string {
+	w := displayWidth(s)
+	if w >= width {
+		return truncateWidth(s, width)
+	}
+	return s + strings.Repeat(" ", width-w)
+}
+
+// padLeft 左侧补空格至指定显示宽度
+func padLeft(s string, width int) string {
+	w := displayWidth(s)
+	if w >= width {
+		return truncateWidth(s, width)
+	}
+	return strings.Repeat(" ", width-w) + s
+}
+
+// boxLine 生成带边框的一行
+func boxLine(left, content, right string, width int) string {
+	return left + padRight(content, width) + right
+}
+
+// horizontalRule 生成水平分隔线
+func horizontalRule(left, fill, right string, width int) string {
+	return left + strings.Repeat(fill, width) + right
+}
+
+// stripEmoji 去除宽度不稳定的表情符号，保证边框对齐
+func stripEmoji(s string) string {
+	var b strings.Builder
+	skipSpace := false
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		if isEmojiRune(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEmojiRune 判断是否为表情符号（保留扑克花色符号）
+func isEmojiRune(r rune) bool {
+	if r >= 0x2660 && r <= 0x2667 {
+		return false
+	}
+	return (r >= 0x1f300 && r <= 0x1faff) || (r >= 0x2600 && r <= 0x27bf) || r == 0x2b50 || r == 0xfe0f
+}
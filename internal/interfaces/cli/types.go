@@ -11,7 +11,46 @@ const (
 
 // 菜单选项常量
 const (
-	MenuOptionStart = "1"
-	MenuOptionRules = "2"
-	MenuOptionExit  = "3"
+	MenuOptionStart    = "1"
+	MenuOptionRules    = "2"
+	MenuOptionExit     = "3"
+	MenuOptionTraining = "4"
+	MenuOptionDrill    = "5"
+	MenuOptionCounting = "6"
 )
+
+// holeCardIndex 庄家底牌在手牌中的位置
+const holeCardIndex = 1
+
+// cardVisibility 庄家手牌的可见程度
+type cardVisibility int
+
+const (
+	cardsVisible     cardVisibility = iota // 全部明牌
+	holeCardHidden                         // 底牌背面朝上
+	dealtCardsHidden                       // 发出的两张牌都背面朝上（Pontoon）
+)
+
+// dealerVisibility 根据是否隐藏底牌及庄家是否两张暗牌确定可见程度
+func dealerVisibility(hideHoleCard, faceDown bool) cardVisibility {
+	switch {
+	case !hideHoleCard:
+		return cardsVisible
+	case faceDown:
+		return dealtCardsHidden
+	default:
+		return holeCardHidden
+	}
+}
+
+// hides 第i张牌是否背面朝上
+func (v cardVisibility) hides(i int) bool {
+	switch v {
+	case holeCardHidden:
+		return i == holeCardIndex
+	case dealtCardsHidden:
+		return i <= holeCardIndex
+	default:
+		return false
+	}
+}